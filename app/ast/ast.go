@@ -13,269 +13,402 @@ that can be found in LICENSE file in the code repository.
 package ast
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
+	"io"
 	"io/ioutil"
+	"log/slog"
+	"os"
 	stdruntime "runtime"
+	"strings"
 	"time"
+)
+
+// The parsers that source files can be parsed with
+const (
+	PARSER_TYPESCRIPT = "typescript" // the Typescript compiler running in QuickJS, the default
+	PARSER_GO         = "go"         // the native Go parser, which needs neither cgo nor Typescript
+)
+
+// The names of the parsers, the default first
+var PARSERS = []string{PARSER_TYPESCRIPT, PARSER_GO}
 
-	"github.com/quickjs-go/quickjs-go"
+// The `typescript.js` file of the Typescript compiler, when not given
+// in the options. The environment variable takes precedence over the
+// file in `node_modules` of the working folder.
+const (
+	ENV_TYPESCRIPT_FILE     = "REDEFINE_TYPESCRIPT"
+	DEFAULT_TYPESCRIPT_FILE = "node_modules/typescript/lib/typescript.js"
 )
 
+// The ECMAScript targets that source files can be parsed with, by
+// their names in `tsconfig.json`
+var SCRIPT_TARGETS = []string{
+	"es3", "es5", "es6", "es2015", "es2016", "es2017", "es2018", "es2019",
+	"es2020", "es2021", "es2022", "es2023", "es2024", "esnext", "latest",
+}
+
+// Names of targets accepted by `tsconfig.json` for the Typescript
+// name of the same target
+var scriptTargetAliases = map[string]string{
+	"es6": "es2015",
+}
+
+// Return the Typescript name of the script target, in lower case,
+// and whether it is a known target.
+func NormalizeScriptTarget(target string) (string, bool) {
+	target = strings.ToLower(target)
+	if alias, exists := scriptTargetAliases[target]; exists {
+		target = alias
+	}
+
+	for _, known := range SCRIPT_TARGETS {
+		if known == target {
+			return target, true
+		}
+	}
+
+	return target, false
+}
+
 /**
- * The value is assigned once the Typescript code is initialized
- * in QuickJS runtime. Any usage before initialization will throw
- * a `nil` error.
+ * Options that control how the source files are parsed.
  */
-var Syntax *SyntaxKind
+type ParseOptions struct {
+	Target      string // the ECMAScript target such as `ES2020`, defaults to the latest
+	TypeChecker bool   // resolve the props types using the Typescript type checker, only when imports are parsed
+	Parser      string // the parser to use, `typescript` or `go`, defaults to `typescript`
+
+	// the `typescript.js` file of the Typescript compiler used by the
+	// typescript parser, defaults to `$REDEFINE_TYPESCRIPT` or else
+	// the one in `node_modules` of the working folder
+	TypescriptFile string
+
+	// limits of the typescript parser, the defaults apply when zero
+	Timeout     time.Duration // the longest time to parse a single file, or run the type checker
+	MemoryLimit uint32        // the most memory the QuickJS runtime may use, in megabytes
+	StackLimit  uint32        // the largest stack the QuickJS runtime may use, in kilobytes
+
+	Logger *slog.Logger // the logger to report progress to, nothing is logged when `nil`
+}
+
+// Return the logger of the options, or one that discards all
+// records if there is none.
+func (options *ParseOptions) getLogger() *slog.Logger {
+	if options == nil || options.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return options.Logger
+}
+
+// Return the `typescript.js` file of the Typescript compiler to
+// load, from the options or else the environment.
+func (options *ParseOptions) getTypescriptFile() string {
+	if options != nil && options.TypescriptFile != "" {
+		return options.TypescriptFile
+	}
+
+	if file := os.Getenv(ENV_TYPESCRIPT_FILE); file != "" {
+		return file
+	}
+
+	return DEFAULT_TYPESCRIPT_FILE
+}
 
 /**
- * A `struct` to store and pass various QuickJS runtime objects
- * down the function chain.
+ * A parser that converts source code into the `SourceFile` AST.
+ * All parsers produce the same shape of AST, though the values of
+ * the syntax kinds differ between parsers. A parser is not safe for
+ * concurrent use, and must be used from the goroutine, and the OS
+ * thread, that created it.
  */
-type tsParser struct {
-	runtime          *quickjs.Runtime
-	context          *quickjs.Context
-	globals          *quickjs.Value
-	codeParser       *quickjs.Value
-	stringify        *quickjs.Value
-	circularReplacer *quickjs.Value
+type Parser interface {
+	// Parse the contents of a file, returning the AST along with
+	// any problems found. The extension of the file name decides
+	// whether the file is parsed as Typescript, JSX or Javascript.
+	// The returned source file is `nil` if it could not be parsed.
+	// Parsing stops early once the context is done.
+	ParseFile(ctx context.Context, fileName string, contents string) (*SourceFile, []Diagnostic)
+
+	// Return the syntax kinds of the nodes in the parsed ASTs
+	GetSyntaxKind() *SyntaxKind
+
+	// Free all resources held by the parser
+	Free()
+}
+
+/**
+ * Implemented by parsers that can run the Typescript type checker.
+ */
+type typeChecker interface {
+	checkTypes(ctx context.Context, rootFiles map[string]SourceFile, allFiles map[string]SourceFile, resolve ModuleResolver) error
+}
+
+// Create the parser selected in the options, which may be `nil`.
+//
+// The parser must be used, and freed, on the goroutine that created
+// it, and that goroutine must stay on its OS thread by calling
+// `runtime.LockOSThread` before creating the parser, as the Typescript
+// parser cannot move between threads.
+func NewParser(options *ParseOptions) (Parser, error) {
+	name := PARSER_TYPESCRIPT
+	if options != nil && options.Parser != "" {
+		name = options.Parser
+	}
+
+	switch name {
+	case PARSER_TYPESCRIPT:
+		return newTypescriptParser(options)
+
+	case PARSER_GO:
+		return newNativeParser(), nil
+	}
+
+	return nil, errors.New("unknown parser: " + name)
 }
 
 // returns an AST for the given file contents
 // this method does not accessess the file system
 // and is primarly meant to be used when testing
 func GetAstForFileContents(contents string) (*SourceFile, *SyntaxKind) {
+	sourceFile, syntaxKind, _ := ParseFileContents("index.tsx", contents, nil)
+	return sourceFile, syntaxKind
+}
+
+// Return an AST for the given file contents, parsed with the parser
+// selected in the options. The file name decides how the contents
+// are parsed, and the file system is not accessed.
+func ParseFileContents(fileName string, contents string, options *ParseOptions) (*SourceFile, *SyntaxKind, []Diagnostic) {
 	var sourceFile *SourceFile
-	quickJsWorker := func(parser *tsParser) {
-		sourceFile = parseSingleFileContents(contents, parser)
+	var diagnostics []Diagnostic
+
+	var syntaxKind *SyntaxKind
+
+	worker := func(parser Parser) {
+		sourceFile, diagnostics = parser.ParseFile(context.Background(), fileName, contents)
+		syntaxKind = parser.GetSyntaxKind()
 	}
 
 	// run the worker
-	runInQuickJS(quickJsWorker)
+	err := runWithParser(options, worker)
+	if err != nil {
+		return nil, nil, []Diagnostic{newErrorDiagnostic(fileName, err)}
+	}
 
 	// return obtained source file
-	return sourceFile, Syntax
+	return sourceFile, syntaxKind, diagnostics
 }
 
 //
 // Create a map of ASTs by parsing each file.
 //
+// @param ctx the context to stop parsing early with
+//
 // @param files an array of absolute file paths to process.
 //
-func BuildAstForFiles(files []string) (map[string]SourceFile, *SyntaxKind) {
-	astMap := make(map[string]SourceFile, len(files))
+// @param options the options to parse files with, may be `nil`
+//
+// Files that cannot be read or parsed are skipped, and the
+// problems found are returned as diagnostics.
+//
+func BuildAstForFiles(ctx context.Context, files []string, options *ParseOptions) (map[string]SourceFile, *SyntaxKind, []Diagnostic) {
+	var astMap map[string]SourceFile
+	var syntaxKind *SyntaxKind
+	var diagnostics []Diagnostic
 
 	// create simple worker to do our job
-
-	quickJsWorker := func(parser *tsParser) {
-		// do job
-		doWork(files, parser, astMap)
+	worker := func(parser Parser) {
+		astMap, diagnostics = ParseFiles(ctx, parser, files)
+		syntaxKind = parser.GetSyntaxKind()
 	}
 
 	// start noting the time
 	start := time.Now()
-	runInQuickJS(quickJsWorker)
+	err := runWithParser(options, worker)
+	if err != nil {
+		return make(map[string]SourceFile), nil, []Diagnostic{newErrorDiagnostic("", err)}
+	}
 
-	// get time spent
-	duration := time.Since(start)
-	fmt.Println("Total time in parsing files: " + duration.String())
+	options.getLogger().Debug("Parsed files", "files", len(astMap), "duration", time.Since(start))
 
-	return astMap, Syntax
+	return astMap, syntaxKind, diagnostics
 }
 
-func runInQuickJS(worker func(parser *tsParser)) {
-	// all processing for QJS happens in same thread
-	stdruntime.LockOSThread()
-
-	parser := tsParser{}
-	parser.init()
-	defer parser.free()
-
-	// run the worker
-	worker(&parser)
+// Create a map of ASTs by parsing each file with the given parser.
+// Files that cannot be read or parsed are skipped, and the problems
+// found are returned as diagnostics.
+func ParseFiles(ctx context.Context, parser Parser, files []string) (map[string]SourceFile, []Diagnostic) {
+	astMap := make(map[string]SourceFile, len(files))
+	diagnostics := doWork(ctx, files, parser, astMap)
 
-	// remove OS thread lock
-	stdruntime.UnlockOSThread()
+	return astMap, diagnostics
 }
 
-func doWork(files []string, parser *tsParser, astMap map[string]SourceFile) {
-	for _, file := range files {
-		sourceFile := parseSingleFile(file, parser)
-		if sourceFile != nil {
-			astMap[file] = *sourceFile
-		}
+//
+// Create a map of ASTs by parsing each file, and also parse the files
+// they import, transitively, so that types can be read across files.
+//
+// @param ctx the context to stop parsing early with
+//
+// @param files an array of absolute file paths to process.
+//
+// @param options the options to parse files with, may be `nil`
+//
+// @param resolve the resolver to find the files being imported
+//
+// Returns the ASTs of the given files, and the modules holding the
+// ASTs of all files, including the imported ones. Imports that cannot
+// be resolved, such as those of third-party packages, are skipped.
+//
+func BuildAstForFilesAndImports(ctx context.Context, files []string, options *ParseOptions, resolve ModuleResolver) (map[string]SourceFile, *Modules, *SyntaxKind, []Diagnostic) {
+	var astMap map[string]SourceFile
+	var modules *Modules
+	var syntaxKind *SyntaxKind
+	var diagnostics []Diagnostic
+
+	worker := func(parser Parser) {
+		astMap, modules, diagnostics = ParseFilesAndImports(ctx, parser, files, options, resolve)
+		syntaxKind = parser.GetSyntaxKind()
 	}
-}
 
-// Parse a single file after reading from the disk.
-// The file path specified must be an absolute file path that resolves.
-func parseSingleFile(file string, parser *tsParser) *SourceFile {
-	// fmt.Println("Processing file: " + file)
-
-	// read the source code file from disk
-	sourceCode, err := ioutil.ReadFile(file)
+	start := time.Now()
+	err := runWithParser(options, worker)
 	if err != nil {
-		panic(err)
+		return make(map[string]SourceFile), NewModules(nil, resolve), nil, []Diagnostic{newErrorDiagnostic("", err)}
 	}
 
-	return parseSingleFileContents(string(sourceCode), parser)
+	options.getLogger().Debug("Parsed files and their imports", "files", len(astMap), "duration", time.Since(start))
+
+	return astMap, modules, syntaxKind, diagnostics
 }
 
-// Parse the contents of a file/or supplied from memory
-// using the Typescript parser and return the `SourceFile` AST.
-func parseSingleFileContents(sourceCode string, parser *tsParser) *SourceFile {
-	// create argument list to call the method
-	args := make([]quickjs.Value, 4)
-	args[0] = parser.context.String("index.tsx")
-	args[1] = parser.context.String(string(sourceCode))
-	args[2] = parser.context.String("")
-	args[3] = parser.context.Bool(true)
-
-	// invoke the "createSourceFile" method
-	result, err := parser.context.Call(*parser.globals, *parser.codeParser, args)
-	defer result.Free()
-
-	args = make([]quickjs.Value, 2)
-	args[0] = result
-	args[1] = *parser.circularReplacer
-	codeJson, err := parser.context.Call(*parser.globals, *parser.stringify, args)
-	defer codeJson.Free()
-
-	// check for error, and free up the result
-	check(err)
-	if err != nil {
-		panic(err)
-	}
+// Parse the files, and the files they import, with the given parser.
+// Runs the type checker over all files if enabled in the options and
+// supported by the parser. Returns the ASTs of the given files, and the
+// modules holding the ASTs of all files, including the imported ones.
+func ParseFilesAndImports(ctx context.Context, parser Parser, files []string, options *ParseOptions, resolve ModuleResolver) (map[string]SourceFile, *Modules, []Diagnostic) {
+	astMap := make(map[string]SourceFile, len(files))
+	allFiles := make(map[string]SourceFile, len(files))
 
-	sourceFileAsString := codeJson.String()
+	diagnostics := doWork(ctx, files, parser, astMap)
+	for file, sourceFile := range astMap {
+		allFiles[file] = sourceFile
+	}
 
-	// now convert the "result" represented as AST in QJS objects
-	// to the pure objects that we require
-	// fmt.Println(sourceFileAsString)
+	// parse imported files till no new files are found
+	pending := astMap
+	for depth := 0; depth < maxImportDepth && len(pending) > 0 && ctx.Err() == nil; depth++ {
+		imported := getUnparsedImports(pending, allFiles, resolve)
 
-	sourceFile := SourceFile{}
+		pending = make(map[string]SourceFile, len(imported))
+		diagnostics = append(diagnostics, doWork(ctx, imported, parser, pending)...)
+		for file, sourceFile := range pending {
+			allFiles[file] = sourceFile
+		}
+	}
 
-	json.Unmarshal([]byte(sourceFileAsString), &sourceFile)
+	// resolve props types across all files using the type checker
+	if options != nil && options.TypeChecker && ctx.Err() == nil {
+		checker, ok := parser.(typeChecker)
+		if !ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SEVERITY_WARNING,
+				Message:  "the type checker needs the typescript parser, continuing without it",
+			})
+		} else if err := checker.checkTypes(ctx, astMap, allFiles, resolve); err != nil {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SEVERITY_WARNING,
+				Message:  "unable to run the type checker, continuing without it: " + err.Error(),
+			})
+		}
+	}
 
-	_, err = json.MarshalIndent(sourceFile, "", "  ")
-	return &sourceFile
+	return astMap, NewModules(allFiles, resolve), diagnostics
 }
 
-/**
- * Free all created objects
- */
-func (parser *tsParser) free() {
-	parser.stringify.Free()
-	parser.circularReplacer.Free()
-	parser.context.Free()
-	parser.codeParser.Free()
-
-	// finally free the runtime
-	defer parser.runtime.Free()
-}
+// Return the files imported by the given source files that have not
+// been parsed yet.
+func getUnparsedImports(sourceFiles map[string]SourceFile, parsed map[string]SourceFile, resolve ModuleResolver) []string {
+	seen := make(map[string]bool)
+	imported := make([]string, 0)
+
+	for file, sourceFile := range sourceFiles {
+		for _, specifier := range sourceFile.GetModuleSpecifiers() {
+			path := resolve(file, specifier)
+			if path == "" || seen[path] {
+				continue
+			}
 
-/**
- * Initialize the Typescript parser based on QuickJS runtime
- */
-func (parser *tsParser) init() {
-	// read typescript code to be used
-	typeScript, err := ioutil.ReadFile("/Users/sangupta/git/sangupta/bedrock/node_modules/typescript/lib/typescript.js")
-	if err != nil {
-		panic(err)
+			seen[path] = true
+			if _, exists := parsed[path]; !exists {
+				imported = append(imported, path)
+			}
+		}
 	}
 
-	// build quick js runtime
-	runtime := quickjs.NewRuntime()
-	parser.runtime = &runtime
-
-	context := runtime.NewContext()
-	parser.context = context
-
-	// load TS source code
-	result, err := context.EvalFile(string(typeScript), 0, "typescript.js")
-	check(err)
-	defer result.Free()
+	return imported
+}
 
-	// never free this - throws cgo error at app termination
-	globals := context.Globals()
-	parser.globals = &globals
+// Create the parser selected in the options and run the worker
+// with it. Returns an error if the parser could not be created.
+func runWithParser(options *ParseOptions, worker func(parser Parser)) error {
+	// all processing for QJS happens in same thread
+	stdruntime.LockOSThread()
+	defer stdruntime.UnlockOSThread()
 
-	ts := globals.Get("ts")
-	defer ts.Free()
+	parser, err := NewParser(options)
+	if err != nil {
+		return err
+	}
+	defer parser.Free()
 
-	// read syntax kind
-	sk := ts.Get("SyntaxKind")
-	defer sk.Free()
+	// run the worker
+	worker(parser)
+	return nil
+}
 
-	// get JSON.stringify function
-	jsJson := globals.Get("JSON")
-	defer jsJson.Free()
+func doWork(ctx context.Context, files []string, parser Parser, astMap map[string]SourceFile) []Diagnostic {
+	var diagnostics []Diagnostic
 
-	stringify := jsJson.Get("stringify")
-	parser.stringify = &stringify
+	for _, file := range files {
+		// leave the remaining files once the context is done
+		if err := ctx.Err(); err != nil {
+			diagnostics = append(diagnostics, newErrorDiagnostic("", errors.New("parsing stopped before all files were read: "+err.Error())))
+			break
+		}
 
-	stringifyArgs := make([]quickjs.Value, 1)
-	stringifyArgs[0] = sk
+		sourceFile, fileDiagnostics := parseSingleFile(ctx, file, parser)
+		if sourceFile != nil {
+			astMap[file] = *sourceFile
+		}
 
-	syntaxKind := SyntaxKind{}
-	syntaxKindJson, err := context.Call(globals, stringify, stringifyArgs)
-	if err == nil {
-		_ = json.Unmarshal([]byte(syntaxKindJson.String()), &syntaxKind)
+		diagnostics = append(diagnostics, fileDiagnostics...)
 	}
 
-	Syntax = &syntaxKind
-
-	// read script target
-	scriptTarget := ts.Get("ScriptTarget")
-	defer scriptTarget.Free()
-
-	system := scriptTarget.Get("Latest")
-	defer system.Free()
+	return diagnostics
+}
 
-	// read parsing function
-	parseCode := ts.Get("createSourceFile")
-	parser.codeParser = &parseCode
+// Parse a single file after reading from the disk.
+// The file path specified must be an absolute file path that resolves.
+func parseSingleFile(ctx context.Context, file string, parser Parser) (*SourceFile, []Diagnostic) {
+	// fmt.Println("Processing file: " + file)
 
-	// craeate a circular replacer
-	replacerCode := `const ____getCircularReplacer = () => {
-		const seen = new WeakSet();
-		return (key, value) => {
-		  if (typeof value === 'object' && value !== null) {
-			if (seen.has(value)) {
-			  return;
-			}
-			seen.add(value);
-		  }
-		  return value;
-		};
-	  };
-	  `
-
-	replacerCodeResult, err := context.Eval(replacerCode, quickjs.EVAL_GLOBAL)
-	defer replacerCodeResult.Free()
+	// read the source code file from disk
+	sourceCode, err := ioutil.ReadFile(file)
 	if err != nil {
-		panic(err)
+		return nil, []Diagnostic{newErrorDiagnostic(file, err)}
 	}
 
-	replacer, err := context.Eval("____getCircularReplacer()", quickjs.EVAL_GLOBAL)
-	parser.circularReplacer = &replacer
-	if err != nil {
-		panic(err)
-	}
+	return parser.ParseFile(ctx, file, string(sourceCode))
 }
 
-/**
- * Check and print the QuickJS error if any
- */
-func check(err error) {
-	if err != nil {
-		var evalErr *quickjs.Error
-		if errors.As(err, &evalErr) {
-			fmt.Println(evalErr.Cause)
-			fmt.Println(evalErr.Stack)
-		}
-		panic(err)
+// Create an error diagnostic for a file that could not be read
+// or parsed at all.
+func newErrorDiagnostic(file string, err error) Diagnostic {
+	return Diagnostic{
+		File:     file,
+		Severity: SEVERITY_ERROR,
+		Message:  err.Error(),
 	}
 }
@@ -0,0 +1,219 @@
+//go:build cgo
+
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickjs-go/quickjs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a large source file with the given number of
+// documented components, each with its own props interface.
+func getLargeSourceCode(components int) string {
+	var builder strings.Builder
+	builder.WriteString("import React from 'react';\n\n")
+
+	for index := 0; index < components; index++ {
+		fmt.Fprintf(&builder, `
+/**
+ * Props of component %[1]d
+ */
+export interface Component%[1]dProps extends React.HTMLAttributes<HTMLDivElement> {
+	/** the title to display */
+	title: string;
+	/** the size of the component */
+	size?: 'small' | 'medium' | 'large';
+	onClick?: (event: React.MouseEvent<HTMLDivElement>) => void;
+}
+
+/**
+ * Component number %[1]d.
+ *
+ * @since 1.0.0
+ */
+export class Component%[1]d extends React.Component<Component%[1]dProps> {
+	static defaultProps = {
+		size: 'medium',
+	};
+
+	render() {
+		const { title, size } = this.props;
+		return <div className={'component ' + size} onClick={this.props.onClick}>{title}</div>;
+	}
+}
+`, index)
+	}
+
+	return builder.String()
+}
+
+// Run the worker with the parser running the Typescript compiler.
+// Fails the test if the Typescript compiler cannot be loaded.
+func runWithTypescriptParser(t testing.TB, worker func(parser *tsParser)) {
+	err := runWithParser(nil, func(parser Parser) {
+		worker(parser.(*tsParser))
+	})
+	assert.Nil(t, err, "Set %s to the typescript.js file to run the test with", ENV_TYPESCRIPT_FILE)
+}
+
+func TestPrunedAstMatchesFullAst(t *testing.T) {
+	code := getLargeSourceCode(3)
+
+	runWithTypescriptParser(t, func(parser *tsParser) {
+		pruned, _, _ := parseSingleFileContents("component.tsx", code, parser)
+
+		parser.keepFullAst = true
+		full, _, _ := parseSingleFileContents("component.tsx", code, parser)
+
+		assert.NotNil(t, pruned)
+		assert.Equal(t, full, pruned, "Pruning must keep everything read into Go")
+	})
+}
+
+func TestTypeChecker(t *testing.T) {
+	folder := t.TempDir()
+	types := filepath.Join(folder, "types.ts")
+	button := filepath.Join(folder, "Button.tsx")
+
+	assert.Nil(t, os.WriteFile(types, []byte(`
+export type Size = 'small' | 'large';
+
+export interface BaseProps {
+	/** the id of the element */
+	id: string;
+	size: Size;
+	color: string;
+}
+`), 0644))
+	assert.Nil(t, os.WriteFile(button, []byte(`
+import { BaseProps } from './types';
+
+type ButtonProps = Pick<BaseProps, 'id' | 'size'> & {
+	items?: Array<string>;
+	onLoad: () => Promise<void>;
+};
+
+export function Button(props: ButtonProps) {
+	return <button id={props.id}>{props.items}</button>;
+}
+`), 0644))
+
+	resolve := func(fromFile string, specifier string) string {
+		file := filepath.Join(filepath.Dir(fromFile), specifier) + ".ts"
+		if _, err := os.Stat(file); err != nil {
+			return ""
+		}
+		return file
+	}
+
+	astMap, _, _, diagnostics := BuildAstForFilesAndImports(context.Background(), []string{button}, &ParseOptions{TypeChecker: true}, resolve)
+	assert.Equal(t, 0, len(diagnostics), "Set %s to the typescript.js file to run the test with", ENV_TYPESCRIPT_FILE)
+
+	sourceFile := astMap[button]
+	props := make(map[string]CheckedProp)
+	for _, prop := range sourceFile.GetCheckedProps("Button") {
+		props[prop.Name] = prop
+	}
+
+	// utility types and the types of the standard library are
+	// resolved from the library files
+	assert.Equal(t, 4, len(props))
+	assert.Equal(t, "string", props["id"].Type)
+	assert.Equal(t, "the id of the element", props["id"].Description)
+	assert.Equal(t, `"small" | "large"`, props["size"].Type)
+	assert.Equal(t, "string[]", props["items"].Type)
+	assert.True(t, props["items"].Optional)
+	assert.Equal(t, "() => Promise<void>", props["onLoad"].Type)
+}
+
+func TestRuntimeLimits(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	jsRuntime := quickjs.NewRuntime()
+	jsContext := jsRuntime.NewContext()
+	limits := newRuntimeLimits(&jsRuntime, &ParseOptions{Timeout: 200 * time.Millisecond, MemoryLimit: 16})
+
+	eval := func(ctx context.Context, code string) error {
+		return limits.run(ctx, func() error {
+			result, err := jsContext.Eval(code, quickjs.EVAL_GLOBAL)
+			result.Free()
+			return err
+		})
+	}
+
+	err := eval(context.Background(), "while (true) {}")
+	assert.ErrorIs(t, err, errTimedOut)
+
+	err = eval(context.Background(), "const list = []; while (true) { list.push(new Array(100000).fill(1)); }")
+	assert.True(t, isRuntimeExhausted(err), "Memory limit must stop the code: %v", err)
+
+	err = eval(context.Background(), "function recurse() { return recurse() + 1; }; recurse();")
+	assert.True(t, isRuntimeExhausted(err), "Stack limit must stop the code: %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = eval(ctx, "while (true) {}")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the runtime can be used again once interrupted
+	assert.NoError(t, eval(context.Background(), "1 + 2"))
+
+	jsContext.Free()
+	jsRuntime.Free()
+	limits.free()
+}
+
+func benchmarkParse(b *testing.B, keepFullAst bool) {
+	code := getLargeSourceCode(200)
+
+	runWithTypescriptParser(b, func(parser *tsParser) {
+		parser.keepFullAst = keepFullAst
+		b.SetBytes(int64(len(code)))
+		b.ResetTimer()
+
+		for index := 0; index < b.N; index++ {
+			parseSingleFileContents("component.tsx", code, parser)
+		}
+	})
+}
+
+func BenchmarkParsePrunedAst(b *testing.B) {
+	benchmarkParse(b, false)
+}
+
+func BenchmarkParseFullAst(b *testing.B) {
+	benchmarkParse(b, true)
+}
+
+func BenchmarkParseNative(b *testing.B) {
+	code := getLargeSourceCode(200)
+	parser := newNativeParser()
+	b.SetBytes(int64(len(code)))
+	b.ResetTimer()
+
+	for index := 0; index < b.N; index++ {
+		parser.ParseFile(context.Background(), "component.tsx", code)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+// This file contains functions to run the Typescript type checker
+// over the parsed files, so that the props of components can be read
+// with their types fully resolved, rather than as written in code.
+
+// A prop of a component as resolved by the Typescript type checker
+type CheckedProp struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`        // the resolved type, such as `"small" | "large"` for a type alias
+	Optional    bool   `json:"optional"`    // whether the prop is optional
+	Description string `json:"description"` // the documentation of the prop
+	DeclaredIn  string `json:"declaredIn"`  // the name of the interface or type declaring the prop
+	FileName    string `json:"fileName"`    // the file declaring the prop
+}
+
+// Return the props of the named component as resolved by the type
+// checker, or `nil` if the type checker was not run.
+func (sf *SourceFile) GetCheckedProps(componentName string) []CheckedProp {
+	return sf.checkedProps[componentName]
+}
@@ -0,0 +1,157 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// The severity of a diagnostic as reported by Typescript.
+// Values match the `DiagnosticCategory` enum of Typescript.
+type DiagnosticSeverity int
+
+const (
+	SEVERITY_WARNING DiagnosticSeverity = iota
+	SEVERITY_ERROR
+	SEVERITY_SUGGESTION
+	SEVERITY_MESSAGE
+)
+
+func (severity DiagnosticSeverity) String() string {
+	switch severity {
+	case SEVERITY_WARNING:
+		return "warning"
+
+	case SEVERITY_ERROR:
+		return "error"
+
+	case SEVERITY_SUGGESTION:
+		return "suggestion"
+	}
+
+	return "message"
+}
+
+// A problem found when reading or parsing a source file.
+// Line and column are 1-based, and are zero when the problem
+// does not relate to a position in the file.
+type Diagnostic struct {
+	File     string             `json:"file"`
+	Line     int                `json:"line"`
+	Column   int                `json:"column"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Message  string             `json:"message"`
+	Code     int                `json:"code"`
+}
+
+// Format the diagnostic in the usual compiler style, such as
+// `src/Button.tsx:12:5: error TS1005: ';' expected.`
+func (diagnostic Diagnostic) String() string {
+	var sb strings.Builder
+	if diagnostic.File != "" {
+		sb.WriteString(diagnostic.File)
+
+		if diagnostic.Line > 0 {
+			sb.WriteString(":" + strconv.Itoa(diagnostic.Line) + ":" + strconv.Itoa(diagnostic.Column))
+		}
+
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString(diagnostic.Severity.String())
+
+	if diagnostic.Code > 0 {
+		sb.WriteString(" TS" + strconv.Itoa(diagnostic.Code))
+	}
+
+	sb.WriteString(": " + diagnostic.Message)
+	return sb.String()
+}
+
+func (diagnostic Diagnostic) IsError() bool {
+	return diagnostic.Severity == SEVERITY_ERROR
+}
+
+// Check if any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.IsError() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A diagnostic as present in `parseDiagnostics` of the
+// Typescript `SourceFile`. The message may either be a
+// string or a chain of messages.
+type TsDiagnostic struct {
+	Start       int             `json:"start"`
+	Length      int             `json:"length"`
+	MessageText json.RawMessage `json:"messageText"`
+	Category    int             `json:"category"`
+	Code        int             `json:"code"`
+}
+
+type tsDiagnosticMessageChain struct {
+	MessageText string                     `json:"messageText"`
+	Next        []tsDiagnosticMessageChain `json:"next"`
+}
+
+// Convert the Typescript diagnostic into a `Diagnostic` for the
+// given file, computing the line and column from the source text.
+func (tsDiagnostic *TsDiagnostic) toDiagnostic(file string, sourceFile *SourceFile) Diagnostic {
+	sourceFile.ComputeLineStarts()
+	line, column := sourceFile.getLineAndColumn(tsDiagnostic.Start)
+
+	return Diagnostic{
+		File:     file,
+		Line:     line,
+		Column:   column,
+		Severity: DiagnosticSeverity(tsDiagnostic.Category),
+		Message:  tsDiagnostic.getMessage(),
+		Code:     tsDiagnostic.Code,
+	}
+}
+
+func (tsDiagnostic *TsDiagnostic) getMessage() string {
+	var message string
+	if json.Unmarshal(tsDiagnostic.MessageText, &message) == nil {
+		return message
+	}
+
+	// flatten the message chain, one message per line
+	var chain tsDiagnosticMessageChain
+	if json.Unmarshal(tsDiagnostic.MessageText, &chain) != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	flattenMessageChain(&sb, chain, 0)
+	return sb.String()
+}
+
+func flattenMessageChain(sb *strings.Builder, chain tsDiagnosticMessageChain, depth int) {
+	if depth > 0 {
+		sb.WriteRune('\n')
+		sb.WriteString(strings.Repeat("  ", depth))
+	}
+
+	sb.WriteString(chain.MessageText)
+	for _, next := range chain.Next {
+		flattenMessageChain(sb, next, depth+1)
+	}
+}
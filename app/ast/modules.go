@@ -0,0 +1,158 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+// This file contains functions to follow imports across source
+// files, so that types such as the props interface of a component
+// can be read from the file they are declared in.
+
+// The maximum number of imports and re-exports followed when looking
+// for a declaration, which also guards against import cycles.
+const maxImportDepth = 16
+
+// Resolves the module specifier of an import in the given file, such as
+// `./types` or `@/types/button`, to the absolute path of the file it
+// refers to. Returns an empty string if the module cannot be resolved.
+type ModuleResolver func(fromFile string, specifier string) string
+
+// The source files that may be imported by other source files, along
+// with the resolver used to find them.
+type Modules struct {
+	files   map[string]SourceFile
+	resolve ModuleResolver
+	loaded  map[string]*SourceFile
+}
+
+// Create the modules from the given source files, keyed by
+// their absolute path.
+func NewModules(files map[string]SourceFile, resolve ModuleResolver) *Modules {
+	return &Modules{
+		files:   files,
+		resolve: resolve,
+		loaded:  make(map[string]*SourceFile),
+	}
+}
+
+// Return the source file imported via the given specifier from the
+// given file. Returns `nil` if the module cannot be resolved, or was
+// not parsed.
+func (modules *Modules) Load(fromFile string, specifier string) *SourceFile {
+	if modules == nil || modules.resolve == nil {
+		return nil
+	}
+
+	path := modules.resolve(fromFile, specifier)
+	if path == "" {
+		return nil
+	}
+
+	if sourceFile, exists := modules.loaded[path]; exists {
+		return sourceFile
+	}
+
+	sourceFile, exists := modules.files[path]
+	if !exists {
+		return nil
+	}
+
+	sourceFile.modules = modules
+	modules.loaded[path] = &sourceFile
+	return &sourceFile
+}
+
+// Set the modules this source file may import from.
+func (sf *SourceFile) SetModules(modules *Modules) {
+	sf.modules = modules
+}
+
+// Return the path of the file the given name is imported from, along
+// with the name it is exported as from there, such as `default` for
+// default imports. Returns an empty path if the module cannot be
+// loaded, and empty values if the name is not imported.
+func (sf *SourceFile) GetImportedFile(name string) (string, string) {
+	library := sf.GetImportPath(name)
+	if library == "" {
+		return "", ""
+	}
+
+	module := sf.modules.Load(sf.FileName, library)
+	if module == nil {
+		return "", sf.importNames[name]
+	}
+
+	return module.FileName, sf.importNames[name]
+}
+
+// Return the module specifiers of all imports and re-exports
+// in the source file, such as `./Button` in `export * from './Button'`.
+func (sf *SourceFile) GetModuleSpecifiers() []string {
+	specifiers := make([]string, 0)
+	for _, statement := range sf.Statements {
+		if !sf.syntax.IsImportDeclaration(&statement) && !sf.syntax.IsExportDeclaration(&statement) {
+			continue
+		}
+
+		if statement.ModuleSpecifier != nil && statement.ModuleSpecifier.Text != "" {
+			specifiers = append(specifiers, statement.ModuleSpecifier.Text)
+		}
+	}
+
+	return specifiers
+}
+
+// Find the interface of the given name exported by the library. The
+// library's own imports and re-exports, like `export * from './types'`
+// or `export { Props as ButtonProps } from './Button'`, are followed.
+func (sf *SourceFile) findInterfaceInLibrary(library string, typeName string, depth int) (*SourceFile, *Statement) {
+	if depth >= maxImportDepth {
+		return nil, nil
+	}
+
+	module := sf.modules.Load(sf.FileName, library)
+	if module == nil {
+		return nil, nil
+	}
+
+	// declared in, or imported into, the module itself
+	if source, declaration := module.findInterface(typeName, depth+1); declaration != nil {
+		return source, declaration
+	}
+
+	// re-exported from another module
+	for _, statement := range module.Statements {
+		if !module.syntax.IsExportDeclaration(&statement) || statement.ModuleSpecifier == nil {
+			continue
+		}
+
+		name := typeName
+		if statement.ExportClause != nil {
+			name = ""
+			for _, element := range statement.ExportClause.Elements {
+				if element.Name.EscapedText == typeName {
+					name = element.GetImportedName()
+					break
+				}
+			}
+		}
+
+		if name == "" {
+			continue
+		}
+
+		if source, declaration := module.findInterfaceInLibrary(statement.ModuleSpecifier.Text, name, depth+1); declaration != nil {
+			return source, declaration
+		}
+	}
+
+	return nil, nil
+}
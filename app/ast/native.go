@@ -0,0 +1,512 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// This file contains the native Go parser, which parses Typescript,
+// JSX and Javascript without cgo or the Typescript compiler. It
+// creates nodes with the same kinds and properties as Typescript, for
+// the properties read by the `ast` types, and converts them to the
+// same `SourceFile` as read from the AST of the Typescript parser.
+
+// A node of the AST created by the native parser. Only the properties
+// that are read into the `ast` types are kept. Lists are pointers to
+// tell an empty list, like the parameters of `()`, from a missing one.
+type nativeNode struct {
+	Kind                     int
+	Pos                      int
+	End                      int
+	Text                     string
+	EscapedText              string
+	HasExtendedUnicodeEscape bool
+	Comment                  string
+	IsBracketed              bool
+	IsTypeOnly               bool
+	Name                     *nativeNode
+	PropertyName             *nativeNode
+	Expression               *nativeNode
+	Body                     *nativeNode
+	Type                     *nativeNode
+	TypeName                 *nativeNode
+	Literal                  *nativeNode
+	ElementType              *nativeNode
+	Constraint               *nativeNode
+	Default                  *nativeNode
+	Initializer              *nativeNode
+	ImportClause             *nativeNode
+	NamedBindings            *nativeNode
+	ModuleSpecifier          *nativeNode
+	ExportClause             *nativeNode
+	Left                     *nativeNode
+	OperatorToken            *nativeNode
+	Right                    *nativeNode
+	QuestionToken            *nativeNode
+	DotDotDotToken           *nativeNode
+	TagName                  *nativeNode
+	TypeExpression           *nativeNode
+	OpeningElement           *nativeNode
+	ClosingElement           *nativeNode
+	Attributes               *nativeNode
+	Statements               *nativeNodes
+	Members                  *nativeNodes
+	Parameters               *nativeNodes
+	TypeParameters           *nativeNodes
+	TypeArguments            *nativeNodes
+	HeritageClauses          *nativeNodes
+	Modifiers                *nativeNodes
+	JsDoc                    *nativeNodes
+	Tags                     *nativeNodes
+	Types                    *nativeNodes
+	Elements                 *nativeNodes
+	Arguments                *nativeNodes
+	Properties               *nativeNodes
+	Children                 *nativeNodes
+	JsDocPropertyTags        *nativeNodes
+
+	pos int // the offset in bytes where the node starts, including leading trivia
+	end int // the offset in bytes where the node ends
+}
+
+type nativeNodes []*nativeNode
+
+// Return a pointer to the list, as stored in the nodes.
+func listOf(nodes nativeNodes) *nativeNodes {
+	if nodes == nil {
+		nodes = nativeNodes{}
+	}
+
+	return &nodes
+}
+
+// Parses files natively. The parser has no state of its own, and
+// each file is parsed by a new `nativeFileParser`.
+type nativeParser struct {
+	kinds *SyntaxKind
+}
+
+var nativeSyntaxKind *SyntaxKind
+var nativeSyntaxKindOnce sync.Once
+
+// Create a parser that parses files natively, in Go.
+func newNativeParser() Parser {
+	nativeSyntaxKindOnce.Do(func() {
+		nativeSyntaxKind = newNativeSyntaxKind()
+	})
+
+	return &nativeParser{kinds: nativeSyntaxKind}
+}
+
+func (parser *nativeParser) GetSyntaxKind() *SyntaxKind {
+	return parser.kinds
+}
+
+func (parser *nativeParser) Free() {
+}
+
+func (parser *nativeParser) ParseFile(ctx context.Context, fileName string, contents string) (*SourceFile, []Diagnostic) {
+	p := newNativeFileParser(parser.kinds, fileName, contents)
+	root := p.parseSourceFile()
+
+	sourceFile := root.toSourceFile(fileName, contents, p.diagnostics)
+	sourceFile.syntax = parser.kinds
+
+	diagnostics := make([]Diagnostic, 0, len(sourceFile.ParseDiagnostics))
+	for _, tsDiagnostic := range sourceFile.ParseDiagnostics {
+		diagnostics = append(diagnostics, tsDiagnostic.toDiagnostic(fileName, &sourceFile))
+	}
+
+	return &sourceFile, diagnostics
+}
+
+// Create the syntax kinds used by the native parser. Every kind is
+// given its own value, including the kinds that Typescript aliases,
+// like `FirstStatement` which the native parser uses for the
+// `VariableStatement` that is missing in `SyntaxKind`.
+func newNativeSyntaxKind() *SyntaxKind {
+	kinds := &SyntaxKind{}
+	value := reflect.ValueOf(kinds).Elem()
+
+	for index := 0; index < value.NumField(); index++ {
+		field := value.Field(index)
+		if field.CanSet() {
+			field.SetInt(int64(index + 1))
+		}
+	}
+
+	return kinds
+}
+
+// The state of parsing a single file natively.
+type nativeFileParser struct {
+	nativeScanner
+	kinds       *SyntaxKind
+	fileName    string
+	jsx         bool           // whether JSX is allowed, as in all but `.ts` files
+	offsets     []int          // the UTF-16 offset of each byte, or `nil` if the text is ASCII
+	diagnostics []TsDiagnostic // the syntax errors found
+	lastError   int            // the start of the last error, to report one error per position
+	speculating int            // the depth of speculative parsing, in which errors abort parsing
+	inJsDoc     bool           // whether parsing a JSDoc comment, in which errors are ignored
+	inJsDocType bool           // whether parsing a JSDoc type, which allows types like `?string`
+	disallowIn  bool           // whether `in` is not an operator, as in the initializer of `for`
+	inYield     bool           // whether `yield` is an operator, as in generators
+	inAwait     bool           // whether `await` is an operator, as in async functions
+	keywords    map[string]int // the kinds of keywords, keyed by the keyword
+	operators   map[string]int // the kinds of operators, keyed by the operator
+}
+
+func newNativeFileParser(kinds *SyntaxKind, fileName string, text string) *nativeFileParser {
+	p := &nativeFileParser{
+		kinds:     kinds,
+		fileName:  fileName,
+		lastError: -1,
+		keywords:  getNativeKeywordKinds(kinds),
+		operators: getNativeOperatorKinds(kinds),
+	}
+
+	p.nativeScanner = nativeScanner{text: text, end: len(text), onError: p.errorAt}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ts", ".mts", ".cts":
+		p.jsx = false
+
+	default:
+		p.jsx = true
+	}
+
+	// Typescript reports offsets in UTF-16 code units
+	for index := 0; index < len(text); index++ {
+		if text[index] >= utf8.RuneSelf {
+			p.offsets = getUtf16Offsets(text)
+			break
+		}
+	}
+
+	return p
+}
+
+// Return the UTF-16 offset of every byte offset in the text.
+func getUtf16Offsets(text string) []int {
+	offsets := make([]int, len(text)+1)
+	offset := 0
+
+	for index, char := range text {
+		size := utf8.RuneLen(char)
+		if size < 0 {
+			size = 1
+		}
+
+		for byteIndex := 0; byteIndex < size && index+byteIndex < len(text); byteIndex++ {
+			offsets[index+byteIndex] = offset
+		}
+
+		offset += len(utf16.Encode([]rune{char}))
+	}
+
+	offsets[len(text)] = offset
+	return offsets
+}
+
+// Return the UTF-16 offset for the byte offset.
+func (p *nativeFileParser) offset(pos int) int {
+	if p.offsets == nil {
+		return pos
+	}
+
+	return p.offsets[pos]
+}
+
+// Create a node starting at the given byte offset.
+func (p *nativeFileParser) newNode(kind int, pos int) *nativeNode {
+	return &nativeNode{Kind: kind, pos: pos}
+}
+
+// Finish the node, which ends where the last token consumed ends.
+func (p *nativeFileParser) finish(node *nativeNode) *nativeNode {
+	return p.finishAt(node, p.fullStart)
+}
+
+func (p *nativeFileParser) finishAt(node *nativeNode, end int) *nativeNode {
+	if end < node.pos {
+		end = node.pos
+	}
+
+	node.end = end
+	node.Pos = p.offset(node.pos)
+	node.End = p.offset(end)
+	return node
+}
+
+// Record a syntax error, unless one has already been reported at the
+// same position. When parsing speculatively, parsing is aborted instead.
+func (p *nativeFileParser) errorAt(message string, code int, start int, length int) {
+	if p.speculating > 0 {
+		panic(nativeSpeculationFailed{})
+	}
+
+	if p.inJsDoc || start == p.lastError {
+		return
+	}
+
+	messageJson, _ := json.Marshal(message)
+	p.lastError = start
+	p.diagnostics = append(p.diagnostics, TsDiagnostic{
+		Start:       p.offset(start),
+		Length:      p.offset(start+length) - p.offset(start),
+		MessageText: messageJson,
+		Category:    int(SEVERITY_ERROR),
+		Code:        code,
+	})
+}
+
+// Record a syntax error at the current token.
+func (p *nativeFileParser) error(message string, code int) {
+	length := p.pos - p.start
+	if p.start+length > len(p.text) {
+		length = 0
+	}
+
+	p.errorAt(message, code, p.start, length)
+}
+
+// Aborts parsing when parsing speculatively.
+type nativeSpeculationFailed struct{}
+
+// The state to restore when speculative parsing fails.
+type nativeParserState struct {
+	scanner     nativeScanner
+	diagnostics int
+	lastError   int
+	inJsDocType bool
+	disallowIn  bool
+	inYield     bool
+	inAwait     bool
+}
+
+func (p *nativeFileParser) saveState() nativeParserState {
+	return nativeParserState{
+		scanner:     p.nativeScanner,
+		diagnostics: len(p.diagnostics),
+		lastError:   p.lastError,
+		inJsDocType: p.inJsDocType,
+		disallowIn:  p.disallowIn,
+		inYield:     p.inYield,
+		inAwait:     p.inAwait,
+	}
+}
+
+func (p *nativeFileParser) restoreState(state nativeParserState) {
+	p.nativeScanner = state.scanner
+	p.diagnostics = p.diagnostics[:state.diagnostics]
+	p.lastError = state.lastError
+	p.inJsDocType = state.inJsDocType
+	p.disallowIn = state.disallowIn
+	p.inYield = state.inYield
+	p.inAwait = state.inAwait
+}
+
+// Run the callback and always restore the state afterwards.
+func (p *nativeFileParser) lookAhead(callback func() bool) bool {
+	state := p.saveState()
+	defer p.restoreState(state)
+
+	return p.speculate(callback)
+}
+
+// Run the callback speculatively, restoring the state if it fails,
+// either by returning `false` or by running into a syntax error.
+func (p *nativeFileParser) tryParse(callback func() bool) bool {
+	state := p.saveState()
+	if p.speculate(callback) {
+		return true
+	}
+
+	p.restoreState(state)
+	return false
+}
+
+func (p *nativeFileParser) speculate(callback func() bool) (result bool) {
+	p.speculating++
+	defer func() {
+		p.speculating--
+		if recovered := recover(); recovered != nil {
+			if _, ok := recovered.(nativeSpeculationFailed); !ok {
+				panic(recovered)
+			}
+
+			result = false
+		}
+	}()
+
+	return callback()
+}
+
+// Move to the next token.
+func (p *nativeFileParser) next() {
+	p.scan()
+}
+
+// Check if the current token is the given punctuation.
+func (p *nativeFileParser) at(punctuation string) bool {
+	return p.token == tokenPunctuation && p.value == punctuation
+}
+
+// Check if the current token is the given keyword, or contextual
+// keyword like `type`, written without escapes.
+func (p *nativeFileParser) atWord(word string) bool {
+	return p.token == tokenIdentifier && p.value == word && !p.escaped
+}
+
+// Consume the given punctuation if it is the current token.
+func (p *nativeFileParser) eat(punctuation string) bool {
+	if p.at(punctuation) {
+		p.next()
+		return true
+	}
+
+	return false
+}
+
+// Consume the given keyword if it is the current token.
+func (p *nativeFileParser) eatWord(word string) bool {
+	if p.atWord(word) {
+		p.next()
+		return true
+	}
+
+	return false
+}
+
+// Consume the given punctuation, reporting an error if it is not
+// the current token.
+func (p *nativeFileParser) expect(punctuation string) bool {
+	if p.eat(punctuation) {
+		return true
+	}
+
+	p.error("'"+punctuation+"' expected.", 1005)
+	return false
+}
+
+// Consume the given keyword, reporting an error if it is not
+// the current token.
+func (p *nativeFileParser) expectWord(word string) bool {
+	if p.eatWord(word) {
+		return true
+	}
+
+	p.error("'"+word+"' expected.", 1005)
+	return false
+}
+
+// Consume the semicolon ending a statement, which may be left out
+// before a line break, a closing brace or the end of the file.
+func (p *nativeFileParser) parseSemicolon() {
+	if p.eat(";") {
+		return
+	}
+
+	if p.at("}") || p.token == tokenEndOfFile || p.lineBreak {
+		return
+	}
+
+	p.error("';' expected.", 1005)
+}
+
+// Check if the next token is the given punctuation, on the same line
+// if required.
+func (p *nativeFileParser) nextIs(punctuation string, sameLine bool) bool {
+	return p.lookAhead(func() bool {
+		p.next()
+		return p.at(punctuation) && !(sameLine && p.lineBreak)
+	})
+}
+
+// Check if the next token is the given keyword, on the same line
+// if required.
+func (p *nativeFileParser) nextIsWord(word string, sameLine bool) bool {
+	return p.lookAhead(func() bool {
+		p.next()
+		return p.atWord(word) && !(sameLine && p.lineBreak)
+	})
+}
+
+// Create a token node, such as a modifier or an operator.
+func (p *nativeFileParser) parseTokenNode(kind int) *nativeNode {
+	node := p.newNode(kind, p.fullStart)
+	p.next()
+	return p.finish(node)
+}
+
+// The kinds of the keywords, keyed by the keyword. Keywords missing
+// in `SyntaxKind` use the alias Typescript declares for them.
+func getNativeKeywordKinds(kinds *SyntaxKind) map[string]int {
+	keywordKinds := map[string]int{
+		"break":      kinds.FirstKeyword,
+		"with":       kinds.LastReservedWord,
+		"implements": kinds.FirstFutureReservedWord,
+		"yield":      kinds.LastFutureReservedWord,
+		"abstract":   kinds.FirstContextualKeyword,
+		"of":         kinds.LastContextualKeyword,
+	}
+
+	value := reflect.ValueOf(kinds).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		name := value.Type().Field(index).Name
+		if strings.HasPrefix(name, "First") || strings.HasPrefix(name, "Last") {
+			continue
+		}
+
+		if strings.HasSuffix(name, "Keyword") && value.Field(index).CanInterface() {
+			keywordKinds[strings.ToLower(strings.TrimSuffix(name, "Keyword"))] = int(value.Field(index).Int())
+		}
+	}
+
+	return keywordKinds
+}
+
+// The kinds of the operators, keyed by the operator. Operators missing
+// in `SyntaxKind` use the alias Typescript declares for them.
+func getNativeOperatorKinds(kinds *SyntaxKind) map[string]int {
+	return map[string]int{
+		"{": kinds.FirstPunctuation, "}": kinds.CloseBraceToken, "(": kinds.OpenParenToken, ")": kinds.CloseParenToken,
+		"[": kinds.OpenBracketToken, "]": kinds.CloseBracketToken, ".": kinds.DotToken, "...": kinds.DotDotDotToken,
+		";": kinds.SemicolonToken, ",": kinds.CommaToken, "?.": kinds.QuestionDotToken, "<": kinds.FirstBinaryOperator,
+		"</": kinds.LessThanSlashToken, ">": kinds.GreaterThanToken, "<=": kinds.LessThanEqualsToken,
+		">=": kinds.GreaterThanEqualsToken, "==": kinds.EqualsEqualsToken, "!=": kinds.ExclamationEqualsToken,
+		"===": kinds.EqualsEqualsEqualsToken, "!==": kinds.ExclamationEqualsEqualsToken, "=>": kinds.EqualsGreaterThanToken,
+		"+": kinds.PlusToken, "-": kinds.MinusToken, "*": kinds.AsteriskToken, "**": kinds.AsteriskAsteriskToken,
+		"/": kinds.SlashToken, "%": kinds.PercentToken, "++": kinds.PlusPlusToken, "--": kinds.MinusMinusToken,
+		"<<": kinds.LessThanLessThanToken, ">>": kinds.GreaterThanGreaterThanToken,
+		">>>": kinds.GreaterThanGreaterThanGreaterThanToken, "&": kinds.AmpersandToken, "|": kinds.BarToken,
+		"^": kinds.CaretToken, "!": kinds.ExclamationToken, "~": kinds.TildeToken, "&&": kinds.AmpersandAmpersandToken,
+		"||": kinds.BarBarToken, "?": kinds.QuestionToken, ":": kinds.ColonToken, "@": kinds.AtToken,
+		"??": kinds.QuestionQuestionToken, "`": kinds.BacktickToken, "#": kinds.HashToken, "=": kinds.EqualsToken,
+		"+=": kinds.FirstCompoundAssignment, "-=": kinds.MinusEqualsToken, "*=": kinds.AsteriskEqualsToken,
+		"**=": kinds.AsteriskAsteriskEqualsToken, "/=": kinds.SlashEqualsToken, "%=": kinds.PercentEqualsToken,
+		"<<=": kinds.LessThanLessThanEqualsToken, ">>=": kinds.GreaterThanGreaterThanEqualsToken,
+		">>>=": kinds.GreaterThanGreaterThanGreaterThanEqualsToken, "&=": kinds.AmpersandEqualsToken,
+		"|=": kinds.BarEqualsToken, "||=": kinds.BarBarEqualsToken, "&&=": kinds.AmpersandAmpersandEqualsToken,
+		"??=": kinds.QuestionQuestionEqualsToken, "^=": kinds.LastBinaryOperator,
+	}
+}
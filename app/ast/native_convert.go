@@ -0,0 +1,277 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+// This file converts the nodes created by the native parser to the
+// `ast` types. Each type is given the properties of the node that it
+// reads from the AST of the Typescript parser, so that both parsers
+// create the same `SourceFile`. Missing nodes are left `nil` and
+// missing lists are left `nil`, while empty lists are kept empty.
+
+// Convert each node of the list, or return `nil` if there is no list.
+func convertNodes[T any](nodes *nativeNodes, convert func(node *nativeNode) T) []T {
+	if nodes == nil {
+		return nil
+	}
+
+	converted := make([]T, 0, len(*nodes))
+	for _, node := range *nodes {
+		converted = append(converted, convert(node))
+	}
+
+	return converted
+}
+
+// Convert the node, or return `nil` if there is no node.
+func convertNode[T any](node *nativeNode, convert func(node *nativeNode) T) *T {
+	if node == nil {
+		return nil
+	}
+
+	converted := convert(node)
+	return &converted
+}
+
+func (node *nativeNode) toSourceFile(fileName string, text string, diagnostics []TsDiagnostic) SourceFile {
+	return SourceFile{
+		FileName:         fileName,
+		Statements:       convertNodes(node.Statements, (*nativeNode).toStatement),
+		ParseDiagnostics: diagnostics,
+		Text:             text,
+		Kind:             node.Kind,
+	}
+}
+
+func (node *nativeNode) toAstObject() AstObject {
+	return AstObject{
+		EscapedText:              node.EscapedText,
+		Comment:                  node.Comment,
+		Text:                     node.Text,
+		HasExtendedUnicodeEscape: node.HasExtendedUnicodeEscape,
+		Left:                     convertNode(node.Left, (*nativeNode).toAstObject),
+		Right:                    convertNode(node.Right, (*nativeNode).toAstObject),
+		Kind:                     node.Kind,
+	}
+}
+
+func (node *nativeNode) toBlock() Block {
+	return Block{
+		Statements: convertNodes(node.Statements, (*nativeNode).toStatement),
+		Kind:       node.Kind,
+	}
+}
+
+func (node *nativeNode) toElement() Element {
+	element := Element{Kind: node.Kind}
+	if node.Name != nil {
+		element.Name = node.Name.toAstObject()
+	}
+	if node.PropertyName != nil {
+		element.PropertyName = node.PropertyName.toAstObject()
+	}
+
+	return element
+}
+
+func (node *nativeNode) toExpression() Expression {
+	return Expression{
+		Expression:               convertNode(node.Expression, (*nativeNode).toExpression),
+		Name:                     convertNode(node.Name, (*nativeNode).toAstObject),
+		EscapedText:              node.EscapedText,
+		Comment:                  node.Comment,
+		Text:                     node.Text,
+		HasExtendedUnicodeEscape: node.HasExtendedUnicodeEscape,
+		Kind:                     node.Kind,
+		OpeningElement:           convertNode(node.OpeningElement, (*nativeNode).toJsxElement),
+		Children:                 convertNodes(node.Children, (*nativeNode).toAstObject),
+		ClosingElement:           convertNode(node.ClosingElement, (*nativeNode).toJsxElement),
+		Arguments:                convertNodes(node.Arguments, (*nativeNode).toExpression),
+		Left:                     convertNode(node.Left, (*nativeNode).toExpression),
+		OperatorToken:            convertNode(node.OperatorToken, (*nativeNode).toAstObject),
+		Right:                    convertNode(node.Right, (*nativeNode).toExpression),
+		Properties:               convertNodes(node.Properties, (*nativeNode).toProperty),
+		Elements:                 convertNodes(node.Elements, (*nativeNode).toExpression),
+	}
+}
+
+func (node *nativeNode) toJsDoc() JsDoc {
+	return JsDoc{
+		Comment: node.Comment,
+		Tags:    convertNodes(node.Tags, (*nativeNode).toJsDocTag),
+		Kind:    node.Kind,
+	}
+}
+
+func (node *nativeNode) toJsDocTag() JsDocTag {
+	return JsDocTag{
+		TagName:        convertNode(node.TagName, (*nativeNode).toAstObject),
+		Name:           convertNode(node.Name, (*nativeNode).toAstObject),
+		TypeExpression: convertNode(node.TypeExpression, (*nativeNode).toJsDocTypeExpression),
+		Comment:        node.Comment,
+		IsBracketed:    node.IsBracketed,
+		Pos:            node.Pos,
+		End:            node.End,
+		Kind:           node.Kind,
+	}
+}
+
+func (node *nativeNode) toJsDocTypeExpression() JsDocTypeExpression {
+	return JsDocTypeExpression{
+		TypeValue:         convertNode(node.Type, (*nativeNode).toTypeReference),
+		JsDocPropertyTags: convertNodes(node.JsDocPropertyTags, (*nativeNode).toJsDocTag),
+		Kind:              node.Kind,
+	}
+}
+
+func (node *nativeNode) toJsxElement() JsxElement {
+	return JsxElement{
+		TagName:    convertNode(node.TagName, (*nativeNode).toAstObject),
+		Attributes: convertNode(node.Attributes, (*nativeNode).toJsxAttributes),
+		Kind:       node.Kind,
+	}
+}
+
+func (node *nativeNode) toJsxAttributes() JsxAttributes {
+	return JsxAttributes{
+		Properties: convertNodes(node.Properties, (*nativeNode).toMember),
+		Kind:       node.Kind,
+	}
+}
+
+func (node *nativeNode) toHeritageClause() HeritageClause {
+	return HeritageClause{
+		Types: convertNodes(node.Types, (*nativeNode).toTypeValue),
+		Kind:  node.Kind,
+	}
+}
+
+func (node *nativeNode) toImportClause() ImportClause {
+	return ImportClause{
+		IsTypeOnly:    node.IsTypeOnly,
+		Name:          convertNode(node.Name, (*nativeNode).toAstObject),
+		NamedBindings: convertNode(node.NamedBindings, (*nativeNode).toNamedBindings),
+		Kind:          node.Kind,
+	}
+}
+
+func (node *nativeNode) toInitializer() Initializer {
+	return Initializer{
+		Properties:  convertNodes(node.Properties, (*nativeNode).toProperty),
+		EscapedText: node.EscapedText,
+		Kind:        node.Kind,
+	}
+}
+
+func (node *nativeNode) toMember() Member {
+	return Member{
+		Name:          convertNode(node.Name, (*nativeNode).toAstObject),
+		TypeReference: convertNode(node.Type, (*nativeNode).toTypeReference),
+		QuestionToken: convertNode(node.QuestionToken, (*nativeNode).toAstObject),
+		JsDoc:         convertNodes(node.JsDoc, (*nativeNode).toJsDoc),
+		Modifiers:     convertNodes(node.Modifiers, (*nativeNode).toAstObject),
+		Initializer:   convertNode(node.Initializer, (*nativeNode).toInitializer),
+		Parameters:    convertNodes(node.Parameters, (*nativeNode).toParameter),
+		Pos:           node.Pos,
+		End:           node.End,
+		Kind:          node.Kind,
+	}
+}
+
+func (node *nativeNode) toModuleSpecifier() ModuleSpecifier {
+	return ModuleSpecifier{
+		Text: node.Text,
+		Kind: node.Kind,
+	}
+}
+
+func (node *nativeNode) toNamedBindings() NamedBindings {
+	return NamedBindings{
+		Name:     convertNode(node.Name, (*nativeNode).toAstObject),
+		Elements: convertNodes(node.Elements, (*nativeNode).toElement),
+		Kind:     node.Kind,
+	}
+}
+
+func (node *nativeNode) toParameter() Parameter {
+	return Parameter{
+		Name:           convertNode(node.Name, (*nativeNode).toAstObject),
+		TypeReference:  convertNode(node.Type, (*nativeNode).toTypeReference),
+		QuestionToken:  convertNode(node.QuestionToken, (*nativeNode).toAstObject),
+		DotDotDotToken: convertNode(node.DotDotDotToken, (*nativeNode).toAstObject),
+		Initializer:    convertNode(node.Initializer, (*nativeNode).toAstObject),
+		Kind:           node.Kind,
+	}
+}
+
+func (node *nativeNode) toProperty() Property {
+	return Property{
+		Name:        convertNode(node.Name, (*nativeNode).toAstObject),
+		Initializer: convertNode(node.Initializer, (*nativeNode).toExpression),
+		JsDoc:       convertNodes(node.JsDoc, (*nativeNode).toJsDoc),
+		Pos:         node.Pos,
+		End:         node.End,
+		Kind:        node.Kind,
+	}
+}
+
+func (node *nativeNode) toStatement() Statement {
+	return Statement{
+		ImportClause:    convertNode(node.ImportClause, (*nativeNode).toImportClause),
+		ModuleSpecifier: convertNode(node.ModuleSpecifier, (*nativeNode).toModuleSpecifier),
+		ExportClause:    convertNode(node.ExportClause, (*nativeNode).toNamedBindings),
+		Name:            convertNode(node.Name, (*nativeNode).toAstObject),
+		Body:            convertNode(node.Body, (*nativeNode).toBlock),
+		Expression:      convertNode(node.Expression, (*nativeNode).toExpression),
+		HeritageClauses: convertNodes(node.HeritageClauses, (*nativeNode).toHeritageClause),
+		Modifiers:       convertNodes(node.Modifiers, (*nativeNode).toAstObject),
+		Members:         convertNodes(node.Members, (*nativeNode).toMember),
+		JsDoc:           convertNodes(node.JsDoc, (*nativeNode).toJsDoc),
+		Parameters:      convertNodes(node.Parameters, (*nativeNode).toParameter),
+		TypeParameters:  convertNodes(node.TypeParameters, (*nativeNode).toTypeParameter),
+		Pos:             node.Pos,
+		End:             node.End,
+		Kind:            node.Kind,
+	}
+}
+
+func (node *nativeNode) toTypeParameter() TypeParameter {
+	return TypeParameter{
+		Name:       convertNode(node.Name, (*nativeNode).toAstObject),
+		Constraint: convertNode(node.Constraint, (*nativeNode).toTypeReference),
+		Default:    convertNode(node.Default, (*nativeNode).toTypeReference),
+		Kind:       node.Kind,
+	}
+}
+
+func (node *nativeNode) toTypeValue() TypeValue {
+	return TypeValue{
+		Expression:    convertNode(node.Expression, (*nativeNode).toExpression),
+		TypeArguments: convertNodes(node.TypeArguments, (*nativeNode).toTypeReference),
+		Kind:          node.Kind,
+	}
+}
+
+func (node *nativeNode) toTypeReference() TypeReference {
+	return TypeReference{
+		TypeName:          convertNode(node.TypeName, (*nativeNode).toAstObject),
+		TypeValue:         convertNode(node.Type, (*nativeNode).toTypeReference),
+		TypeArguments:     convertNodes(node.TypeArguments, (*nativeNode).toTypeReference),
+		ElementType:       convertNode(node.ElementType, (*nativeNode).toTypeReference),
+		Types:             convertNodes(node.Types, (*nativeNode).toTypeReference),
+		Members:           convertNodes(node.Members, (*nativeNode).toMember),
+		Parameters:        convertNodes(node.Parameters, (*nativeNode).toParameter),
+		Literal:           convertNode(node.Literal, (*nativeNode).toAstObject),
+		JsDocPropertyTags: convertNodes(node.JsDocPropertyTags, (*nativeNode).toJsDocTag),
+		Kind:              node.Kind,
+	}
+}
@@ -0,0 +1,1301 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import "strings"
+
+// This file contains the functions of the native parser that parse
+// names, expressions and JSX, following the Typescript parser.
+
+// The reserved words, which cannot be used as identifiers
+var nativeReservedWords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+}
+
+// The reserved words that may start an expression
+var nativeExpressionWords = map[string]bool{
+	"this": true, "super": true, "null": true, "true": true, "false": true, "function": true,
+	"class": true, "new": true, "delete": true, "typeof": true, "void": true, "import": true,
+}
+
+// The precedence of the binary operators, where a higher precedence
+// binds tighter
+var nativePrecedence = map[string]int{
+	"??": 4, "||": 5, "&&": 6, "|": 7, "^": 8, "&": 9,
+	"==": 10, "!=": 10, "===": 10, "!==": 10,
+	"<": 11, ">": 11, "<=": 11, ">=": 11, "instanceof": 11, "in": 11, "as": 11, "satisfies": 11,
+	"<<": 12, ">>": 12, ">>>": 12,
+	"+": 13, "-": 13,
+	"*": 14, "/": 14, "%": 14,
+	"**": 15,
+}
+
+// The assignment operators
+var nativeAssignmentOperators = map[string]bool{
+	"=": true, "+=": true, "-=": true, "*=": true, "**=": true, "/=": true, "%=": true,
+	"<<=": true, ">>=": true, ">>>=": true, "&=": true, "|=": true, "^=": true,
+	"||=": true, "&&=": true, "??=": true,
+}
+
+// Check if the current token is an identifier, rather than a reserved
+// word, or `yield` and `await` where they are operators.
+func (p *nativeFileParser) isIdentifier() bool {
+	if p.token != tokenIdentifier {
+		return false
+	}
+
+	if p.escaped {
+		return true
+	}
+
+	if p.value == "yield" && p.inYield || p.value == "await" && p.inAwait {
+		return false
+	}
+
+	return !nativeReservedWords[p.value]
+}
+
+// Parse an identifier, reporting an error if the current token is not
+// an identifier.
+func (p *nativeFileParser) parseIdentifier() *nativeNode {
+	if p.isIdentifier() {
+		return p.parseIdentifierName()
+	}
+
+	if p.token == tokenIdentifier {
+		p.error("Identifier expected. '"+p.value+"' is a reserved word that cannot be used here.", 1359)
+	} else {
+		p.error("Identifier expected.", 1003)
+	}
+
+	return p.newMissingIdentifier()
+}
+
+// Parse the identifier naming a declaration.
+func (p *nativeFileParser) parseBindingIdentifier() *nativeNode {
+	return p.parseIdentifier()
+}
+
+// Parse an identifier, where reserved words are allowed, as in the
+// names of properties.
+func (p *nativeFileParser) parseIdentifierName() *nativeNode {
+	if p.token != tokenIdentifier {
+		p.error("Identifier expected.", 1003)
+		return p.newMissingIdentifier()
+	}
+
+	node := p.newNode(p.kinds.Identifier, p.fullStart)
+	node.EscapedText = escapeNativeName(p.value)
+	node.HasExtendedUnicodeEscape = p.extended
+	p.next()
+
+	return p.finish(node)
+}
+
+// Create an identifier for a name that is missing, which ends where
+// it starts.
+func (p *nativeFileParser) newMissingIdentifier() *nativeNode {
+	return p.finish(p.newNode(p.kinds.Identifier, p.fullStart))
+}
+
+// Escape the name the way Typescript does, which prefixes names
+// starting with `__` with another underscore.
+func escapeNativeName(name string) string {
+	if strings.HasPrefix(name, "__") {
+		return "_" + name
+	}
+
+	return name
+}
+
+// Parse a string, number or bigint literal.
+func (p *nativeFileParser) parseLiteral() *nativeNode {
+	kind := p.kinds.StringLiteral
+	switch p.token {
+	case tokenNumber:
+		kind = p.kinds.NumericLiteral
+
+	case tokenBigInt:
+		kind = p.kinds.BigIntLiteral
+
+	case tokenRegularExpression:
+		kind = p.kinds.RegularExpressionLiteral
+	}
+
+	node := p.newNode(kind, p.fullStart)
+	node.Text = p.value
+	p.next()
+
+	return p.finish(node)
+}
+
+// Parse the name of a property, which may also be a string, a number,
+// a private name or a computed name, like `[key]`.
+func (p *nativeFileParser) parsePropertyName() *nativeNode {
+	switch p.token {
+	case tokenString, tokenNumber, tokenBigInt:
+		return p.parseLiteral()
+
+	case tokenPrivateIdentifier:
+		node := p.newNode(p.kinds.PrivateIdentifier, p.fullStart)
+		node.EscapedText = p.value
+		p.next()
+		return p.finish(node)
+	}
+
+	if p.at("[") {
+		node := p.newNode(p.kinds.ComputedPropertyName, p.fullStart)
+		p.next()
+		node.Expression = p.parseAssignmentExpressionAllowIn()
+		p.expect("]")
+		return p.finish(node)
+	}
+
+	return p.parseIdentifierName()
+}
+
+// Parse an expression, which may be a list of expressions separated
+// by commas.
+func (p *nativeFileParser) parseExpression() *nativeNode {
+	pos := p.fullStart
+	expression := p.parseAssignmentExpression()
+
+	for p.at(",") {
+		node := p.newNode(p.kinds.BinaryExpression, pos)
+		node.Left = expression
+		node.OperatorToken = p.parseTokenNode(p.kinds.CommaToken)
+		node.Right = p.parseAssignmentExpression()
+		expression = p.finish(node)
+	}
+
+	return expression
+}
+
+// Parse an expression in which `in` is an operator.
+func (p *nativeFileParser) parseExpressionAllowIn() *nativeNode {
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+	defer func() {
+		p.disallowIn = savedDisallowIn
+	}()
+
+	return p.parseExpression()
+}
+
+func (p *nativeFileParser) parseAssignmentExpressionAllowIn() *nativeNode {
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+	defer func() {
+		p.disallowIn = savedDisallowIn
+	}()
+
+	return p.parseAssignmentExpression()
+}
+
+// Parse an assignment, an arrow function, a conditional, or any
+// expression of a higher precedence.
+func (p *nativeFileParser) parseAssignmentExpression() *nativeNode {
+	if p.isYieldExpression() {
+		return p.parseYieldExpression()
+	}
+
+	if arrow := p.tryParseArrowFunction(); arrow != nil {
+		return arrow
+	}
+
+	pos := p.fullStart
+	expression := p.parseBinaryExpression(0)
+
+	// a simple arrow function, like `value => value * 2`
+	if expression.Kind == p.kinds.Identifier && p.at("=>") {
+		return p.parseSimpleArrowFunction(pos, expression, nil)
+	}
+
+	p.reScanGreater()
+	if p.token == tokenPunctuation && nativeAssignmentOperators[p.value] && p.isLeftHandSideExpression(expression) {
+		node := p.newNode(p.kinds.BinaryExpression, pos)
+		node.Left = expression
+		node.OperatorToken = p.parseTokenNode(p.operators[p.value])
+		node.Right = p.parseAssignmentExpression()
+		return p.finish(node)
+	}
+
+	return p.parseConditionalExpressionRest(pos, expression)
+}
+
+// Check if the expression may be assigned to, like an identifier or
+// a property access, rather than being an operation.
+func (p *nativeFileParser) isLeftHandSideExpression(expression *nativeNode) bool {
+	switch expression.Kind {
+	case p.kinds.BinaryExpression, p.kinds.ConditionalExpression, p.kinds.PrefixUnaryExpression,
+		p.kinds.PostfixUnaryExpression, p.kinds.ArrowFunction, p.kinds.AsExpression,
+		p.kinds.YieldExpression, p.kinds.AwaitExpression, p.kinds.TypeOfExpression,
+		p.kinds.VoidExpression, p.kinds.DeleteExpression, p.kinds.TypeAssertionExpression,
+		p.kinds.SpreadElement:
+		return false
+	}
+
+	return true
+}
+
+func (p *nativeFileParser) parseConditionalExpressionRest(pos int, condition *nativeNode) *nativeNode {
+	if !p.at("?") {
+		return condition
+	}
+
+	node := p.newNode(p.kinds.ConditionalExpression, pos)
+	node.QuestionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	p.parseAssignmentExpressionAllowIn()
+	p.expect(":")
+	p.parseAssignmentExpression()
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) isYieldExpression() bool {
+	if !p.atWord("yield") {
+		return false
+	}
+
+	if p.inYield {
+		return true
+	}
+
+	return p.lookAhead(func() bool {
+		p.next()
+		return !p.lineBreak && (p.token == tokenIdentifier || p.token == tokenString || p.token == tokenNumber)
+	})
+}
+
+func (p *nativeFileParser) parseYieldExpression() *nativeNode {
+	node := p.newNode(p.kinds.YieldExpression, p.fullStart)
+	p.next()
+
+	if !p.lineBreak && (p.at("*") || p.isStartOfExpression()) {
+		p.eat("*")
+		node.Expression = p.parseAssignmentExpression()
+	}
+
+	return p.finish(node)
+}
+
+// Check if the current token may start an expression.
+func (p *nativeFileParser) isStartOfExpression() bool {
+	switch p.token {
+	case tokenIdentifier:
+		return !nativeReservedWords[p.value] || nativeExpressionWords[p.value] || p.escaped
+
+	case tokenPrivateIdentifier, tokenString, tokenNumber, tokenBigInt, tokenTemplate, tokenRegularExpression:
+		return true
+
+	case tokenPunctuation:
+		switch p.value {
+		case "(", "[", "{", "+", "-", "~", "!", "++", "--", "<", "/", "/=", "@", "#":
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse an arrow function whose parameters are parenthesized, like
+// `(a, b) => a + b` or `async <T>(value: T) => value`. The head of
+// the function is parsed speculatively, as it may turn out to be a
+// parenthesized expression. Returns `nil` if there is no arrow function.
+func (p *nativeFileParser) tryParseArrowFunction() *nativeNode {
+	if p.atWord("async") && p.lookAhead(func() bool {
+		p.next()
+		if !p.isIdentifier() || p.lineBreak {
+			return false
+		}
+
+		p.next()
+		return p.at("=>")
+	}) {
+		pos := p.fullStart
+		modifiers := listOf(nativeNodes{p.parseTokenNode(p.kinds.AsyncKeyword)})
+		return p.parseSimpleArrowFunction(pos, p.parseIdentifier(), modifiers)
+	}
+
+	if !p.isStartOfParenthesizedArrowFunction() {
+		return nil
+	}
+
+	var node *nativeNode
+	if !p.tryParse(func() bool {
+		node = p.parseArrowFunctionHead()
+		return node != nil
+	}) {
+		return nil
+	}
+
+	async := hasModifier(node.Modifiers, p.kinds.AsyncKeyword)
+	node.Body = p.parseArrowFunctionBody(async)
+
+	return p.finish(node)
+}
+
+// Check if the current token may start an arrow function with
+// parenthesized parameters or type parameters.
+func (p *nativeFileParser) isStartOfParenthesizedArrowFunction() bool {
+	if p.atWord("async") {
+		return p.lookAhead(func() bool {
+			p.next()
+			return !p.lineBreak && (p.at("(") || p.at("<"))
+		})
+	}
+
+	if p.at("(") {
+		return true
+	}
+
+	if !p.at("<") {
+		return false
+	}
+
+	if !p.jsx {
+		return true
+	}
+
+	// in JSX files, `<T,>` and `<T extends U>` are type parameters,
+	// while anything else is an element
+	return p.lookAhead(func() bool {
+		p.next()
+		if p.atWord("const") {
+			p.next()
+		}
+
+		if !p.isIdentifier() {
+			return false
+		}
+
+		p.next()
+		if p.at(",") {
+			return true
+		}
+
+		if !p.atWord("extends") {
+			return false
+		}
+
+		p.next()
+		return !p.at("=") && !p.at(">") && !p.at("/")
+	})
+}
+
+// Parse the modifiers, type parameters, parameters and return type
+// of an arrow function, up to and including the `=>`. Returns `nil`
+// if this is not an arrow function.
+func (p *nativeFileParser) parseArrowFunctionHead() *nativeNode {
+	node := p.newNode(p.kinds.ArrowFunction, p.fullStart)
+
+	if p.atWord("async") {
+		node.Modifiers = listOf(nativeNodes{p.parseTokenNode(p.kinds.AsyncKeyword)})
+	}
+
+	if !p.at("(") && !p.at("<") {
+		return nil
+	}
+
+	async := node.Modifiers != nil
+	p.parseSignature(node, ":", false, async)
+
+	if !p.at("=>") {
+		return nil
+	}
+
+	p.next()
+	return node
+}
+
+// Parse an arrow function with a single parameter that is not
+// parenthesized, from the token following the `=>`.
+func (p *nativeFileParser) parseSimpleArrowFunction(pos int, identifier *nativeNode, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.ArrowFunction, pos)
+	node.Modifiers = modifiers
+
+	parameter := p.newNode(p.kinds.Parameter, identifier.pos)
+	parameter.Name = identifier
+	parameter.Pos, parameter.End = identifier.Pos, identifier.End
+	node.Parameters = listOf(nativeNodes{parameter})
+
+	p.expect("=>")
+	node.Body = p.parseArrowFunctionBody(modifiers != nil)
+
+	return p.finish(node)
+}
+
+// Parse the body of an arrow function, which is either a block or
+// an expression.
+func (p *nativeFileParser) parseArrowFunctionBody(async bool) *nativeNode {
+	if p.at("{") {
+		return p.parseFunctionBlock(false, async)
+	}
+
+	savedYield, savedAwait := p.inYield, p.inAwait
+	p.inYield, p.inAwait = false, async
+	defer func() {
+		p.inYield, p.inAwait = savedYield, savedAwait
+	}()
+
+	return p.parseAssignmentExpression()
+}
+
+// Parse the binary expressions of operators that bind tighter than
+// the given precedence.
+func (p *nativeFileParser) parseBinaryExpression(precedence int) *nativeNode {
+	pos := p.fullStart
+	return p.parseBinaryExpressionRest(precedence, p.parseUnaryExpression(), pos)
+}
+
+func (p *nativeFileParser) parseBinaryExpressionRest(precedence int, left *nativeNode, pos int) *nativeNode {
+	for {
+		p.reScanGreater()
+
+		operatorPrecedence := p.getBinaryOperatorPrecedence()
+		if operatorPrecedence <= 0 {
+			break
+		}
+
+		// `**` is right associative, the others are left associative
+		if operatorPrecedence < precedence || operatorPrecedence == precedence && !p.at("**") {
+			break
+		}
+
+		if p.atWord("in") && p.disallowIn {
+			break
+		}
+
+		if p.atWord("as") || p.atWord("satisfies") {
+			// `as` on the next line starts a new statement
+			if p.lineBreak {
+				break
+			}
+
+			node := p.newNode(p.kinds.AsExpression, pos)
+			p.next()
+			node.Expression = left
+			node.Type = p.parseType()
+			left = p.finish(node)
+			continue
+		}
+
+		node := p.newNode(p.kinds.BinaryExpression, pos)
+		node.Left = left
+		if p.token == tokenIdentifier {
+			node.OperatorToken = p.parseTokenNode(p.keywords[p.value])
+		} else {
+			node.OperatorToken = p.parseTokenNode(p.operators[p.value])
+		}
+
+		node.Right = p.parseBinaryExpression(operatorPrecedence)
+		left = p.finish(node)
+	}
+
+	return left
+}
+
+func (p *nativeFileParser) getBinaryOperatorPrecedence() int {
+	switch p.token {
+	case tokenIdentifier:
+		if p.escaped || p.value != "instanceof" && p.value != "in" && p.value != "as" && p.value != "satisfies" {
+			return 0
+		}
+
+	case tokenPunctuation:
+
+	default:
+		return 0
+	}
+
+	return nativePrecedence[p.value]
+}
+
+// Parse a unary expression, like `!value` or `typeof value`.
+func (p *nativeFileParser) parseUnaryExpression() *nativeNode {
+	pos := p.fullStart
+
+	if p.token == tokenPunctuation {
+		switch p.value {
+		case "+", "-", "~", "!":
+			node := p.newNode(p.kinds.PrefixUnaryExpression, pos)
+			p.next()
+			p.parseUnaryExpression()
+			return p.finish(node)
+
+		case "<":
+			if !p.jsx {
+				return p.parseTypeAssertion()
+			}
+		}
+	}
+
+	if p.token == tokenIdentifier && !p.escaped {
+		kind := 0
+		switch p.value {
+		case "delete":
+			kind = p.kinds.DeleteExpression
+
+		case "typeof":
+			kind = p.kinds.TypeOfExpression
+
+		case "void":
+			kind = p.kinds.VoidExpression
+
+		case "await":
+			if p.isAwaitExpression() {
+				kind = p.kinds.AwaitExpression
+			}
+		}
+
+		if kind != 0 {
+			node := p.newNode(kind, pos)
+			p.next()
+			node.Expression = p.parseUnaryExpression()
+			return p.finish(node)
+		}
+	}
+
+	expression := p.parseUpdateExpression()
+	if p.at("**") {
+		return p.parseBinaryExpressionRest(nativePrecedence["**"], expression, pos)
+	}
+
+	return expression
+}
+
+func (p *nativeFileParser) isAwaitExpression() bool {
+	if p.inAwait {
+		return true
+	}
+
+	// `await` outside of async functions, as in modules
+	return p.lookAhead(func() bool {
+		p.next()
+		return !p.lineBreak && (p.token == tokenIdentifier || p.token == tokenString || p.token == tokenNumber)
+	})
+}
+
+// Parse a type assertion, like `<string>value`, as allowed in `.ts` files.
+func (p *nativeFileParser) parseTypeAssertion() *nativeNode {
+	node := p.newNode(p.kinds.TypeAssertionExpression, p.fullStart)
+	p.expect("<")
+	node.Type = p.parseType()
+	p.expect(">")
+	node.Expression = p.parseUnaryExpression()
+
+	return p.finish(node)
+}
+
+// Parse a prefix or postfix increment or decrement, a JSX element, or
+// a left hand side expression.
+func (p *nativeFileParser) parseUpdateExpression() *nativeNode {
+	pos := p.fullStart
+
+	if p.at("++") || p.at("--") {
+		node := p.newNode(p.kinds.PrefixUnaryExpression, pos)
+		p.next()
+		p.parseLeftHandSideExpression()
+		return p.finish(node)
+	}
+
+	if p.jsx && p.at("<") && p.lookAhead(func() bool {
+		p.next()
+		return p.token == tokenIdentifier || p.at(">")
+	}) {
+		return p.parseJsxElementOrFragment(true)
+	}
+
+	expression := p.parseLeftHandSideExpression()
+	if (p.at("++") || p.at("--")) && !p.lineBreak {
+		node := p.newNode(p.kinds.PostfixUnaryExpression, pos)
+		p.next()
+		return p.finish(node)
+	}
+
+	return expression
+}
+
+// Parse a left hand side expression, like a call or a property access.
+func (p *nativeFileParser) parseLeftHandSideExpression() *nativeNode {
+	pos := p.fullStart
+
+	var expression *nativeNode
+	switch {
+	case p.atWord("import") && p.nextIs("(", false):
+		// dynamic imports, like `import('./module')`
+		expression = p.parseTokenNode(p.kinds.ImportKeyword)
+
+	case p.atWord("import") && p.nextIs(".", false):
+		// `import.meta`
+		node := p.newNode(p.kinds.MetaProperty, pos)
+		p.next()
+		p.next()
+		node.Name = p.parseIdentifierName()
+		expression = p.finish(node)
+
+	case p.atWord("super"):
+		expression = p.parseTokenNode(p.kinds.SuperKeyword)
+
+	default:
+		expression = p.parsePrimaryExpression()
+	}
+
+	return p.parseCallExpressionRest(pos, p.parseMemberExpressionRest(pos, expression, true))
+}
+
+// Parse the property accesses, element accesses, non-null assertions
+// and tagged templates following an expression.
+func (p *nativeFileParser) parseMemberExpressionRest(pos int, expression *nativeNode, allowOptionalChain bool) *nativeNode {
+	for {
+		var questionDotToken bool
+		isPropertyAccess := false
+
+		if allowOptionalChain && p.at("?.") && p.lookAhead(func() bool {
+			p.next()
+			return p.token == tokenIdentifier || p.token == tokenPrivateIdentifier || p.at("[") || p.token == tokenTemplate
+		}) {
+			p.next()
+			questionDotToken = true
+			isPropertyAccess = p.token == tokenIdentifier || p.token == tokenPrivateIdentifier
+		} else {
+			isPropertyAccess = p.eat(".")
+		}
+
+		if isPropertyAccess {
+			node := p.newNode(p.kinds.PropertyAccessExpression, pos)
+			node.Expression = expression
+
+			if p.token == tokenPrivateIdentifier {
+				node.Name = p.parsePropertyName()
+			} else {
+				node.Name = p.parseIdentifierName()
+			}
+
+			expression = p.finish(node)
+			continue
+		}
+
+		if p.eat("[") {
+			node := p.newNode(p.kinds.ElementAccessExpression, pos)
+			node.Expression = expression
+			if p.at("]") {
+				p.error("An element access expression should take an argument.", 1011)
+			} else {
+				p.parseExpressionAllowIn()
+			}
+
+			p.expect("]")
+			expression = p.finish(node)
+			continue
+		}
+
+		if p.token == tokenTemplate {
+			expression = p.parseTaggedTemplate(pos, nil)
+			continue
+		}
+
+		if !questionDotToken && p.at("!") && !p.lineBreak {
+			node := p.newNode(p.kinds.NonNullExpression, pos)
+			p.next()
+			node.Expression = expression
+			expression = p.finish(node)
+			continue
+		}
+
+		return expression
+	}
+}
+
+// Parse the calls following an expression, like `a(b)`, `a?.(b)` or
+// `a<T>(b)`, along with any member accesses that follow.
+func (p *nativeFileParser) parseCallExpressionRest(pos int, expression *nativeNode) *nativeNode {
+	for {
+		expression = p.parseMemberExpressionRest(pos, expression, true)
+
+		questionDotToken := false
+		if p.at("?.") {
+			p.next()
+			questionDotToken = true
+		}
+
+		var typeArguments *nativeNodes
+		if p.at("<") || p.at("<<") {
+			typeArguments = p.tryParseTypeArgumentsInExpression()
+			if typeArguments == nil && !questionDotToken {
+				return expression
+			}
+
+			if typeArguments != nil && p.token == tokenTemplate {
+				expression = p.parseTaggedTemplate(pos, typeArguments)
+				continue
+			}
+		}
+
+		if typeArguments != nil || p.at("(") {
+			node := p.newNode(p.kinds.CallExpression, pos)
+			node.Expression = expression
+			node.TypeArguments = typeArguments
+			node.Arguments = p.parseArguments()
+			expression = p.finish(node)
+			continue
+		}
+
+		if questionDotToken {
+			// `?.` followed by nothing that can be accessed
+			p.error("Identifier expected.", 1003)
+			node := p.newNode(p.kinds.PropertyAccessExpression, pos)
+			node.Expression = expression
+			node.Name = p.newMissingIdentifier()
+			expression = p.finish(node)
+		}
+
+		return expression
+	}
+}
+
+// Parse the type arguments of a call, like `<T>` in `a<T>()`, which
+// are only type arguments when followed by the arguments or a template.
+// Returns `nil` if there are no type arguments.
+func (p *nativeFileParser) tryParseTypeArgumentsInExpression() *nativeNodes {
+	var typeArguments *nativeNodes
+
+	if !p.tryParse(func() bool {
+		if p.at("<<") {
+			return false
+		}
+
+		typeArguments = p.parseTypeArguments()
+		return p.at("(") || p.token == tokenTemplate
+	}) {
+		return nil
+	}
+
+	return typeArguments
+}
+
+// Parse the arguments of a call, like `(a, ...b)`.
+func (p *nativeFileParser) parseArguments() *nativeNodes {
+	var arguments nativeNodes
+	p.expect("(")
+
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+
+	p.parseDelimitedList(")", func() {
+		arguments = append(arguments, p.parseArgumentOrArrayLiteralElement())
+	})
+
+	p.disallowIn = savedDisallowIn
+	p.expect(")")
+
+	return listOf(arguments)
+}
+
+func (p *nativeFileParser) parseArgumentOrArrayLiteralElement() *nativeNode {
+	if p.at("...") {
+		node := p.newNode(p.kinds.SpreadElement, p.fullStart)
+		p.next()
+		node.Expression = p.parseAssignmentExpression()
+		return p.finish(node)
+	}
+
+	if p.at(",") {
+		return p.finish(p.newNode(p.kinds.OmittedExpression, p.fullStart))
+	}
+
+	return p.parseAssignmentExpression()
+}
+
+// Parse a primary expression, like an identifier, a literal, or a
+// parenthesized expression.
+func (p *nativeFileParser) parsePrimaryExpression() *nativeNode {
+	pos := p.fullStart
+
+	switch p.token {
+	case tokenString, tokenNumber, tokenBigInt:
+		return p.parseLiteral()
+
+	case tokenTemplate:
+		return p.parseTemplate()
+
+	case tokenPrivateIdentifier:
+		return p.parsePropertyName()
+
+	case tokenIdentifier:
+		if p.escaped {
+			break
+		}
+
+		switch p.value {
+		case "this", "super", "null", "true", "false":
+			return p.parseTokenNode(p.keywords[p.value])
+
+		case "function":
+			return p.parseFunctionExpression(pos, nil)
+
+		case "class":
+			return p.parseClass(p.kinds.ClassExpression, pos, nil)
+
+		case "new":
+			return p.parseNewExpression()
+
+		case "async":
+			if p.nextIsWord("function", true) {
+				modifiers := listOf(nativeNodes{p.parseTokenNode(p.kinds.AsyncKeyword)})
+				return p.parseFunctionExpression(pos, modifiers)
+			}
+		}
+
+	case tokenPunctuation:
+		switch p.value {
+		case "(":
+			node := p.newNode(p.kinds.ParenthesizedExpression, pos)
+			p.next()
+			node.Expression = p.parseExpressionAllowIn()
+			p.expect(")")
+			return p.finish(node)
+
+		case "[":
+			return p.parseArrayLiteral()
+
+		case "{":
+			return p.parseObjectLiteral()
+
+		case "/", "/=":
+			p.reScanSlash()
+			return p.parseLiteral()
+
+		case "@":
+			p.parseDecorators()
+			return p.parseClass(p.kinds.ClassExpression, pos, p.parseModifiers(false))
+		}
+	}
+
+	if p.isIdentifier() {
+		return p.parseIdentifierName()
+	}
+
+	p.error("Expression expected.", 1109)
+	return p.newMissingIdentifier()
+}
+
+// Parse a template literal, with or without substitutions.
+func (p *nativeFileParser) parseTemplate() *nativeNode {
+	pos := p.fullStart
+
+	if p.templateTail {
+		node := p.newNode(p.kinds.FirstTemplateToken, pos)
+		node.Text = p.value
+		p.next()
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.TemplateExpression, pos)
+	for !p.templateTail {
+		p.next()
+		p.parseExpressionAllowIn()
+
+		if !p.at("}") {
+			p.error("'}' expected.", 1005)
+			return p.finish(node)
+		}
+
+		p.reScanTemplate()
+	}
+
+	p.next()
+	return p.finish(node)
+}
+
+// Parse a tagged template, like css`color: red;`.
+func (p *nativeFileParser) parseTaggedTemplate(pos int, typeArguments *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.TaggedTemplateExpression, pos)
+	node.TypeArguments = typeArguments
+	p.parseTemplate()
+
+	return p.finish(node)
+}
+
+// Parse a function expression, like `function (props) {}`.
+func (p *nativeFileParser) parseFunctionExpression(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.FunctionExpression, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("function")
+	generator := p.eat("*")
+	async := modifiers != nil
+
+	if p.isIdentifier() || p.atWord("yield") || p.atWord("await") {
+		node.Name = p.parseIdentifierName()
+	}
+
+	p.parseSignature(node, ":", generator, async)
+	node.Body = p.parseFunctionBlock(generator, async)
+
+	return p.attachJsDoc(p.finish(node))
+}
+
+// Parse `new` expressions, like `new Map<string, number>()`, or the
+// `new.target` meta property.
+func (p *nativeFileParser) parseNewExpression() *nativeNode {
+	pos := p.fullStart
+	p.expectWord("new")
+
+	if p.eat(".") {
+		node := p.newNode(p.kinds.MetaProperty, pos)
+		node.Name = p.parseIdentifierName()
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.NewExpression, pos)
+	expressionPos := p.fullStart
+	node.Expression = p.parseMemberExpressionRest(expressionPos, p.parsePrimaryExpression(), false)
+
+	if p.at("<") {
+		node.TypeArguments = p.tryParseTypeArgumentsInExpression()
+	}
+
+	if p.at("(") {
+		node.Arguments = p.parseArguments()
+	}
+
+	return p.finish(node)
+}
+
+// Parse an array literal, like `[a, , ...b]`.
+func (p *nativeFileParser) parseArrayLiteral() *nativeNode {
+	node := p.newNode(p.kinds.ArrayLiteralExpression, p.fullStart)
+	p.expect("[")
+
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+
+	var elements nativeNodes
+	p.parseDelimitedList("]", func() {
+		elements = append(elements, p.parseArgumentOrArrayLiteralElement())
+	})
+
+	p.disallowIn = savedDisallowIn
+	node.Elements = listOf(elements)
+	p.expect("]")
+
+	return p.finish(node)
+}
+
+// Parse an object literal, like `{ a: 1, b, ...c, d() {} }`.
+func (p *nativeFileParser) parseObjectLiteral() *nativeNode {
+	node := p.newNode(p.kinds.ObjectLiteralExpression, p.fullStart)
+	p.expect("{")
+
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+
+	var properties nativeNodes
+	p.parseDelimitedList("}", func() {
+		properties = append(properties, p.attachJsDoc(p.parseObjectLiteralElement()))
+	})
+
+	p.disallowIn = savedDisallowIn
+	node.Properties = listOf(properties)
+	p.expect("}")
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseObjectLiteralElement() *nativeNode {
+	pos := p.fullStart
+
+	if p.at("...") {
+		node := p.newNode(p.kinds.SpreadAssignment, pos)
+		p.next()
+		node.Expression = p.parseAssignmentExpression()
+		return p.finish(node)
+	}
+
+	p.parseDecorators()
+	modifiers := p.parseModifiers(false)
+
+	if node := p.tryParseAccessor(pos, modifiers); node != nil {
+		return node
+	}
+
+	generator := p.eat("*")
+	tokenIsIdentifier := p.isIdentifier()
+	name := p.parsePropertyName()
+
+	var questionToken *nativeNode
+	if p.at("?") {
+		questionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	} else if p.at("!") {
+		p.next()
+	}
+
+	if generator || p.at("(") || p.at("<") {
+		node := p.newNode(p.kinds.MethodDeclaration, pos)
+		node.Modifiers = modifiers
+		node.Name = name
+		node.QuestionToken = questionToken
+
+		async := hasModifier(modifiers, p.kinds.AsyncKeyword)
+		p.parseSignature(node, ":", generator, async)
+		node.Body = p.parseFunctionBlockOrSemicolon(generator, async)
+
+		return p.finish(node)
+	}
+
+	// shorthand properties, like `{ a }` or `{ a = 1 }` in patterns
+	if tokenIsIdentifier && !p.at(":") {
+		node := p.newNode(p.kinds.ShorthandPropertyAssignment, pos)
+		node.Name = name
+		node.QuestionToken = questionToken
+
+		if p.eat("=") {
+			p.parseAssignmentExpression()
+		}
+
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.PropertyAssignment, pos)
+	node.Modifiers = modifiers
+	node.Name = name
+	node.QuestionToken = questionToken
+
+	p.expect(":")
+	node.Initializer = p.parseAssignmentExpression()
+
+	return p.finish(node)
+}
+
+// Parse a JSX element, a self closing element or a fragment. In an
+// expression, the token following the element is scanned as usual,
+// while within the children of an element it is scanned as JSX.
+func (p *nativeFileParser) parseJsxElementOrFragment(inExpressionContext bool) *nativeNode {
+	pos := p.fullStart
+	opening := p.parseJsxOpeningElementOrFragment(inExpressionContext)
+
+	switch opening.Kind {
+	case p.kinds.JsxOpeningElement:
+		node := p.newNode(p.kinds.JsxElement, pos)
+		node.OpeningElement = opening
+		node.Children = p.parseJsxChildren()
+		node.ClosingElement = p.parseJsxClosingElement(p.kinds.JsxClosingElement, inExpressionContext)
+		return p.finish(node)
+
+	case p.kinds.JsxOpeningFragment:
+		node := p.newNode(p.kinds.JsxFragment, pos)
+		node.Children = p.parseJsxChildren()
+		p.parseJsxClosingElement(p.kinds.JsxClosingFragment, inExpressionContext)
+		return p.finish(node)
+	}
+
+	return opening
+}
+
+func (p *nativeFileParser) parseJsxOpeningElementOrFragment(inExpressionContext bool) *nativeNode {
+	pos := p.fullStart
+	p.expect("<")
+
+	if p.at(">") {
+		node := p.newNode(p.kinds.JsxOpeningFragment, pos)
+		p.scanJsxChild()
+		return p.finish(node)
+	}
+
+	tagName := p.parseJsxElementName()
+
+	var typeArguments *nativeNodes
+	if p.at("<") {
+		typeArguments = p.parseTypeArguments()
+	}
+
+	attributes := p.newNode(p.kinds.JsxAttributes, p.fullStart)
+	var properties nativeNodes
+	for !p.at("/") && !p.at(">") && p.token != tokenEndOfFile {
+		start := p.start
+		properties = append(properties, p.parseJsxAttribute())
+
+		if p.start == start {
+			p.next()
+		}
+	}
+
+	attributes.Properties = listOf(properties)
+	p.finish(attributes)
+
+	if p.at(">") {
+		node := p.newNode(p.kinds.JsxOpeningElement, pos)
+		node.TagName = tagName
+		node.TypeArguments = typeArguments
+		node.Attributes = attributes
+		p.scanJsxChild()
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.JsxSelfClosingElement, pos)
+	node.TagName = tagName
+	node.TypeArguments = typeArguments
+	node.Attributes = attributes
+
+	p.expect("/")
+	if inExpressionContext {
+		p.expect(">")
+	} else if p.at(">") {
+		p.scanJsxChild()
+	} else {
+		p.expect(">")
+	}
+
+	return p.finish(node)
+}
+
+// Parse the name of a JSX element, like `div`, `my-element` or `Menu.Item`.
+func (p *nativeFileParser) parseJsxElementName() *nativeNode {
+	pos := p.fullStart
+	p.scanJsxIdentifier()
+
+	var expression *nativeNode
+	if p.atWord("this") {
+		expression = p.parseTokenNode(p.kinds.ThisKeyword)
+	} else {
+		expression = p.parseIdentifierName()
+	}
+
+	for p.eat(".") {
+		node := p.newNode(p.kinds.PropertyAccessExpression, pos)
+		node.Expression = expression
+		node.Name = p.parseIdentifierName()
+		expression = p.finish(node)
+	}
+
+	return expression
+}
+
+// Parse an attribute of a JSX element, like `label="Save"`,
+// `disabled`, `onClick={handler}` or `{...props}`.
+func (p *nativeFileParser) parseJsxAttribute() *nativeNode {
+	pos := p.fullStart
+
+	if p.at("{") {
+		node := p.newNode(p.kinds.JsxSpreadAttribute, pos)
+		p.next()
+		p.expect("...")
+		node.Expression = p.parseExpressionAllowIn()
+		p.expect("}")
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.JsxAttribute, pos)
+	p.scanJsxIdentifier()
+	node.Name = p.parseIdentifierName()
+
+	if p.at("=") {
+		p.scanJsxAttributeValue()
+
+		switch {
+		case p.token == tokenString:
+			node.Initializer = p.parseLiteral()
+
+		case p.at("{"):
+			node.Initializer = p.parseJsxExpression(true)
+
+		case p.at("<"):
+			node.Initializer = p.parseJsxElementOrFragment(true)
+
+		default:
+			p.error("'{' expected.", 1005)
+		}
+	}
+
+	return p.finish(node)
+}
+
+// Parse an expression within braces, as a child or an attribute.
+func (p *nativeFileParser) parseJsxExpression(inExpressionContext bool) *nativeNode {
+	node := p.newNode(p.kinds.JsxExpression, p.fullStart)
+	p.expect("{")
+
+	if !p.at("}") {
+		if p.at("...") {
+			node.DotDotDotToken = p.parseTokenNode(p.kinds.DotDotDotToken)
+		}
+
+		node.Expression = p.parseExpressionAllowIn()
+	}
+
+	if inExpressionContext {
+		p.expect("}")
+	} else if p.at("}") {
+		p.scanJsxChild()
+	} else {
+		p.expect("}")
+	}
+
+	return p.finish(node)
+}
+
+// Parse the children of an element, up to the `</` of the closing element.
+func (p *nativeFileParser) parseJsxChildren() *nativeNodes {
+	var children nativeNodes
+
+	for {
+		switch {
+		case p.token == tokenJsxText:
+			node := p.newNode(p.kinds.JsxText, p.fullStart)
+			node.Text = p.value
+			p.scanJsxChild()
+			children = append(children, p.finish(node))
+
+		case p.at("{"):
+			children = append(children, p.parseJsxExpression(false))
+
+		case p.at("<"):
+			children = append(children, p.parseJsxElementOrFragment(false))
+
+		case p.at("</"):
+			return listOf(children)
+
+		default:
+			p.error("Expected corresponding closing tag for JSX fragment.", 17015)
+			return listOf(children)
+		}
+	}
+}
+
+// Parse the closing element, like `</div>`, or the closing fragment `</>`.
+func (p *nativeFileParser) parseJsxClosingElement(kind int, inExpressionContext bool) *nativeNode {
+	node := p.newNode(kind, p.fullStart)
+	if !p.expect("</") {
+		return p.finish(node)
+	}
+
+	if kind == p.kinds.JsxClosingElement {
+		node.TagName = p.parseJsxElementName()
+	}
+
+	if inExpressionContext {
+		p.expect(">")
+	} else if p.at(">") {
+		p.scanJsxChild()
+	} else {
+		p.expect(">")
+	}
+
+	return p.finish(node)
+}
@@ -0,0 +1,1003 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// This file contains the functions of the native parser that parse
+// JSDoc comments. They follow the JSDoc parser of Typescript closely,
+// so that comments and tags are read the same way by both parsers.
+
+// The states of reading the text of a JSDoc comment
+const (
+	jsDocBeginningOfLine = iota
+	jsDocSawAsterisk
+	jsDocSavingComments
+	jsDocSavingBackticks
+)
+
+// The kinds of tags parsed by `parseParameterOrPropertyTag`
+const (
+	jsDocProperty = 1 << iota
+	jsDocParameter
+	jsDocCallbackParameter
+)
+
+// Attach the JSDoc comments preceding the node to it.
+func (p *nativeFileParser) attachJsDoc(node *nativeNode) *nativeNode {
+	if node == nil || p.inJsDoc {
+		return node
+	}
+
+	// like Typescript, the comments on the same line as the previous
+	// token only count for some nodes
+	allComments := node.pos == 0
+	switch node.Kind {
+	case p.kinds.Parameter, p.kinds.TypeParameter, p.kinds.FunctionExpression, p.kinds.ArrowFunction,
+		p.kinds.ParenthesizedExpression, p.kinds.VariableDeclaration:
+		allComments = true
+	}
+
+	var jsDocs nativeNodes
+	for _, comment := range p.getCommentRanges(node.pos, allComments) {
+		start, end := comment[0], comment[1]
+		if end-start > 4 && strings.HasPrefix(p.text[start:], "/**") && p.text[start+3] != '/' {
+			jsDocs = append(jsDocs, p.parseJsDocComment(start, end))
+		}
+	}
+
+	if jsDocs != nil {
+		node.JsDoc = listOf(jsDocs)
+	}
+
+	return node
+}
+
+// Return the start and end of the comments from the given offset up
+// to the next token. Unless all comments are asked for, only those
+// after the first line break are returned.
+func (p *nativeFileParser) getCommentRanges(pos int, allComments bool) [][2]int {
+	var comments [][2]int
+	collecting := allComments || pos == 0
+
+	if pos == 0 && strings.HasPrefix(p.text, "#!") {
+		pos = p.findLineEnd(pos)
+	}
+
+	for pos < len(p.text) {
+		char, size := utf8.DecodeRuneInString(p.text[pos:])
+
+		switch {
+		case char == '\r' || char == '\n' || char == 0x2028 || char == 0x2029:
+			pos += size
+			collecting = true
+
+		case isNativeWhitespace(char):
+			pos += size
+
+		case strings.HasPrefix(p.text[pos:], "//"):
+			end := p.findLineEnd(pos)
+			if collecting {
+				comments = append(comments, [2]int{pos, end})
+			}
+
+			pos = end
+
+		case strings.HasPrefix(p.text[pos:], "/*"):
+			end := len(p.text)
+			if index := strings.Index(p.text[pos+2:], "*/"); index >= 0 {
+				end = pos + 2 + index + 2
+			}
+
+			if collecting {
+				comments = append(comments, [2]int{pos, end})
+			}
+
+			pos = end
+
+		default:
+			return comments
+		}
+	}
+
+	return comments
+}
+
+// Parse the JSDoc comment between the given offsets, which include
+// the `/**` and `*/` of the comment.
+func (p *nativeFileParser) parseJsDocComment(start int, end int) *nativeNode {
+	state := p.saveState()
+	savedSpeculating := p.speculating
+	defer func() {
+		p.restoreState(state)
+		p.speculating = savedSpeculating
+		p.inJsDoc = false
+	}()
+
+	// errors never abort parsing the comment, as they are ignored
+	p.speculating = 0
+	p.inJsDoc = true
+
+	contentEnd := end - 2
+	if contentEnd < start+3 {
+		contentEnd = start + 3
+	}
+
+	p.pos = start + 3
+	p.end = contentEnd
+
+	return p.parseJsDocCommentWorker(start, end)
+}
+
+func (p *nativeFileParser) parseJsDocCommentWorker(start int, end int) *nativeNode {
+	node := p.newNode(p.kinds.JSDocComment, start)
+
+	var comments []string
+	var tags nativeNodes
+
+	state := jsDocSawAsterisk
+	indent := start - (strings.LastIndexByte(p.text[:start], '\n') + 1) + 4
+	margin, hasMargin := 0, false
+
+	pushComment := func(text string) {
+		if !hasMargin || margin == 0 {
+			margin, hasMargin = indent, true
+		}
+
+		comments = append(comments, text)
+		indent += len(text)
+	}
+
+	p.nextJsDoc()
+	for p.token == tokenJsDocWhitespace {
+		p.nextJsDoc()
+	}
+
+	if p.token == tokenJsDocNewLine {
+		state = jsDocBeginningOfLine
+		indent = 0
+		p.nextJsDoc()
+	}
+
+loop:
+	for {
+		switch {
+		case p.at("@"):
+			if state == jsDocBeginningOfLine || state == jsDocSawAsterisk {
+				comments = removeTrailingWhitespace(comments)
+				tags = append(tags, p.parseJsDocTag(indent))
+
+				// the tag ends before the next `@`, or the end of the comment
+				state = jsDocBeginningOfLine
+				hasMargin = false
+			} else {
+				pushComment(p.tokenText())
+			}
+
+		case p.token == tokenJsDocNewLine:
+			comments = append(comments, p.tokenText())
+			state = jsDocBeginningOfLine
+			indent = 0
+
+		case p.at("*"):
+			if state == jsDocSawAsterisk || state == jsDocSavingComments {
+				// only the first asterisk on a line is part of the margin
+				state = jsDocSavingComments
+				pushComment("*")
+			} else {
+				state = jsDocSawAsterisk
+				indent++
+			}
+
+		case p.token == tokenJsDocWhitespace:
+			whitespace := p.tokenText()
+			if state == jsDocSavingComments {
+				comments = append(comments, whitespace)
+			} else if hasMargin && indent+len(whitespace) > margin {
+				comments = append(comments, sliceJsDocText(whitespace, margin-indent))
+			}
+
+			indent += len(whitespace)
+
+		case p.token == tokenEndOfFile:
+			break loop
+
+		default:
+			state = jsDocSavingComments
+			pushComment(p.tokenText())
+		}
+
+		p.nextJsDoc()
+	}
+
+	node.Comment = strings.Join(removeLeadingNewlines(removeTrailingWhitespace(comments)), "")
+	if tags != nil {
+		node.Tags = listOf(tags)
+	}
+
+	return p.finishAt(node, end)
+}
+
+// Return the text from the given offset, which counts from the end
+// of the text when negative, like `slice` in Javascript.
+func sliceJsDocText(text string, from int) string {
+	if from < 0 {
+		from += len(text)
+		if from < 0 {
+			from = 0
+		}
+	}
+
+	if from > len(text) {
+		return ""
+	}
+
+	return text[from:]
+}
+
+func removeLeadingNewlines(comments []string) []string {
+	for len(comments) > 0 && (comments[0] == "\n" || comments[0] == "\r\n" || comments[0] == "\r") {
+		comments = comments[1:]
+	}
+
+	return comments
+}
+
+func removeTrailingWhitespace(comments []string) []string {
+	for len(comments) > 0 && strings.TrimSpace(comments[len(comments)-1]) == "" {
+		comments = comments[:len(comments)-1]
+	}
+
+	return comments
+}
+
+// Move to the next token within the JSDoc comment.
+func (p *nativeFileParser) nextJsDoc() {
+	p.scanJsDoc()
+}
+
+// Check if only whitespace and line breaks remain in the comment.
+func (p *nativeFileParser) isNextNonWhitespaceTokenEndOfFile() bool {
+	return p.lookAhead(func() bool {
+		for {
+			p.nextJsDoc()
+			if p.token == tokenEndOfFile {
+				return true
+			}
+
+			if p.token != tokenJsDocWhitespace && p.token != tokenJsDocNewLine {
+				return false
+			}
+		}
+	})
+}
+
+func (p *nativeFileParser) skipWhitespace() {
+	if (p.token == tokenJsDocWhitespace || p.token == tokenJsDocNewLine) && p.isNextNonWhitespaceTokenEndOfFile() {
+		return
+	}
+
+	for p.token == tokenJsDocWhitespace || p.token == tokenJsDocNewLine {
+		p.nextJsDoc()
+	}
+}
+
+// Skip the whitespace and the asterisks starting lines, returning the
+// indentation of the last line if a line break was skipped.
+func (p *nativeFileParser) skipWhitespaceOrAsterisk() string {
+	if (p.token == tokenJsDocWhitespace || p.token == tokenJsDocNewLine) && p.isNextNonWhitespaceTokenEndOfFile() {
+		return ""
+	}
+
+	precedingLineBreak := p.lineBreak
+	seenLineBreak := false
+	indentText := ""
+
+	for (precedingLineBreak && p.at("*")) || p.token == tokenJsDocWhitespace || p.token == tokenJsDocNewLine {
+		indentText += p.tokenText()
+
+		if p.token == tokenJsDocNewLine {
+			precedingLineBreak = true
+			seenLineBreak = true
+			indentText = ""
+		} else if p.at("*") {
+			precedingLineBreak = false
+		}
+
+		p.nextJsDoc()
+	}
+
+	if seenLineBreak {
+		return indentText
+	}
+
+	return ""
+}
+
+// Parse a tag, like `@param {string} label the label`, starting at
+// the `@` of the current token.
+func (p *nativeFileParser) parseJsDocTag(margin int) *nativeNode {
+	start := p.start
+	p.nextJsDoc()
+
+	tagName := p.parseJsDocIdentifierName()
+	indentText := p.skipWhitespaceOrAsterisk()
+
+	var node *nativeNode
+	switch tagName.EscapedText {
+	case "param", "arg", "argument":
+		return p.parseParameterOrPropertyTag(start, tagName, jsDocParameter, margin)
+
+	case "typedef":
+		return p.parseTypedefTag(start, tagName, margin, indentText)
+
+	case "return", "returns":
+		node = p.newNode(p.kinds.JSDocReturnTag, start)
+		node.TypeExpression = p.tryParseTypeExpression()
+
+	case "type":
+		node = p.newNode(p.kinds.JSDocTypeTag, start)
+		node.TypeExpression = p.parseJsDocTypeExpression(true)
+
+	case "this":
+		node = p.newNode(p.kinds.JSDocThisTag, start)
+		node.TypeExpression = p.parseJsDocTypeExpression(true)
+		p.skipWhitespace()
+
+	case "enum":
+		node = p.newNode(p.kinds.JSDocEnumTag, start)
+		node.TypeExpression = p.parseJsDocTypeExpression(true)
+		p.skipWhitespace()
+
+	case "template":
+		node = p.newNode(p.kinds.JSDocTemplateTag, start)
+		p.parseTemplateTagTypeParameters()
+
+	case "augments", "extends":
+		node = p.newNode(p.kinds.JSDocAugmentsTag, start)
+		p.tryParseTypeExpression()
+
+	case "implements":
+		node = p.newNode(p.kinds.JSDocImplementsTag, start)
+		p.tryParseTypeExpression()
+
+	case "author":
+		node = p.newNode(p.kinds.JSDocAuthorTag, start)
+
+	case "deprecated":
+		node = p.newNode(p.kinds.JSDocDeprecatedTag, start)
+
+	case "class", "constructor":
+		node = p.newNode(p.kinds.JSDocClassTag, start)
+
+	case "public":
+		node = p.newNode(p.kinds.JSDocPublicTag, start)
+
+	case "private":
+		node = p.newNode(p.kinds.JSDocPrivateTag, start)
+
+	case "protected":
+		node = p.newNode(p.kinds.JSDocProtectedTag, start)
+
+	case "readonly":
+		node = p.newNode(p.kinds.JSDocReadonlyTag, start)
+
+	case "override":
+		node = p.newNode(p.kinds.JSDocOverrideTag, start)
+
+	case "see":
+		node = p.newNode(p.kinds.JSDocSeeTag, start)
+
+	default:
+		node = p.newNode(p.kinds.FirstJSDocTagNode, start)
+	}
+
+	node.TagName = tagName
+	node.Comment = p.parseTrailingTagComments(start, p.fullStart, margin, indentText)
+
+	return p.finish(node)
+}
+
+// Parse an identifier within the comment, which may contain dashes.
+func (p *nativeFileParser) parseJsDocIdentifierName() *nativeNode {
+	if p.token != tokenIdentifier {
+		return p.finish(p.newNode(p.kinds.Identifier, p.fullStart))
+	}
+
+	node := p.newNode(p.kinds.Identifier, p.start)
+	node.EscapedText = escapeNativeName(p.value)
+	p.finishAt(node, p.pos)
+	p.nextJsDoc()
+
+	return node
+}
+
+// Parse a name that may be qualified, like `props.label`.
+func (p *nativeFileParser) parseJsDocEntityName() *nativeNode {
+	pos := p.fullStart
+	entity := p.parseJsDocIdentifierName()
+
+	if p.eat("[") {
+		p.expect("]")
+	}
+
+	for p.eat(".") {
+		name := p.parseJsDocIdentifierName()
+		if p.eat("[") {
+			p.expect("]")
+		}
+
+		node := p.newNode(p.kinds.FirstNode, pos)
+		node.Left = entity
+		node.Right = name
+		entity = p.finish(node)
+	}
+
+	return entity
+}
+
+// Parse the comments following a tag, up to the next tag or the end
+// of the comment.
+func (p *nativeFileParser) parseTrailingTagComments(pos int, end int, margin int, indentText string) string {
+	if indentText == "" {
+		margin += end - pos
+	}
+
+	return p.parseTagComments(margin, sliceJsDocText(indentText, margin), true)
+}
+
+func (p *nativeFileParser) parseTagComments(indent int, initialMargin string, hasInitialMargin bool) string {
+	var comments []string
+	state := jsDocBeginningOfLine
+	previousWhitespace := true
+	margin, hasMargin := 0, false
+
+	pushComment := func(text string) {
+		if !hasMargin || margin == 0 {
+			margin, hasMargin = indent, true
+		}
+
+		comments = append(comments, text)
+		indent += len(text)
+	}
+
+	if hasInitialMargin {
+		// the margin is the indentation of the first line of the comment
+		if initialMargin != "" {
+			pushComment(initialMargin)
+		}
+
+		state = jsDocSawAsterisk
+	}
+
+loop:
+	for {
+		switch {
+		case p.token == tokenJsDocNewLine:
+			state = jsDocBeginningOfLine
+			comments = append(comments, p.tokenText())
+			indent = 0
+
+		case p.at("@"):
+			if state == jsDocSavingBackticks || state == jsDocSavingComments && (!previousWhitespace || p.lookAhead(p.isNextJsDocTokenWhitespace)) {
+				// an `@` in the middle of text, like in an email address
+				comments = append(comments, p.tokenText())
+				break
+			}
+
+			// rewind to the `@`, which starts the next tag
+			p.pos = p.start
+			p.token = tokenUnknown
+			p.value = ""
+			break loop
+
+		case p.token == tokenEndOfFile:
+			break loop
+
+		case p.token == tokenJsDocWhitespace:
+			whitespace := p.tokenText()
+			if state == jsDocSavingComments || state == jsDocSavingBackticks {
+				pushComment(whitespace)
+			} else {
+				if hasMargin && indent+len(whitespace) > margin {
+					comments = append(comments, sliceJsDocText(whitespace, margin-indent))
+				}
+
+				indent += len(whitespace)
+			}
+
+		case p.at("{"):
+			state = jsDocSavingComments
+			pushComment(p.tokenText())
+
+		case p.at("`"):
+			if state == jsDocSavingBackticks {
+				state = jsDocSavingComments
+			} else {
+				state = jsDocSavingBackticks
+			}
+
+			pushComment(p.tokenText())
+
+		case p.at("*") && state == jsDocBeginningOfLine:
+			state = jsDocSawAsterisk
+			indent++
+
+		default:
+			if state != jsDocSavingBackticks {
+				state = jsDocSavingComments
+			}
+
+			pushComment(p.tokenText())
+		}
+
+		previousWhitespace = p.token == tokenJsDocWhitespace
+		p.nextJsDoc()
+	}
+
+	return strings.Join(removeTrailingWhitespace(removeLeadingNewlines(comments)), "")
+}
+
+func (p *nativeFileParser) isNextJsDocTokenWhitespace() bool {
+	p.nextJsDoc()
+	return p.token == tokenJsDocWhitespace || p.token == tokenJsDocNewLine
+}
+
+// Parse the type of a tag, like `{string}`, if any.
+func (p *nativeFileParser) tryParseTypeExpression() *nativeNode {
+	p.skipWhitespaceOrAsterisk()
+	if p.at("{") {
+		return p.parseJsDocTypeExpression(false)
+	}
+
+	return nil
+}
+
+// Parse the type of a tag within braces, which may be left out for
+// some tags, like `@type string`.
+func (p *nativeFileParser) parseJsDocTypeExpression(mayOmitBraces bool) *nativeNode {
+	node := p.newNode(p.kinds.FirstJSDocNode, p.fullStart)
+
+	hasBrace := p.at("{")
+	if hasBrace {
+		p.next()
+	} else if !mayOmitBraces {
+		p.expect("{")
+	}
+
+	savedInJsDocType := p.inJsDocType
+	p.inJsDocType = true
+	node.Type = p.parseJsDocType()
+	p.inJsDocType = savedInJsDocType
+
+	if !mayOmitBraces || hasBrace {
+		if p.at("}") {
+			p.nextJsDoc()
+		} else {
+			p.error("'}' expected.", 1005)
+		}
+	}
+
+	return p.finish(node)
+}
+
+// Parse a JSDoc type, which may be variadic, like `...string`, or
+// optional, like `string=`.
+func (p *nativeFileParser) parseJsDocType() *nativeNode {
+	pos := p.fullStart
+	hasDotDotDot := p.eat("...")
+
+	typ := p.parseTypeOrTypePredicate()
+	if hasDotDotDot {
+		node := p.newNode(p.kinds.JSDocVariadicType, pos)
+		node.Type = typ
+		typ = p.finish(node)
+	}
+
+	if p.at("=") {
+		node := p.newNode(p.kinds.JSDocOptionalType, pos)
+		p.next()
+		node.Type = typ
+		return p.finish(node)
+	}
+
+	return typ
+}
+
+// Parse a JSDoc function type, like `function(string, number): void`.
+func (p *nativeFileParser) parseJsDocFunctionType() *nativeNode {
+	node := p.newNode(p.kinds.JSDocFunctionType, p.fullStart)
+	p.next()
+
+	var parameters nativeNodes
+	p.expect("(")
+	p.parseDelimitedList(")", func() {
+		parameters = append(parameters, p.parseJsDocParameter())
+	})
+	p.expect(")")
+	node.Parameters = listOf(parameters)
+
+	if p.eat(":") {
+		node.Type = p.parseTypeOrTypePredicate()
+	}
+
+	return p.finish(node)
+}
+
+// Parse a parameter of a JSDoc function type, which only has a type,
+// unless it is the `this:` or `new:` parameter.
+func (p *nativeFileParser) parseJsDocParameter() *nativeNode {
+	node := p.newNode(p.kinds.Parameter, p.fullStart)
+
+	if p.atWord("this") || p.atWord("new") {
+		node.Name = p.parseIdentifierName()
+		p.expect(":")
+	}
+
+	node.Type = p.parseJsDocType()
+	return p.finish(node)
+}
+
+// Parse a `@param` or `@property` tag, like `@param {string} label the label`
+// or `@param [size='md'] the size`.
+func (p *nativeFileParser) parseParameterOrPropertyTag(start int, tagName *nativeNode, target int, margin int) *nativeNode {
+	typeExpression := p.tryParseTypeExpression()
+	isNameFirst := typeExpression == nil
+	p.skipWhitespaceOrAsterisk()
+
+	name, isBracketed := p.parseBracketNameInPropertyAndParamTag()
+	indentText := p.skipWhitespaceOrAsterisk()
+
+	if isNameFirst {
+		typeExpression = p.tryParseTypeExpression()
+	}
+
+	comment := p.parseTrailingTagComments(start, p.fullStart, margin, indentText)
+
+	if target != jsDocCallbackParameter {
+		if nestedTypeLiteral := p.parseNestedTypeLiteral(typeExpression, name, target, margin); nestedTypeLiteral != nil {
+			typeExpression = nestedTypeLiteral
+		}
+	}
+
+	kind := p.kinds.JSDocParameterTag
+	if target == jsDocProperty {
+		kind = p.kinds.JSDocPropertyTag
+	}
+
+	node := p.newNode(kind, start)
+	node.TagName = tagName
+	node.Name = name
+	node.IsBracketed = isBracketed
+	node.TypeExpression = typeExpression
+	node.Comment = comment
+
+	return p.finish(node)
+}
+
+// Parse the name of a `@param` or `@property` tag, which is within
+// brackets when optional, like `[size='md']`.
+func (p *nativeFileParser) parseBracketNameInPropertyAndParamTag() (*nativeNode, bool) {
+	isBracketed := p.at("[")
+	if isBracketed {
+		p.nextJsDoc()
+		p.skipWhitespace()
+	}
+
+	isBackquoted := p.at("`")
+	if isBackquoted {
+		p.nextJsDoc()
+	}
+
+	name := p.parseJsDocEntityName()
+
+	if isBackquoted && p.at("`") {
+		p.nextJsDoc()
+	}
+
+	if isBracketed {
+		p.skipWhitespace()
+
+		// the default value, which is not kept
+		if p.eat("=") {
+			p.parseExpression()
+		}
+
+		p.expect("]")
+	}
+
+	return name, isBracketed
+}
+
+// Check if the type is `Object`, `object` or an array of them, which
+// may have its properties described by the tags following the tag.
+func (p *nativeFileParser) isObjectOrObjectArrayTypeReference(node *nativeNode) bool {
+	if node == nil {
+		return false
+	}
+
+	switch node.Kind {
+	case p.kinds.ObjectKeyword:
+		return true
+
+	case p.kinds.ArrayType:
+		return p.isObjectOrObjectArrayTypeReference(node.ElementType)
+
+	case p.kinds.TypeReference:
+		return node.TypeName != nil && node.TypeName.Kind == p.kinds.Identifier &&
+			node.TypeName.EscapedText == "Object" && node.TypeArguments == nil
+	}
+
+	return false
+}
+
+// Parse the tags describing the properties of an object parameter,
+// like `@param {string} props.label` following `@param {Object} props`,
+// into a type literal.
+func (p *nativeFileParser) parseNestedTypeLiteral(typeExpression *nativeNode, name *nativeNode, target int, margin int) *nativeNode {
+	if typeExpression == nil || !p.isObjectOrObjectArrayTypeReference(typeExpression.Type) {
+		return nil
+	}
+
+	pos := p.fullStart
+
+	var children nativeNodes
+	for {
+		var child *nativeNode
+		if !p.tryParse(func() bool {
+			child = p.parseChildParameterOrPropertyTag(target, margin, name)
+			return child != nil
+		}) {
+			break
+		}
+
+		if child.Kind == p.kinds.JSDocParameterTag || child.Kind == p.kinds.JSDocPropertyTag {
+			children = append(children, child)
+		}
+	}
+
+	if children == nil {
+		return nil
+	}
+
+	literal := p.newNode(p.kinds.JSDocTypeLiteral, pos)
+	literal.JsDocPropertyTags = listOf(children)
+
+	node := p.newNode(p.kinds.FirstJSDocNode, pos)
+	node.Type = p.finish(literal)
+
+	return p.finish(node)
+}
+
+// Parse the next tag if it describes a property of the given name,
+// returning `nil` otherwise.
+func (p *nativeFileParser) parseChildParameterOrPropertyTag(target int, margin int, name *nativeNode) *nativeNode {
+	canParseTag := true
+	seenAsterisk := false
+
+	for {
+		p.nextJsDoc()
+
+		switch {
+		case p.at("@"):
+			if canParseTag {
+				child := p.tryParseChildTag(target, margin)
+				if child != nil && (child.Kind == p.kinds.JSDocParameterTag || child.Kind == p.kinds.JSDocPropertyTag) &&
+					target != jsDocCallbackParameter && name != nil &&
+					(child.Name.Kind == p.kinds.Identifier || !isSameNativeEntityName(name, child.Name.Left)) {
+					return nil
+				}
+
+				return child
+			}
+
+			seenAsterisk = false
+
+		case p.token == tokenJsDocNewLine:
+			canParseTag = true
+			seenAsterisk = false
+
+		case p.at("*"):
+			if seenAsterisk {
+				canParseTag = false
+			}
+
+			seenAsterisk = true
+
+		case p.token == tokenIdentifier:
+			if _, ok := p.keywords[p.value]; !ok {
+				canParseTag = false
+			}
+
+		case p.token == tokenEndOfFile:
+			return nil
+		}
+	}
+}
+
+func isSameNativeEntityName(a *nativeNode, b *nativeNode) bool {
+	for a.Left != nil && b.Left != nil {
+		if a.Right.EscapedText != b.Right.EscapedText {
+			return false
+		}
+
+		a, b = a.Left, b.Left
+	}
+
+	return a.Left == nil && b.Left == nil && a.EscapedText == b.EscapedText
+}
+
+func (p *nativeFileParser) tryParseChildTag(target int, margin int) *nativeNode {
+	start := p.start
+	p.nextJsDoc()
+
+	tagName := p.parseJsDocIdentifierName()
+	p.skipWhitespace()
+
+	var tagTarget int
+	switch tagName.EscapedText {
+	case "type":
+		if target != jsDocProperty {
+			return nil
+		}
+
+		node := p.newNode(p.kinds.JSDocTypeTag, start)
+		node.TagName = tagName
+		node.TypeExpression = p.parseJsDocTypeExpression(true)
+		return p.finish(node)
+
+	case "prop", "property":
+		tagTarget = jsDocProperty
+
+	case "arg", "argument", "param":
+		tagTarget = jsDocParameter | jsDocCallbackParameter
+
+	default:
+		return nil
+	}
+
+	if target&tagTarget == 0 {
+		return nil
+	}
+
+	return p.parseParameterOrPropertyTag(start, tagName, target, margin)
+}
+
+// Parse a `@typedef` tag, whose properties may be described by the
+// `@property` tags following it.
+func (p *nativeFileParser) parseTypedefTag(start int, tagName *nativeNode, margin int, indentText string) *nativeNode {
+	node := p.newNode(p.kinds.JSDocTypedefTag, start)
+	node.TagName = tagName
+
+	typeExpression := p.tryParseTypeExpression()
+	p.skipWhitespaceOrAsterisk()
+
+	name := p.parseJsDocTypeNameWithNamespace()
+	p.skipWhitespace()
+	comment := p.parseTagComments(margin, "", false)
+
+	hasChildren := false
+	if typeExpression == nil || p.isObjectOrObjectArrayTypeReference(typeExpression.Type) {
+		var childTypeTag *nativeNode
+		var propertyTags nativeNodes
+
+		for {
+			var child *nativeNode
+			if !p.tryParse(func() bool {
+				child = p.parseChildParameterOrPropertyTag(jsDocProperty, margin, nil)
+				return child != nil
+			}) {
+				break
+			}
+
+			hasChildren = true
+			if child.Kind == p.kinds.JSDocTypeTag {
+				if childTypeTag != nil {
+					break
+				}
+
+				childTypeTag = child
+			} else {
+				propertyTags = append(propertyTags, child)
+			}
+		}
+
+		if hasChildren {
+			if childTypeTag != nil && childTypeTag.TypeExpression != nil && !p.isObjectOrObjectArrayTypeReference(childTypeTag.TypeExpression.Type) {
+				typeExpression = childTypeTag.TypeExpression
+			} else {
+				literal := p.newNode(p.kinds.JSDocTypeLiteral, start)
+				literal.JsDocPropertyTags = listOf(propertyTags)
+				typeExpression = p.finish(literal)
+			}
+		}
+	}
+
+	end := p.fullStart
+	if !hasChildren && comment == "" {
+		switch {
+		case name != nil:
+			end = name.end
+
+		case typeExpression != nil:
+			end = typeExpression.end
+
+		default:
+			end = tagName.end
+		}
+	}
+
+	if comment == "" {
+		comment = p.parseTrailingTagComments(start, end, margin, indentText)
+	}
+
+	node.TypeExpression = typeExpression
+	node.Name = name
+	node.Comment = comment
+
+	return p.finishAt(node, end)
+}
+
+// Parse the name of a `@typedef` tag, returning the last name when it
+// is within a namespace, like `components.CardProps`.
+func (p *nativeFileParser) parseJsDocTypeNameWithNamespace() *nativeNode {
+	if p.token != tokenIdentifier {
+		return nil
+	}
+
+	name := p.parseJsDocIdentifierName()
+	if p.eat(".") {
+		return p.parseJsDocTypeNameWithNamespace()
+	}
+
+	return name
+}
+
+// Parse the type parameters of a `@template` tag, like `@template {string} T, U`.
+func (p *nativeFileParser) parseTemplateTagTypeParameters() {
+	if p.at("{") {
+		p.parseJsDocTypeExpression(false)
+	}
+
+	for {
+		p.skipWhitespace()
+
+		isBracketed := p.at("[")
+		if isBracketed {
+			p.nextJsDoc()
+			p.skipWhitespace()
+		}
+
+		p.parseJsDocIdentifierName()
+
+		if isBracketed {
+			p.skipWhitespace()
+			if p.at("=") {
+				p.nextJsDoc()
+				p.parseJsDocType()
+			}
+
+			p.expect("]")
+		}
+
+		p.skipWhitespace()
+		if !p.at(",") {
+			return
+		}
+
+		p.nextJsDoc()
+	}
+}
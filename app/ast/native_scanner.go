@@ -0,0 +1,937 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// This file contains the scanner of the native parser, which splits
+// the source text into tokens. Like the Typescript scanner, the parser
+// asks the scanner to scan some tokens differently depending on where
+// it is, such as regular expressions, template literals and JSX.
+
+// The kinds of tokens scanned
+type nativeToken int
+
+const (
+	tokenEndOfFile         nativeToken = iota
+	tokenIdentifier                    // identifiers as well as keywords
+	tokenPrivateIdentifier             // names starting with `#`
+	tokenPunctuation                   // punctuation and operators
+	tokenString
+	tokenNumber
+	tokenBigInt
+	tokenRegularExpression
+	tokenTemplate // a template literal, or a part of one up to a substitution
+	tokenJsxText
+	tokenUnknown
+)
+
+// The kinds of tokens scanned within JSDoc comments
+const (
+	tokenJsDocWhitespace nativeToken = iota + 100
+	tokenJsDocNewLine
+)
+
+type nativeScanner struct {
+	text      string
+	end       int // the offset at which scanning stops
+	fullStart int // the offset at which the current token starts, including the leading trivia
+	start     int // the offset at which the current token starts
+	pos       int // the offset at which the current token ends
+
+	token        nativeToken
+	value        string // the name of an identifier, the text of punctuation, or the value of a literal
+	escaped      bool   // whether the identifier contains unicode escapes
+	extended     bool   // whether the identifier contains extended unicode escapes, like `\u{61}`
+	lineBreak    bool   // whether there is a line break before the current token
+	templateTail bool   // whether the template token ends the template literal
+
+	onError func(message string, code int, start int, length int)
+}
+
+// Scan the next token, skipping over any whitespace and comments.
+func (s *nativeScanner) scan() {
+	s.fullStart = s.pos
+	s.lineBreak = false
+	s.skipTrivia()
+	s.scanToken()
+}
+
+// Skip over whitespace and comments, noting any line breaks.
+func (s *nativeScanner) skipTrivia() {
+	for s.pos < s.end {
+		char, size := s.charAt(s.pos)
+
+		switch {
+		case char == '\n' || char == '\r' || char == 0x2028 || char == 0x2029:
+			s.lineBreak = true
+			s.pos += size
+
+		case isNativeWhitespace(char):
+			s.pos += size
+
+		case char == '/' && s.pos+1 < s.end && s.text[s.pos+1] == '/':
+			s.pos = s.findLineEnd(s.pos + 2)
+
+		case char == '/' && s.pos+1 < s.end && s.text[s.pos+1] == '*':
+			end := strings.Index(s.text[s.pos+2:s.end], "*/")
+			if end < 0 {
+				s.onError("'*/' expected.", 1010, s.end, 0)
+				if strings.ContainsAny(s.text[s.pos:s.end], "\n\r") {
+					s.lineBreak = true
+				}
+
+				s.pos = s.end
+				break
+			}
+
+			if strings.ContainsAny(s.text[s.pos:s.pos+2+end], "\n\r\u2028\u2029") {
+				s.lineBreak = true
+			}
+
+			s.pos += end + 4
+
+		case char == '#' && s.pos == 0 && s.pos+1 < s.end && s.text[1] == '!':
+			s.pos = s.findLineEnd(2)
+
+		default:
+			return
+		}
+	}
+}
+
+// Return the offset of the line break ending the line.
+func (s *nativeScanner) findLineEnd(pos int) int {
+	for pos < s.end {
+		char, size := s.charAt(pos)
+		if char == '\n' || char == '\r' || char == 0x2028 || char == 0x2029 {
+			break
+		}
+
+		pos += size
+	}
+
+	return pos
+}
+
+// Return the character at the given offset, along with its size.
+func (s *nativeScanner) charAt(pos int) (rune, int) {
+	char := rune(s.text[pos])
+	if char < utf8.RuneSelf {
+		return char, 1
+	}
+
+	return utf8.DecodeRuneInString(s.text[pos:s.end])
+}
+
+// Check the byte at the given offset, which is `0` past the end.
+func (s *nativeScanner) byteAt(pos int) byte {
+	if pos < s.end {
+		return s.text[pos]
+	}
+
+	return 0
+}
+
+// Scan the token at the current offset.
+func (s *nativeScanner) scanToken() {
+	s.start = s.pos
+	s.value = ""
+	s.escaped = false
+	s.extended = false
+	s.templateTail = false
+
+	if s.pos >= s.end {
+		s.token = tokenEndOfFile
+		return
+	}
+
+	char, size := s.charAt(s.pos)
+
+	switch {
+	case char == '"' || char == '\'':
+		s.scanString(byte(char))
+		return
+
+	case char == '`':
+		s.pos++
+		s.scanTemplate()
+		return
+
+	case char >= '0' && char <= '9', char == '.' && isNativeDigit(s.byteAt(s.pos+1)):
+		s.scanNumber()
+		return
+
+	case char == '#' && s.pos+1 < s.end:
+		s.pos++
+		if s.scanIdentifierParts(true) {
+			s.token = tokenPrivateIdentifier
+			s.value = "#" + s.value
+			return
+		}
+
+		s.pos = s.start + 1
+		s.token = tokenPunctuation
+		s.value = "#"
+		return
+
+	case isNativeIdentifierStart(char) || char == '\\':
+		if s.scanIdentifierParts(true) {
+			s.token = tokenIdentifier
+			return
+		}
+
+		s.pos = s.start + size
+		s.onError("Invalid character.", 1127, s.start, size)
+		s.token = tokenUnknown
+		return
+	}
+
+	if punctuation := s.scanPunctuation(); punctuation != "" {
+		s.pos += len(punctuation)
+		s.token = tokenPunctuation
+		s.value = punctuation
+		return
+	}
+
+	s.pos += size
+	s.onError("Invalid character.", 1127, s.start, size)
+	s.token = tokenUnknown
+	s.value = string(char)
+}
+
+// The punctuation, longest first for each leading character. The
+// `>` is always scanned on its own, and the parser rescans it
+// where it may be part of a longer operator.
+var nativePunctuation = map[byte][]string{
+	'{': {"{"}, '}': {"}"}, '(': {"("}, ')': {")"}, '[': {"["}, ']': {"]"},
+	';': {";"}, ',': {","}, '~': {"~"}, '@': {"@"}, ':': {":"}, '>': {">"},
+	'.': {"...", "."},
+	'?': {"??=", "??", "?.", "?"},
+	'<': {"<<=", "<<", "<=", "<"},
+	'=': {"===", "==", "=>", "="},
+	'!': {"!==", "!=", "!"},
+	'+': {"++", "+=", "+"},
+	'-': {"--", "-=", "-"},
+	'*': {"**=", "**", "*=", "*"},
+	'/': {"/=", "/"},
+	'%': {"%=", "%"},
+	'&': {"&&=", "&&", "&=", "&"},
+	'|': {"||=", "||", "|=", "|"},
+	'^': {"^=", "^"},
+}
+
+func (s *nativeScanner) scanPunctuation() string {
+	for _, punctuation := range nativePunctuation[s.text[s.pos]] {
+		if !strings.HasPrefix(s.text[s.pos:s.end], punctuation) {
+			continue
+		}
+
+		// `?.` followed by a digit is a conditional, like `a?.5:1`
+		if punctuation == "?." && isNativeDigit(s.byteAt(s.pos+2)) {
+			continue
+		}
+
+		return punctuation
+	}
+
+	return ""
+}
+
+// Scan an identifier, or the name of a private identifier, from the
+// current offset. Returns `false` if there is no identifier.
+func (s *nativeScanner) scanIdentifierParts(allowEscapes bool) bool {
+	var sb strings.Builder
+	start := s.pos
+
+	for s.pos < s.end {
+		char, size := s.charAt(s.pos)
+
+		if char == '\\' && allowEscapes {
+			escaped, ok := s.scanIdentifierEscape()
+			if !ok {
+				break
+			}
+
+			sb.WriteRune(escaped)
+			continue
+		}
+
+		if s.pos == start && !isNativeIdentifierStart(char) || s.pos > start && !isNativeIdentifierPart(char) {
+			break
+		}
+
+		sb.WriteRune(char)
+		s.pos += size
+	}
+
+	s.value = sb.String()
+	return s.value != ""
+}
+
+// Scan a unicode escape in an identifier, like `\u0061` or `\u{61}`.
+func (s *nativeScanner) scanIdentifierEscape() (rune, bool) {
+	if s.byteAt(s.pos+1) != 'u' {
+		return 0, false
+	}
+
+	pos := s.pos + 2
+	value, end, extended := s.scanUnicodeEscape(pos)
+	if end < 0 {
+		return 0, false
+	}
+
+	s.pos = end
+	s.escaped = true
+	s.extended = s.extended || extended
+
+	return value, true
+}
+
+// Scan the digits of a unicode escape after the `\u`, returning the
+// value and the offset after the escape, or `-1` if it is invalid.
+func (s *nativeScanner) scanUnicodeEscape(pos int) (rune, int, bool) {
+	if s.byteAt(pos) == '{' {
+		end := strings.IndexByte(s.text[pos:s.end], '}')
+		if end < 2 {
+			return 0, -1, false
+		}
+
+		value, err := strconv.ParseUint(s.text[pos+1:pos+end], 16, 32)
+		if err != nil || value > unicode.MaxRune {
+			return 0, -1, false
+		}
+
+		return rune(value), pos + end + 1, true
+	}
+
+	if pos+4 > s.end {
+		return 0, -1, false
+	}
+
+	value, err := strconv.ParseUint(s.text[pos:pos+4], 16, 32)
+	if err != nil {
+		return 0, -1, false
+	}
+
+	return rune(value), pos + 4, false
+}
+
+// Scan a string literal, resolving the escapes in its value.
+func (s *nativeScanner) scanString(quote byte) {
+	var sb strings.Builder
+	s.pos++
+
+	for {
+		if s.pos >= s.end {
+			s.onError("Unterminated string literal.", 1002, s.start, s.pos-s.start)
+			break
+		}
+
+		char := s.text[s.pos]
+		if char == quote {
+			s.pos++
+			break
+		}
+
+		if char == '\n' || char == '\r' {
+			s.onError("Unterminated string literal.", 1002, s.start, s.pos-s.start)
+			break
+		}
+
+		if char == '\\' {
+			s.scanEscape(&sb, false)
+			continue
+		}
+
+		sb.WriteByte(char)
+		s.pos++
+	}
+
+	s.token = tokenString
+	s.value = sb.String()
+}
+
+// Scan an escape sequence in a string or template literal.
+func (s *nativeScanner) scanEscape(sb *strings.Builder, template bool) {
+	s.pos++
+	if s.pos >= s.end {
+		return
+	}
+
+	char := s.text[s.pos]
+	s.pos++
+
+	switch char {
+	case 'n':
+		sb.WriteByte('\n')
+
+	case 't':
+		sb.WriteByte('\t')
+
+	case 'r':
+		sb.WriteByte('\r')
+
+	case 'b':
+		sb.WriteByte('\b')
+
+	case 'f':
+		sb.WriteByte('\f')
+
+	case 'v':
+		sb.WriteByte('\v')
+
+	case '0':
+		if !isNativeDigit(s.byteAt(s.pos)) {
+			sb.WriteByte(0)
+			break
+		}
+
+		sb.WriteByte('0')
+
+	case 'x':
+		if s.pos+2 <= s.end {
+			if value, err := strconv.ParseUint(s.text[s.pos:s.pos+2], 16, 8); err == nil {
+				sb.WriteRune(rune(value))
+				s.pos += 2
+				break
+			}
+		}
+
+		sb.WriteByte('x')
+
+	case 'u':
+		value, end, _ := s.scanUnicodeEscape(s.pos)
+		if end < 0 {
+			sb.WriteByte('u')
+			break
+		}
+
+		s.pos = end
+
+		// join the surrogate pairs, like `\uD83D\uDE00`
+		if utf16IsHighSurrogate(value) && s.byteAt(s.pos) == '\\' && s.byteAt(s.pos+1) == 'u' {
+			if low, lowEnd, _ := s.scanUnicodeEscape(s.pos + 2); lowEnd >= 0 && utf16IsLowSurrogate(low) {
+				value = (value-0xD800)<<10 + (low - 0xDC00) + 0x10000
+				s.pos = lowEnd
+			}
+		}
+
+		sb.WriteRune(value)
+
+	case '\r':
+		// line continuation
+		if s.byteAt(s.pos) == '\n' {
+			s.pos++
+		}
+
+	case '\n':
+		// line continuation
+
+	default:
+		// other characters, including multi-byte ones, stand for themselves
+		s.pos--
+		char, size := s.charAt(s.pos)
+		if char == 0x2028 || char == 0x2029 {
+			s.pos += size
+			break
+		}
+
+		sb.WriteRune(char)
+		s.pos += size
+	}
+}
+
+func utf16IsHighSurrogate(char rune) bool {
+	return char >= 0xD800 && char <= 0xDBFF
+}
+
+func utf16IsLowSurrogate(char rune) bool {
+	return char >= 0xDC00 && char <= 0xDFFF
+}
+
+// Scan a template literal, or the part of one, starting after the
+// backtick or the `}` ending a substitution. The token ends either
+// at the closing backtick or at the `${` starting a substitution.
+func (s *nativeScanner) scanTemplate() {
+	var sb strings.Builder
+
+	for {
+		if s.pos >= s.end {
+			s.onError("Unterminated template literal.", 1160, s.start, s.pos-s.start)
+			s.templateTail = true
+			break
+		}
+
+		char := s.text[s.pos]
+		if char == '`' {
+			s.pos++
+			s.templateTail = true
+			break
+		}
+
+		if char == '$' && s.byteAt(s.pos+1) == '{' {
+			s.pos += 2
+			break
+		}
+
+		if char == '\\' {
+			s.scanEscape(&sb, true)
+			continue
+		}
+
+		// line breaks are normalized to `\n` in the value
+		if char == '\r' {
+			sb.WriteByte('\n')
+			s.pos++
+			if s.byteAt(s.pos) == '\n' {
+				s.pos++
+			}
+			continue
+		}
+
+		sb.WriteByte(char)
+		s.pos++
+	}
+
+	s.token = tokenTemplate
+	s.value = sb.String()
+}
+
+// Rescan the `}` of the current token as the continuation of the
+// template literal whose substitution it ends.
+func (s *nativeScanner) reScanTemplate() {
+	s.pos = s.start + 1
+	s.scanTemplate()
+}
+
+// Scan a numeric literal, normalizing its value the way Typescript
+// does, such that `0x10` has the value `16`.
+func (s *nativeScanner) scanNumber() {
+	start := s.pos
+
+	if s.text[s.pos] == '0' && s.pos+1 < s.end {
+		base := 0
+		switch s.text[s.pos+1] {
+		case 'x', 'X':
+			base = 16
+
+		case 'o', 'O':
+			base = 8
+
+		case 'b', 'B':
+			base = 2
+		}
+
+		if base != 0 {
+			s.pos += 2
+			for s.pos < s.end && (isNativeHexDigit(s.text[s.pos]) || s.text[s.pos] == '_') {
+				s.pos++
+			}
+
+			digits := strings.ReplaceAll(s.text[start+2:s.pos], "_", "")
+			s.finishNumber(formatNativeInteger(digits, base))
+			return
+		}
+	}
+
+	fraction := false
+	exponent := false
+	s.scanDigits()
+
+	if s.byteAt(s.pos) == '.' {
+		fraction = true
+		s.pos++
+		s.scanDigits()
+	}
+
+	if char := s.byteAt(s.pos); char == 'e' || char == 'E' {
+		next := s.byteAt(s.pos + 1)
+		if isNativeDigit(next) || (next == '+' || next == '-') && isNativeDigit(s.byteAt(s.pos+2)) {
+			exponent = true
+			s.pos += 2
+			s.scanDigits()
+		}
+	}
+
+	digits := strings.ReplaceAll(s.text[start:s.pos], "_", "")
+	if !fraction && !exponent {
+		// legacy octal literals like `0777`
+		if len(digits) > 1 && digits[0] == '0' && strings.Trim(digits, "01234567") == "" {
+			s.finishNumber(formatNativeInteger(digits[1:], 8))
+			return
+		}
+
+		s.finishNumber(digits)
+		return
+	}
+
+	value, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		s.finishNumber(digits)
+		return
+	}
+
+	s.finishNumber(formatNativeNumber(value))
+}
+
+func (s *nativeScanner) scanDigits() {
+	for s.pos < s.end && (isNativeDigit(s.text[s.pos]) || s.text[s.pos] == '_') {
+		s.pos++
+	}
+}
+
+// Finish scanning a number, which may be a big integer like `10n`.
+func (s *nativeScanner) finishNumber(value string) {
+	s.token = tokenNumber
+	s.value = value
+
+	if s.byteAt(s.pos) == 'n' {
+		s.pos++
+		s.token = tokenBigInt
+		s.value = value + "n"
+	}
+
+	if s.pos < s.end {
+		if char, _ := s.charAt(s.pos); isNativeIdentifierStart(char) {
+			s.onError("An identifier or keyword cannot immediately follow a numeric literal.", 1351, s.pos, 1)
+		}
+	}
+}
+
+// Format the digits of an integer in the given base as a decimal
+// number, the way Javascript converts it to a string.
+func formatNativeInteger(digits string, base int) string {
+	value, err := strconv.ParseUint(digits, base, 64)
+	if err != nil {
+		return digits
+	}
+
+	if value < 1<<53 {
+		return strconv.FormatUint(value, 10)
+	}
+
+	return formatNativeNumber(float64(value))
+}
+
+// Format the number the way Javascript converts it to a string.
+func formatNativeNumber(value float64) string {
+	if value == 0 {
+		return "0"
+	}
+
+	abs := value
+	if abs < 0 {
+		abs = -abs
+	}
+
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	text := strconv.FormatFloat(value, 'g', -1, 64)
+	mantissa, exponent, _ := strings.Cut(text, "e")
+	exponent = strings.TrimLeft(exponent[1:], "0")
+
+	return mantissa + "e" + text[len(mantissa)+1:len(mantissa)+2] + exponent
+}
+
+// Rescan the `>` of the current token as part of a longer operator,
+// like `>=` or `>>>`, where binary operators are expected.
+func (s *nativeScanner) reScanGreater() {
+	if s.token != tokenPunctuation || s.value != ">" {
+		return
+	}
+
+	for _, operator := range []string{">>>=", ">>>", ">>=", ">>", ">="} {
+		if strings.HasPrefix(s.text[s.start:s.end], operator) {
+			s.pos = s.start + len(operator)
+			s.value = operator
+			return
+		}
+	}
+}
+
+// Rescan the `/` or `/=` of the current token as a regular expression,
+// where an expression is expected.
+func (s *nativeScanner) reScanSlash() {
+	if s.token != tokenPunctuation || (s.value != "/" && s.value != "/=") {
+		return
+	}
+
+	pos := s.start + 1
+	inClass := false
+
+	for {
+		if pos >= s.end || s.text[pos] == '\n' || s.text[pos] == '\r' {
+			s.onError("Unterminated regular expression literal.", 1161, s.start, pos-s.start)
+			break
+		}
+
+		char := s.text[pos]
+		if char == '\\' {
+			pos += 2
+			continue
+		}
+
+		pos++
+		if char == '/' && !inClass {
+			break
+		}
+
+		if char == '[' {
+			inClass = true
+		} else if char == ']' {
+			inClass = false
+		}
+	}
+
+	// the flags
+	for pos < s.end {
+		char, size := s.charAt(pos)
+		if !isNativeIdentifierPart(char) {
+			break
+		}
+
+		pos += size
+	}
+
+	if pos > s.end {
+		pos = s.end
+	}
+
+	s.pos = pos
+	s.token = tokenRegularExpression
+	s.value = s.text[s.start:pos]
+}
+
+// Scan the next token within the children of a JSX element, which is
+// either text, or the `{`, `<` or `</` starting another child or the
+// closing element.
+func (s *nativeScanner) scanJsxChild() {
+	s.fullStart = s.pos
+	s.start = s.pos
+	s.lineBreak = false
+	s.escaped = false
+
+	if s.pos >= s.end {
+		s.token = tokenEndOfFile
+		s.value = ""
+		return
+	}
+
+	switch s.text[s.pos] {
+	case '{':
+		s.pos++
+		s.token = tokenPunctuation
+		s.value = "{"
+		return
+
+	case '<':
+		s.pos++
+		s.token = tokenPunctuation
+		s.value = "<"
+		if s.byteAt(s.pos) == '/' {
+			s.pos++
+			s.value = "</"
+		}
+		return
+	}
+
+	for s.pos < s.end && s.text[s.pos] != '{' && s.text[s.pos] != '<' {
+		s.pos++
+	}
+
+	s.token = tokenJsxText
+	s.value = s.text[s.start:s.pos]
+}
+
+// Extend the identifier of the current token with the dashes and
+// identifier parts that may follow, as allowed in JSX names like
+// `aria-label`.
+func (s *nativeScanner) scanJsxIdentifier() {
+	if s.token != tokenIdentifier {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s.value)
+
+	for s.pos < s.end {
+		if s.text[s.pos] == '-' {
+			sb.WriteByte('-')
+			s.pos++
+			continue
+		}
+
+		start := s.pos
+		if !s.scanIdentifierPartsAfterStart() {
+			s.pos = start
+			break
+		}
+
+		sb.WriteString(s.value)
+	}
+
+	s.value = sb.String()
+}
+
+// Scan identifier parts, which may also start with digits.
+func (s *nativeScanner) scanIdentifierPartsAfterStart() bool {
+	start := s.pos
+	for s.pos < s.end {
+		char, size := s.charAt(s.pos)
+		if !isNativeIdentifierPart(char) {
+			break
+		}
+
+		s.pos += size
+	}
+
+	s.value = s.text[start:s.pos]
+	return s.pos > start
+}
+
+// Scan the value of a JSX attribute, which is either a string that
+// has no escapes, or any other token.
+func (s *nativeScanner) scanJsxAttributeValue() {
+	s.fullStart = s.pos
+	s.lineBreak = false
+	s.skipTrivia()
+
+	if s.pos < s.end && (s.text[s.pos] == '"' || s.text[s.pos] == '\'') {
+		s.start = s.pos
+		quote := s.text[s.pos]
+
+		end := strings.IndexByte(s.text[s.pos+1:s.end], quote)
+		if end < 0 {
+			s.onError("Unterminated string literal.", 1002, s.start, s.end-s.start)
+			s.pos = s.end
+			s.value = s.text[s.start+1 : s.end]
+		} else {
+			s.pos += end + 2
+			s.value = s.text[s.start+1 : s.pos-1]
+		}
+
+		s.token = tokenString
+		return
+	}
+
+	s.scanToken()
+}
+
+// Scan the next token within a JSDoc comment, where whitespace and
+// line breaks are tokens of their own, and identifiers may contain
+// dashes.
+func (s *nativeScanner) scanJsDoc() {
+	s.fullStart = s.pos
+	s.start = s.pos
+	s.lineBreak = false
+	s.escaped = false
+	s.value = ""
+
+	if s.pos >= s.end {
+		s.token = tokenEndOfFile
+		return
+	}
+
+	char, size := s.charAt(s.pos)
+
+	switch {
+	case char == ' ' || char == '\t' || char == '\v' || char == '\f':
+		for s.pos < s.end {
+			char, size := s.charAt(s.pos)
+			if char == '\n' || char == '\r' || !isNativeWhitespace(char) {
+				break
+			}
+
+			s.pos += size
+		}
+
+		s.token = tokenJsDocWhitespace
+
+	case char == '\r' || char == '\n':
+		s.pos++
+		if char == '\r' && s.byteAt(s.pos) == '\n' {
+			s.pos++
+		}
+
+		s.lineBreak = true
+		s.token = tokenJsDocNewLine
+
+	case strings.ContainsRune("@*{}[]<>=,.`", char):
+		s.pos++
+		s.token = tokenPunctuation
+
+	case isNativeIdentifierStart(char):
+		for s.pos < s.end {
+			char, size := s.charAt(s.pos)
+			if !isNativeIdentifierPart(char) && char != '-' {
+				break
+			}
+
+			s.pos += size
+		}
+
+		s.token = tokenIdentifier
+
+	default:
+		s.pos += size
+		s.token = tokenUnknown
+	}
+
+	s.value = s.text[s.start:s.pos]
+}
+
+// Return the text of the current token as written in the source.
+func (s *nativeScanner) tokenText() string {
+	return s.text[s.start:s.pos]
+}
+
+func isNativeDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isNativeHexDigit(char byte) bool {
+	return isNativeDigit(char) || char >= 'a' && char <= 'f' || char >= 'A' && char <= 'F'
+}
+
+func isNativeWhitespace(char rune) bool {
+	switch char {
+	case ' ', '\t', '\v', '\f', 0xA0, 0xFEFF, 0x1680, 0x202F, 0x205F, 0x3000, 0x85:
+		return true
+	}
+
+	return char >= 0x2000 && char <= 0x200B
+}
+
+func isNativeIdentifierStart(char rune) bool {
+	if char < utf8.RuneSelf {
+		return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char == '$' || char == '_'
+	}
+
+	return unicode.IsLetter(char) || unicode.Is(unicode.Nl, char) || unicode.Is(unicode.Other_ID_Start, char)
+}
+
+func isNativeIdentifierPart(char rune) bool {
+	if char < utf8.RuneSelf {
+		return isNativeIdentifierStart(char) || char >= '0' && char <= '9'
+	}
+
+	return isNativeIdentifierStart(char) || unicode.In(char, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) || char == 0x200C || char == 0x200D
+}
@@ -0,0 +1,1504 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+// This file contains the functions of the native parser that parse
+// statements and declarations, following the Typescript parser.
+
+// Parse the whole file into a `SourceFile` node.
+func (p *nativeFileParser) parseSourceFile() *nativeNode {
+	p.next()
+
+	node := p.newNode(p.kinds.SourceFile, 0)
+	node.Statements = listOf(p.parseStatements(false))
+
+	return p.finishAt(node, len(p.text))
+}
+
+// Parse statements till the end of the file, or till the closing
+// brace of the enclosing block.
+func (p *nativeFileParser) parseStatements(inBlock bool) nativeNodes {
+	var statements nativeNodes
+
+	for p.token != tokenEndOfFile && !(inBlock && p.at("}")) {
+		start := p.start
+		statements = append(statements, p.parseStatement())
+
+		// skip the token that cannot start a statement
+		if p.start == start {
+			p.error("Declaration or statement expected.", 1128)
+			p.next()
+		}
+	}
+
+	return statements
+}
+
+// Parse a single statement, along with any JSDoc comments before it.
+func (p *nativeFileParser) parseStatement() *nativeNode {
+	return p.attachJsDoc(p.parseStatementWorker())
+}
+
+func (p *nativeFileParser) parseStatementWorker() *nativeNode {
+	if p.token == tokenPunctuation {
+		switch p.value {
+		case ";":
+			return p.parseTokenNode(p.kinds.EmptyStatement)
+
+		case "{":
+			return p.parseBlock()
+
+		case "@":
+			return p.parseDeclaration()
+		}
+	}
+
+	if p.token != tokenIdentifier || p.escaped {
+		return p.parseExpressionOrLabeledStatement()
+	}
+
+	switch p.value {
+	case "var", "function", "class", "enum":
+		return p.parseDeclaration()
+
+	case "let":
+		if p.isLetDeclaration() {
+			return p.parseDeclaration()
+		}
+
+	case "if":
+		return p.parseIfStatement()
+
+	case "do":
+		return p.parseDoStatement()
+
+	case "while":
+		return p.parseWhileStatement()
+
+	case "for":
+		return p.parseForStatement()
+
+	case "continue", "break":
+		return p.parseBreakOrContinueStatement()
+
+	case "return":
+		return p.parseReturnStatement()
+
+	case "with":
+		return p.parseWithStatement()
+
+	case "switch":
+		return p.parseSwitchStatement()
+
+	case "throw":
+		return p.parseThrowStatement()
+
+	case "try":
+		return p.parseTryStatement()
+
+	case "debugger":
+		node := p.newNode(p.kinds.LastStatement, p.fullStart)
+		p.next()
+		p.parseSemicolon()
+		return p.finish(node)
+
+	case "async", "interface", "type", "module", "namespace", "declare", "const", "export", "import",
+		"private", "protected", "public", "abstract", "static", "readonly", "global", "override":
+		if p.isStartOfDeclaration() {
+			return p.parseDeclaration()
+		}
+	}
+
+	return p.parseExpressionOrLabeledStatement()
+}
+
+// Check if `let` starts a declaration, rather than being an identifier.
+func (p *nativeFileParser) isLetDeclaration() bool {
+	return p.lookAhead(func() bool {
+		p.next()
+		return p.token == tokenIdentifier || p.at("[") || p.at("{")
+	})
+}
+
+// Check if the current token starts a declaration, looking past any
+// modifiers, like `export` or `declare`.
+func (p *nativeFileParser) isStartOfDeclaration() bool {
+	return p.lookAhead(func() bool {
+		for p.token == tokenIdentifier {
+			switch p.value {
+			case "var", "let", "const", "function", "class", "enum":
+				return true
+
+			case "interface", "type":
+				p.next()
+				return p.token == tokenIdentifier && !p.lineBreak
+
+			case "module", "namespace":
+				p.next()
+				return (p.token == tokenIdentifier || p.token == tokenString) && !p.lineBreak
+
+			case "abstract", "async", "declare", "private", "protected", "public", "readonly", "override":
+				p.next()
+				if p.lineBreak {
+					return false
+				}
+
+			case "global":
+				p.next()
+				return p.at("{") || p.token == tokenIdentifier
+
+			case "import":
+				p.next()
+				return p.token == tokenString || p.at("*") || p.at("{") || p.token == tokenIdentifier
+
+			case "export":
+				p.next()
+
+				// `type` is peeked at, as in `export type { Props }`, since
+				// it is also the start of `export type Props = {}`
+				if p.atWord("type") && p.lookAhead(func() bool {
+					p.next()
+					return p.at("*") || p.at("{")
+				}) {
+					return true
+				}
+
+				if p.at("=") || p.at("*") || p.at("{") || p.atWord("default") || p.atWord("as") {
+					return true
+				}
+
+			case "static":
+				p.next()
+
+			default:
+				return false
+			}
+		}
+
+		return p.at("@")
+	})
+}
+
+// Parse a declaration, such as of a variable, function, class,
+// interface or module, along with its decorators and modifiers.
+func (p *nativeFileParser) parseDeclaration() *nativeNode {
+	pos := p.fullStart
+	p.parseDecorators()
+	modifiers := p.parseModifiers(false)
+
+	if p.token == tokenIdentifier {
+		switch p.value {
+		case "var", "let", "const":
+			return p.parseVariableStatement(pos, modifiers)
+
+		case "function":
+			return p.parseFunctionDeclaration(pos, modifiers)
+
+		case "class":
+			return p.parseClass(p.kinds.ClassDeclaration, pos, modifiers)
+
+		case "interface":
+			return p.parseInterfaceDeclaration(pos, modifiers)
+
+		case "type":
+			return p.parseTypeAliasDeclaration(pos, modifiers)
+
+		case "enum":
+			return p.parseEnumDeclaration(pos, modifiers)
+
+		case "global", "module", "namespace":
+			return p.parseModuleDeclaration(pos, modifiers)
+
+		case "import":
+			return p.parseImportDeclaration(pos, modifiers)
+
+		case "export":
+			p.next()
+			switch {
+			case p.atWord("default") || p.at("="):
+				return p.parseExportAssignment(pos, modifiers)
+
+			case p.atWord("as"):
+				return p.parseNamespaceExportDeclaration(pos, modifiers)
+			}
+
+			return p.parseExportDeclaration(pos, modifiers)
+		}
+	}
+
+	// decorators or modifiers without a declaration
+	p.error("Declaration expected.", 1146)
+	node := p.newNode(p.kinds.MissingDeclaration, pos)
+	node.Modifiers = modifiers
+	return p.finish(node)
+}
+
+// Parse the decorators before a declaration, which are not kept.
+func (p *nativeFileParser) parseDecorators() {
+	for p.at("@") {
+		p.next()
+		pos := p.fullStart
+		expression := p.parseMemberExpressionRest(pos, p.parsePrimaryExpression(), false)
+		p.parseCallExpressionRest(pos, expression)
+	}
+}
+
+// The keywords that may be used as modifiers
+var nativeModifiers = map[string]bool{
+	"abstract": true, "async": true, "const": true, "declare": true, "default": true, "export": true,
+	"private": true, "protected": true, "public": true, "readonly": true, "static": true, "override": true,
+}
+
+// Parse the modifiers of a declaration or member. A keyword is only a
+// modifier when followed by what may follow a modifier, such that
+// `static` is the name of the member in `static() {}`.
+func (p *nativeFileParser) parseModifiers(inClass bool) *nativeNodes {
+	var modifiers nativeNodes
+
+	for p.token == tokenIdentifier && !p.escaped && nativeModifiers[p.value] {
+		// `static {` starts a static block
+		if inClass && p.atWord("static") && p.nextIs("{", false) {
+			break
+		}
+
+		pos := p.fullStart
+		kind := p.keywords[p.value]
+		if !p.tryParse(p.nextTokenCanFollowModifier) {
+			break
+		}
+
+		modifiers = append(modifiers, p.finish(p.newNode(kind, pos)))
+	}
+
+	if modifiers == nil {
+		return nil
+	}
+
+	return listOf(modifiers)
+}
+
+func (p *nativeFileParser) nextTokenCanFollowModifier() bool {
+	switch p.value {
+	case "const":
+		// `const` is only a modifier before `enum`
+		p.next()
+		return p.atWord("enum")
+
+	case "export":
+		p.next()
+		if p.atWord("default") {
+			return p.lookAhead(p.nextTokenCanFollowDefaultKeyword)
+		}
+
+		if p.atWord("type") {
+			return p.lookAhead(func() bool {
+				p.next()
+				return p.canFollowExportModifier()
+			})
+		}
+
+		return p.canFollowExportModifier()
+
+	case "default":
+		return p.nextTokenCanFollowDefaultKeyword()
+
+	case "static":
+		p.next()
+		return p.canFollowModifier()
+	}
+
+	p.next()
+	return !p.lineBreak && p.canFollowModifier()
+}
+
+func (p *nativeFileParser) canFollowExportModifier() bool {
+	return !p.at("*") && !p.atWord("as") && !p.at("{") && p.canFollowModifier()
+}
+
+func (p *nativeFileParser) canFollowModifier() bool {
+	return p.at("[") || p.at("{") || p.at("*") || p.at("...") || p.isLiteralPropertyName()
+}
+
+func (p *nativeFileParser) nextTokenCanFollowDefaultKeyword() bool {
+	p.next()
+
+	switch {
+	case p.atWord("class"), p.atWord("function"), p.atWord("interface"):
+		return true
+
+	case p.atWord("abstract"):
+		return p.nextIsWord("class", true)
+
+	case p.atWord("async"):
+		return p.nextIsWord("function", true)
+	}
+
+	return false
+}
+
+// Check if the current token may be the name of a property.
+func (p *nativeFileParser) isLiteralPropertyName() bool {
+	switch p.token {
+	case tokenIdentifier, tokenString, tokenNumber, tokenBigInt, tokenPrivateIdentifier:
+		return true
+	}
+
+	return false
+}
+
+// Parse a block of statements, like `{ a(); b(); }`.
+func (p *nativeFileParser) parseBlock() *nativeNode {
+	node := p.newNode(p.kinds.Block, p.fullStart)
+
+	if p.expect("{") {
+		node.Statements = listOf(p.parseStatements(true))
+		p.expect("}")
+	} else {
+		node.Statements = listOf(nil)
+	}
+
+	return p.finish(node)
+}
+
+// Parse the body of a function, in which `yield` and `await` are
+// keywords depending on the kind of function.
+func (p *nativeFileParser) parseFunctionBlock(generator bool, async bool) *nativeNode {
+	savedYield, savedAwait := p.inYield, p.inAwait
+	p.inYield, p.inAwait = generator, async
+	defer func() {
+		p.inYield, p.inAwait = savedYield, savedAwait
+	}()
+
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+	defer func() {
+		p.disallowIn = savedDisallowIn
+	}()
+
+	return p.parseBlock()
+}
+
+// Parse the body of a function declaration, which may be missing for
+// overloads and ambient declarations.
+func (p *nativeFileParser) parseFunctionBlockOrSemicolon(generator bool, async bool) *nativeNode {
+	if p.at("{") {
+		return p.parseFunctionBlock(generator, async)
+	}
+
+	p.parseSemicolon()
+	return nil
+}
+
+// Parse a statement of variable declarations, like `const a = 1, b = 2;`.
+func (p *nativeFileParser) parseVariableStatement(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.FirstStatement, pos)
+	node.Modifiers = modifiers
+
+	p.parseVariableDeclarationList(false)
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse the `var`, `let` or `const` keyword along with the variable
+// declarations that follow. Within the initializer of a `for` loop,
+// the declarations may be followed by `in` or `of`.
+func (p *nativeFileParser) parseVariableDeclarationList(inForInitializer bool) {
+	p.next()
+
+	// `for (const x of y)` declares without an initializer
+	if inForInitializer && (p.atWord("of") || p.atWord("in")) && p.lookAhead(func() bool {
+		p.next()
+		return p.at(")")
+	}) {
+		return
+	}
+
+	for {
+		p.parseVariableDeclaration(inForInitializer)
+		if !p.eat(",") {
+			break
+		}
+	}
+}
+
+func (p *nativeFileParser) parseVariableDeclaration(inForInitializer bool) *nativeNode {
+	node := p.newNode(p.kinds.VariableDeclaration, p.fullStart)
+	node.Name = p.parseBindingName()
+
+	if p.at("!") && !p.lineBreak {
+		p.next()
+	}
+
+	node.Type = p.parseTypeAnnotation()
+
+	if !(inForInitializer && (p.atWord("in") || p.atWord("of"))) && p.eat("=") {
+		node.Initializer = p.parseAssignmentExpression()
+	}
+
+	return p.attachJsDoc(p.finish(node))
+}
+
+// Parse the name of a binding, which is an identifier or a pattern
+// destructuring an object or array.
+func (p *nativeFileParser) parseBindingName() *nativeNode {
+	switch {
+	case p.at("{"):
+		return p.parseObjectBindingPattern()
+
+	case p.at("["):
+		return p.parseArrayBindingPattern()
+	}
+
+	return p.parseBindingIdentifier()
+}
+
+func (p *nativeFileParser) parseObjectBindingPattern() *nativeNode {
+	node := p.newNode(p.kinds.ObjectBindingPattern, p.fullStart)
+	p.expect("{")
+
+	var elements nativeNodes
+	p.parseDelimitedList("}", func() {
+		element := p.newNode(p.kinds.BindingElement, p.fullStart)
+		if p.at("...") {
+			element.DotDotDotToken = p.parseTokenNode(p.kinds.DotDotDotToken)
+		}
+
+		tokenIsIdentifier := p.isIdentifier()
+		name := p.parsePropertyName()
+		if tokenIsIdentifier && !p.at(":") {
+			element.Name = name
+		} else {
+			p.expect(":")
+			element.PropertyName = name
+			element.Name = p.parseBindingName()
+		}
+
+		if p.eat("=") {
+			element.Initializer = p.parseAssignmentExpressionAllowIn()
+		}
+
+		elements = append(elements, p.finish(element))
+	})
+
+	node.Elements = listOf(elements)
+	p.expect("}")
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseArrayBindingPattern() *nativeNode {
+	node := p.newNode(p.kinds.ArrayBindingPattern, p.fullStart)
+	p.expect("[")
+
+	var elements nativeNodes
+	p.parseDelimitedList("]", func() {
+		if p.at(",") {
+			elements = append(elements, p.finish(p.newNode(p.kinds.OmittedExpression, p.fullStart)))
+			return
+		}
+
+		element := p.newNode(p.kinds.BindingElement, p.fullStart)
+		if p.at("...") {
+			element.DotDotDotToken = p.parseTokenNode(p.kinds.DotDotDotToken)
+		}
+
+		element.Name = p.parseBindingName()
+		if p.eat("=") {
+			element.Initializer = p.parseAssignmentExpressionAllowIn()
+		}
+
+		elements = append(elements, p.finish(element))
+	})
+
+	node.Elements = listOf(elements)
+	p.expect("]")
+
+	return p.finish(node)
+}
+
+// Parse the elements of a list separated by commas, till the token
+// closing the list. The closing token is not consumed.
+func (p *nativeFileParser) parseDelimitedList(closing string, parseElement func()) {
+	for !p.at(closing) && p.token != tokenEndOfFile {
+		start := p.start
+		parseElement()
+
+		if p.eat(",") {
+			continue
+		}
+
+		if p.at(closing) || p.start == start {
+			break
+		}
+
+		p.error("',' expected.", 1005)
+	}
+}
+
+// Parse a function declaration, like `function Button(props) {}`.
+func (p *nativeFileParser) parseFunctionDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.FunctionDeclaration, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("function")
+	generator := p.eat("*")
+
+	// `export default function () {}` has no name
+	if !hasModifier(modifiers, p.kinds.DefaultKeyword) || p.isIdentifier() {
+		node.Name = p.parseBindingIdentifier()
+	}
+
+	async := hasModifier(modifiers, p.kinds.AsyncKeyword)
+	p.parseSignature(node, ":", generator, async)
+	node.Body = p.parseFunctionBlockOrSemicolon(generator, async)
+
+	return p.finish(node)
+}
+
+// Check if the modifiers contain one of the given kind.
+func hasModifier(modifiers *nativeNodes, kind int) bool {
+	if modifiers == nil {
+		return false
+	}
+
+	for _, modifier := range *modifiers {
+		if modifier.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse the type parameters, parameters and return type of a function
+// or method. The return type follows the given token, which is `:` for
+// declarations and `=>` for function types.
+func (p *nativeFileParser) parseSignature(node *nativeNode, returnToken string, generator bool, async bool) {
+	node.TypeParameters = p.parseTypeParameters()
+
+	savedYield, savedAwait := p.inYield, p.inAwait
+	p.inYield, p.inAwait = generator, async
+	node.Parameters = p.parseParameters()
+	p.inYield, p.inAwait = savedYield, savedAwait
+
+	if returnToken == "=>" {
+		if p.expect("=>") {
+			node.Type = p.parseTypeOrTypePredicate()
+		}
+		return
+	}
+
+	if p.eat(":") {
+		node.Type = p.parseTypeOrTypePredicate()
+	}
+}
+
+// Parse the parameters of a function, like `(a: string, b = 1)`.
+func (p *nativeFileParser) parseParameters() *nativeNodes {
+	var parameters nativeNodes
+	if !p.expect("(") {
+		return listOf(nil)
+	}
+
+	p.parseDelimitedList(")", func() {
+		parameters = append(parameters, p.parseParameter())
+	})
+
+	p.expect(")")
+	return listOf(parameters)
+}
+
+func (p *nativeFileParser) parseParameter() *nativeNode {
+	node := p.newNode(p.kinds.Parameter, p.fullStart)
+
+	p.parseDecorators()
+	node.Modifiers = p.parseModifiers(false)
+
+	if p.atWord("this") {
+		node.Name = p.parseIdentifierName()
+	} else {
+		if p.at("...") {
+			node.DotDotDotToken = p.parseTokenNode(p.kinds.DotDotDotToken)
+		}
+
+		node.Name = p.parseBindingName()
+	}
+
+	if p.at("?") {
+		node.QuestionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	}
+
+	node.Type = p.parseTypeAnnotation()
+
+	if p.eat("=") {
+		node.Initializer = p.parseAssignmentExpressionAllowIn()
+	}
+
+	return p.attachJsDoc(p.finish(node))
+}
+
+// Parse the type following `:`, if any.
+func (p *nativeFileParser) parseTypeAnnotation() *nativeNode {
+	if p.eat(":") {
+		return p.parseType()
+	}
+
+	return nil
+}
+
+// Parse the type parameters of a declaration, like `<T extends object = {}>`.
+func (p *nativeFileParser) parseTypeParameters() *nativeNodes {
+	if !p.at("<") {
+		return nil
+	}
+
+	p.next()
+
+	var typeParameters nativeNodes
+	p.parseDelimitedList(">", func() {
+		node := p.newNode(p.kinds.TypeParameter, p.fullStart)
+
+		// variance and const modifiers, like `in T` or `const T`
+		for (p.atWord("in") || p.atWord("out") || p.atWord("const")) && p.lookAhead(func() bool {
+			p.next()
+			return p.isIdentifier()
+		}) {
+			p.next()
+		}
+
+		node.Name = p.parseIdentifier()
+		if p.eatWord("extends") {
+			node.Constraint = p.parseType()
+		}
+
+		if p.eat("=") {
+			node.Default = p.parseType()
+		}
+
+		typeParameters = append(typeParameters, p.finish(node))
+	})
+
+	p.expect(">")
+	return listOf(typeParameters)
+}
+
+// Parse a class declaration or expression.
+func (p *nativeFileParser) parseClass(kind int, pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(kind, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("class")
+
+	// the name is optional, as in `export default class extends Base {}`
+	if p.isIdentifier() && !p.atWord("implements") && !p.atWord("extends") {
+		node.Name = p.parseBindingIdentifier()
+	}
+
+	node.TypeParameters = p.parseTypeParameters()
+	node.HeritageClauses = p.parseHeritageClauses()
+
+	var members nativeNodes
+	if p.expect("{") {
+		for !p.at("}") && p.token != tokenEndOfFile {
+			start := p.start
+
+			member := p.parseClassElement()
+			if member != nil {
+				members = append(members, member)
+			}
+
+			if p.start == start {
+				p.error("Unexpected token. A constructor, method, accessor, or property was expected.", 1068)
+				p.next()
+			}
+		}
+
+		p.expect("}")
+	}
+
+	node.Members = listOf(members)
+	return p.finish(node)
+}
+
+// Parse the `extends` and `implements` clauses of a class or interface.
+func (p *nativeFileParser) parseHeritageClauses() *nativeNodes {
+	var clauses nativeNodes
+
+	for p.atWord("extends") || p.atWord("implements") {
+		clause := p.newNode(p.kinds.HeritageClause, p.fullStart)
+		p.next()
+
+		var types nativeNodes
+		for {
+			typ := p.newNode(p.kinds.ExpressionWithTypeArguments, p.fullStart)
+			typ.Expression = p.parseLeftHandSideExpression()
+			if p.at("<") {
+				typ.TypeArguments = p.parseTypeArguments()
+			}
+
+			types = append(types, p.finish(typ))
+			if !p.eat(",") {
+				break
+			}
+		}
+
+		clause.Types = listOf(types)
+		clauses = append(clauses, p.finish(clause))
+	}
+
+	if clauses == nil {
+		return nil
+	}
+
+	return listOf(clauses)
+}
+
+// Parse a member of a class, like a property, method or constructor.
+// Returns `nil` if there is no member at the current token.
+func (p *nativeFileParser) parseClassElement() *nativeNode {
+	pos := p.fullStart
+
+	if p.at(";") {
+		return p.parseTokenNode(p.kinds.SemicolonClassElement)
+	}
+
+	p.parseDecorators()
+	modifiers := p.parseModifiers(true)
+
+	// static blocks, like `static { init(); }`
+	if p.atWord("static") && p.nextIs("{", false) {
+		node := p.newNode(p.kinds.ClassStaticBlockDeclaration, pos)
+		p.next()
+		node.Body = p.parseFunctionBlock(false, false)
+		return p.attachJsDoc(p.finish(node))
+	}
+
+	if node := p.tryParseAccessor(pos, modifiers); node != nil {
+		return p.attachJsDoc(node)
+	}
+
+	if (p.atWord("constructor") || p.token == tokenString && p.value == "constructor") && p.nextIs("(", false) {
+		node := p.newNode(p.kinds.Constructor, pos)
+		node.Modifiers = modifiers
+		p.next()
+
+		p.parseSignature(node, ":", false, false)
+		node.Body = p.parseFunctionBlockOrSemicolon(false, false)
+		return p.attachJsDoc(p.finish(node))
+	}
+
+	if p.isIndexSignature() {
+		return p.attachJsDoc(p.parseIndexSignature(pos, modifiers))
+	}
+
+	if p.isLiteralPropertyName() || p.at("[") || p.at("*") {
+		return p.attachJsDoc(p.parsePropertyOrMethodDeclaration(pos, modifiers))
+	}
+
+	if modifiers != nil {
+		// modifiers without a member, like `static;`
+		p.error("Identifier expected.", 1003)
+		node := p.newNode(p.kinds.PropertyDeclaration, pos)
+		node.Modifiers = modifiers
+		node.Name = p.newMissingIdentifier()
+		return p.finish(node)
+	}
+
+	return nil
+}
+
+// Parse a property or method of a class.
+func (p *nativeFileParser) parsePropertyOrMethodDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	generator := p.eat("*")
+	name := p.parsePropertyName()
+
+	var questionToken *nativeNode
+	if p.at("?") {
+		questionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	} else if p.at("!") && !p.lineBreak {
+		p.next()
+	}
+
+	if generator || p.at("(") || p.at("<") {
+		node := p.newNode(p.kinds.MethodDeclaration, pos)
+		node.Modifiers = modifiers
+		node.Name = name
+		node.QuestionToken = questionToken
+
+		async := hasModifier(modifiers, p.kinds.AsyncKeyword)
+		p.parseSignature(node, ":", generator, async)
+		node.Body = p.parseFunctionBlockOrSemicolon(generator, async)
+
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.PropertyDeclaration, pos)
+	node.Modifiers = modifiers
+	node.Name = name
+	node.QuestionToken = questionToken
+	node.Type = p.parseTypeAnnotation()
+
+	if p.eat("=") {
+		savedYield, savedAwait := p.inYield, p.inAwait
+		p.inYield, p.inAwait = false, false
+		node.Initializer = p.parseAssignmentExpressionAllowIn()
+		p.inYield, p.inAwait = savedYield, savedAwait
+	}
+
+	p.parseSemicolon()
+	return p.finish(node)
+}
+
+// Parse a `get` or `set` accessor, of a class, an object literal or an
+// interface. Returns `nil` if the current token does not start one.
+func (p *nativeFileParser) tryParseAccessor(pos int, modifiers *nativeNodes) *nativeNode {
+	if !p.atWord("get") && !p.atWord("set") {
+		return nil
+	}
+
+	kind := p.kinds.GetAccessor
+	if p.value == "set" {
+		kind = p.kinds.SetAccessor
+	}
+
+	if !p.lookAhead(func() bool {
+		p.next()
+		return p.canFollowModifier() && !p.at("{") && !p.at("*") && !p.at("...")
+	}) {
+		return nil
+	}
+
+	p.next()
+
+	node := p.newNode(kind, pos)
+	node.Modifiers = modifiers
+	node.Name = p.parsePropertyName()
+
+	p.parseSignature(node, ":", false, false)
+	node.Body = p.parseFunctionBlockOrSemicolon(false, false)
+
+	return p.finish(node)
+}
+
+// Check if the `[` of the current token starts an index signature,
+// like `[key: string]: any`, rather than a computed property name.
+func (p *nativeFileParser) isIndexSignature() bool {
+	if !p.at("[") {
+		return false
+	}
+
+	return p.lookAhead(func() bool {
+		p.next()
+		if p.at("...") || p.at("]") {
+			return true
+		}
+
+		if nativeModifiers[p.value] && p.token == tokenIdentifier {
+			p.next()
+			if p.isIdentifier() {
+				return true
+			}
+		} else if !p.isIdentifier() {
+			return false
+		} else {
+			p.next()
+		}
+
+		if p.at(":") || p.at(",") {
+			return true
+		}
+
+		if !p.at("?") {
+			return false
+		}
+
+		p.next()
+		return p.at(":") || p.at(",") || p.at("]")
+	})
+}
+
+// Parse an index signature, like `[key: string]: any`.
+func (p *nativeFileParser) parseIndexSignature(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.IndexSignature, pos)
+	node.Modifiers = modifiers
+
+	p.expect("[")
+	var parameters nativeNodes
+	p.parseDelimitedList("]", func() {
+		parameters = append(parameters, p.parseParameter())
+	})
+	p.expect("]")
+
+	node.Parameters = listOf(parameters)
+	node.Type = p.parseTypeAnnotation()
+	p.parseTypeMemberSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse an interface declaration.
+func (p *nativeFileParser) parseInterfaceDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.InterfaceDeclaration, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("interface")
+	node.Name = p.parseIdentifier()
+	node.TypeParameters = p.parseTypeParameters()
+	node.HeritageClauses = p.parseHeritageClauses()
+	node.Members = p.parseObjectTypeMembers()
+
+	return p.finish(node)
+}
+
+// Parse a type alias, like `type Size = 'sm' | 'md';`.
+func (p *nativeFileParser) parseTypeAliasDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.TypeAliasDeclaration, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("type")
+	node.Name = p.parseIdentifier()
+	node.TypeParameters = p.parseTypeParameters()
+	p.expect("=")
+	node.Type = p.parseType()
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse an enum declaration.
+func (p *nativeFileParser) parseEnumDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.EnumDeclaration, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("enum")
+	node.Name = p.parseIdentifier()
+
+	var members nativeNodes
+	if p.expect("{") {
+		p.parseDelimitedList("}", func() {
+			member := p.newNode(p.kinds.EnumMember, p.fullStart)
+			member.Name = p.parsePropertyName()
+			if p.eat("=") {
+				member.Initializer = p.parseAssignmentExpressionAllowIn()
+			}
+
+			members = append(members, p.attachJsDoc(p.finish(member)))
+		})
+
+		p.expect("}")
+	}
+
+	node.Members = listOf(members)
+	return p.finish(node)
+}
+
+// Parse a namespace or module declaration, like `namespace A.B {}`,
+// `declare module 'name' {}` or `declare global {}`.
+func (p *nativeFileParser) parseModuleDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.ModuleDeclaration, pos)
+	node.Modifiers = modifiers
+
+	if p.atWord("global") {
+		node.Name = p.parseIdentifierName()
+		node.Body = p.parseModuleBlock()
+		return p.finish(node)
+	}
+
+	p.next()
+	if p.token == tokenString {
+		node.Name = p.parseLiteral()
+		if p.at("{") {
+			node.Body = p.parseModuleBlock()
+		} else {
+			p.parseSemicolon()
+		}
+
+		return p.finish(node)
+	}
+
+	return p.parseNamespaceBody(node)
+}
+
+// Parse the name and body of a namespace, where a dotted name like
+// `A.B` declares a namespace within a namespace.
+func (p *nativeFileParser) parseNamespaceBody(node *nativeNode) *nativeNode {
+	node.Name = p.parseIdentifier()
+
+	if p.eat(".") {
+		node.Body = p.parseNamespaceBody(p.newNode(p.kinds.ModuleDeclaration, p.fullStart))
+	} else {
+		node.Body = p.parseModuleBlock()
+	}
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseModuleBlock() *nativeNode {
+	node := p.newNode(p.kinds.ModuleBlock, p.fullStart)
+
+	if p.expect("{") {
+		node.Statements = listOf(p.parseStatements(true))
+		p.expect("}")
+	} else {
+		node.Statements = listOf(nil)
+	}
+
+	return p.finish(node)
+}
+
+// Parse an import declaration, like `import React, { useState } from 'react';`,
+// or an import equals declaration, like `import fs = require('fs');`.
+func (p *nativeFileParser) parseImportDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	p.expectWord("import")
+	clausePos := p.fullStart
+
+	var identifier *nativeNode
+	if p.isIdentifier() {
+		identifier = p.parseIdentifier()
+	}
+
+	isTypeOnly := false
+	if identifier != nil && identifier.EscapedText == "type" && !p.atWord("from") && (p.isIdentifier() || p.at("*") || p.at("{")) {
+		isTypeOnly = true
+		identifier = nil
+		if p.isIdentifier() {
+			identifier = p.parseIdentifier()
+		}
+	}
+
+	// `import name = require('module')` or `import name = A.B`
+	if identifier != nil && !p.at(",") && !p.atWord("from") {
+		node := p.newNode(p.kinds.ImportEqualsDeclaration, pos)
+		node.Modifiers = modifiers
+		node.Name = identifier
+		node.IsTypeOnly = isTypeOnly
+
+		p.expect("=")
+		if p.atWord("require") && p.nextIs("(", false) {
+			p.next()
+			p.next()
+			p.parseModuleSpecifier()
+			p.expect(")")
+		} else {
+			p.parseEntityName(true)
+		}
+
+		p.parseSemicolon()
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.ImportDeclaration, pos)
+	node.Modifiers = modifiers
+
+	if identifier != nil || p.at("*") || p.at("{") {
+		clause := p.newNode(p.kinds.ImportClause, clausePos)
+		clause.IsTypeOnly = isTypeOnly
+		clause.Name = identifier
+
+		if identifier == nil || p.eat(",") {
+			if p.at("*") {
+				clause.NamedBindings = p.parseNamespaceImport()
+			} else {
+				clause.NamedBindings = p.parseNamedImportsOrExports(p.kinds.NamedImports, p.kinds.ImportSpecifier)
+			}
+		}
+
+		node.ImportClause = p.finish(clause)
+		p.expectWord("from")
+	}
+
+	node.ModuleSpecifier = p.parseModuleSpecifier()
+	p.parseAssertClause()
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse `* as name` of an import.
+func (p *nativeFileParser) parseNamespaceImport() *nativeNode {
+	node := p.newNode(p.kinds.NamespaceImport, p.fullStart)
+	p.expect("*")
+	p.expectWord("as")
+	node.Name = p.parseIdentifier()
+
+	return p.finish(node)
+}
+
+// Parse the names imported or exported, like `{ a, b as c, type d }`.
+func (p *nativeFileParser) parseNamedImportsOrExports(kind int, specifierKind int) *nativeNode {
+	node := p.newNode(kind, p.fullStart)
+
+	var elements nativeNodes
+	if p.expect("{") {
+		p.parseDelimitedList("}", func() {
+			specifier := p.newNode(specifierKind, p.fullStart)
+
+			// `type` is a modifier unless it is the name itself, like
+			// in `{ type }` or `{ type as name }`
+			if p.atWord("type") && p.lookAhead(func() bool {
+				p.next()
+				if p.atWord("as") {
+					p.next()
+					return p.atWord("as")
+				}
+
+				return p.token == tokenIdentifier || p.token == tokenString
+			}) {
+				specifier.IsTypeOnly = true
+				p.next()
+			}
+
+			name := p.parseModuleExportName()
+			if p.eatWord("as") {
+				specifier.PropertyName = name
+				name = p.parseModuleExportName()
+			}
+
+			specifier.Name = name
+			elements = append(elements, p.finish(specifier))
+		})
+
+		p.expect("}")
+	}
+
+	node.Elements = listOf(elements)
+	return p.finish(node)
+}
+
+// Parse the name of an imported or exported symbol, which may also be
+// a string, like `{ "a-b" as ab }`.
+func (p *nativeFileParser) parseModuleExportName() *nativeNode {
+	if p.token == tokenString {
+		return p.parseLiteral()
+	}
+
+	return p.parseIdentifierName()
+}
+
+// Parse the string naming the module of an import or export.
+func (p *nativeFileParser) parseModuleSpecifier() *nativeNode {
+	if p.token == tokenString {
+		return p.parseLiteral()
+	}
+
+	p.error("String literal expected.", 1141)
+	return p.parseExpression()
+}
+
+// Parse the import assertions, like `assert { type: 'json' }`, which
+// are not kept.
+func (p *nativeFileParser) parseAssertClause() {
+	if !p.atWord("assert") || p.lineBreak {
+		return
+	}
+
+	p.next()
+	p.parseObjectLiteral()
+}
+
+// Parse `export default expression;` or `export = expression;`.
+func (p *nativeFileParser) parseExportAssignment(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.ExportAssignment, pos)
+	node.Modifiers = modifiers
+
+	if !p.eat("=") {
+		p.expectWord("default")
+	}
+
+	node.Expression = p.parseAssignmentExpressionAllowIn()
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse `export as namespace Name;`.
+func (p *nativeFileParser) parseNamespaceExportDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.NamespaceExportDeclaration, pos)
+	node.Modifiers = modifiers
+
+	p.expectWord("as")
+	p.expectWord("namespace")
+	node.Name = p.parseIdentifier()
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse an export declaration, like `export { a, b as c } from './d';`
+// or `export * as ns from './e';`.
+func (p *nativeFileParser) parseExportDeclaration(pos int, modifiers *nativeNodes) *nativeNode {
+	node := p.newNode(p.kinds.ExportDeclaration, pos)
+	node.Modifiers = modifiers
+	node.IsTypeOnly = p.eatWord("type")
+
+	if p.at("*") {
+		exportPos := p.fullStart
+		p.next()
+
+		if p.atWord("as") {
+			clause := p.newNode(p.kinds.NamespaceExport, exportPos)
+			p.next()
+			clause.Name = p.parseModuleExportName()
+			node.ExportClause = p.finish(clause)
+		}
+
+		p.expectWord("from")
+		node.ModuleSpecifier = p.parseModuleSpecifier()
+	} else {
+		node.ExportClause = p.parseNamedImportsOrExports(p.kinds.NamedExports, p.kinds.ExportSpecifier)
+
+		if p.atWord("from") || p.token == tokenString && !p.lineBreak {
+			p.expectWord("from")
+			node.ModuleSpecifier = p.parseModuleSpecifier()
+		}
+	}
+
+	p.parseAssertClause()
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse an expression statement, or a labeled statement like `outer: for (...)`.
+func (p *nativeFileParser) parseExpressionOrLabeledStatement() *nativeNode {
+	pos := p.fullStart
+	expression := p.parseExpressionAllowIn()
+
+	if expression.Kind == p.kinds.Identifier && p.at(":") {
+		node := p.newNode(p.kinds.LabeledStatement, pos)
+		p.next()
+		p.parseStatement()
+
+		return p.finish(node)
+	}
+
+	node := p.newNode(p.kinds.ExpressionStatement, pos)
+	node.Expression = expression
+	p.parseSemicolon()
+
+	return p.finish(node)
+}
+
+// Parse the parenthesized condition of statements like `if` and `while`.
+func (p *nativeFileParser) parseCondition() *nativeNode {
+	p.expect("(")
+	expression := p.parseExpressionAllowIn()
+	p.expect(")")
+
+	return expression
+}
+
+func (p *nativeFileParser) parseIfStatement() *nativeNode {
+	node := p.newNode(p.kinds.IfStatement, p.fullStart)
+	p.next()
+
+	node.Expression = p.parseCondition()
+	p.parseStatement()
+	if p.eatWord("else") {
+		p.parseStatement()
+	}
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseDoStatement() *nativeNode {
+	node := p.newNode(p.kinds.DoStatement, p.fullStart)
+	p.next()
+
+	p.parseStatement()
+	p.expectWord("while")
+	node.Expression = p.parseCondition()
+	p.eat(";")
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseWhileStatement() *nativeNode {
+	node := p.newNode(p.kinds.WhileStatement, p.fullStart)
+	p.next()
+
+	node.Expression = p.parseCondition()
+	p.parseStatement()
+
+	return p.finish(node)
+}
+
+// Parse the `for`, `for...in` and `for...of` loops.
+func (p *nativeFileParser) parseForStatement() *nativeNode {
+	pos := p.fullStart
+	p.next()
+
+	if p.atWord("await") {
+		p.next()
+	}
+
+	p.expect("(")
+
+	// the initializer, in which `in` is not an operator
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = true
+
+	if !p.at(";") {
+		if p.atWord("var") || p.atWord("const") || p.atWord("let") && p.isLetDeclaration() {
+			p.parseVariableDeclarationList(true)
+		} else {
+			p.parseExpression()
+		}
+	}
+
+	p.disallowIn = savedDisallowIn
+
+	var node *nativeNode
+	switch {
+	case p.eatWord("of"):
+		node = p.newNode(p.kinds.ForOfStatement, pos)
+		node.Expression = p.parseAssignmentExpressionAllowIn()
+
+	case p.eatWord("in"):
+		node = p.newNode(p.kinds.ForInStatement, pos)
+		node.Expression = p.parseExpressionAllowIn()
+
+	default:
+		node = p.newNode(p.kinds.ForStatement, pos)
+		p.expect(";")
+		if !p.at(";") {
+			p.parseExpressionAllowIn()
+		}
+
+		p.expect(";")
+		if !p.at(")") {
+			p.parseExpressionAllowIn()
+		}
+	}
+
+	p.expect(")")
+	p.parseStatement()
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseBreakOrContinueStatement() *nativeNode {
+	kind := p.kinds.BreakStatement
+	if p.atWord("continue") {
+		kind = p.kinds.ContinueStatement
+	}
+
+	node := p.newNode(kind, p.fullStart)
+	p.next()
+
+	if p.isIdentifier() && !p.lineBreak {
+		p.parseIdentifier()
+	}
+
+	p.parseSemicolon()
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseReturnStatement() *nativeNode {
+	node := p.newNode(p.kinds.ReturnStatement, p.fullStart)
+	p.next()
+
+	if !p.at(";") && !p.at("}") && p.token != tokenEndOfFile && !p.lineBreak {
+		node.Expression = p.parseExpressionAllowIn()
+	}
+
+	p.parseSemicolon()
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseWithStatement() *nativeNode {
+	node := p.newNode(p.kinds.WithStatement, p.fullStart)
+	p.next()
+
+	node.Expression = p.parseCondition()
+	p.parseStatement()
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseSwitchStatement() *nativeNode {
+	node := p.newNode(p.kinds.SwitchStatement, p.fullStart)
+	p.next()
+
+	node.Expression = p.parseCondition()
+
+	if p.expect("{") {
+		for !p.at("}") && p.token != tokenEndOfFile {
+			start := p.start
+
+			if p.eatWord("case") {
+				p.parseExpressionAllowIn()
+				p.expect(":")
+			} else if p.eatWord("default") {
+				p.expect(":")
+			} else {
+				p.error("'case' expected.", 1005)
+			}
+
+			for !p.at("}") && !p.atWord("case") && !p.atWord("default") && p.token != tokenEndOfFile {
+				statementStart := p.start
+				p.parseStatement()
+
+				if p.start == statementStart {
+					break
+				}
+			}
+
+			if p.start == start {
+				p.next()
+			}
+		}
+
+		p.expect("}")
+	}
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseThrowStatement() *nativeNode {
+	node := p.newNode(p.kinds.ThrowStatement, p.fullStart)
+	p.next()
+
+	if p.lineBreak {
+		p.error("Line break not permitted here.", 1142)
+	} else {
+		node.Expression = p.parseExpressionAllowIn()
+	}
+
+	p.parseSemicolon()
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseTryStatement() *nativeNode {
+	node := p.newNode(p.kinds.TryStatement, p.fullStart)
+	p.next()
+
+	p.parseBlock()
+
+	if p.atWord("catch") {
+		p.next()
+		if p.eat("(") {
+			p.parseVariableDeclaration(false)
+			p.expect(")")
+		}
+
+		p.parseBlock()
+	}
+
+	if p.eatWord("finally") {
+		p.parseBlock()
+	}
+
+	return p.finish(node)
+}
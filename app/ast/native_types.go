@@ -0,0 +1,770 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+// This file contains the functions of the native parser that parse
+// types and the members of interfaces and type literals, following
+// the Typescript parser.
+
+// The keywords naming primitive types
+var nativeTypeKeywords = map[string]bool{
+	"any": true, "unknown": true, "string": true, "number": true, "bigint": true, "symbol": true,
+	"boolean": true, "undefined": true, "never": true, "object": true,
+}
+
+// Parse a type.
+func (p *nativeFileParser) parseType() *nativeNode {
+	savedDisallowIn := p.disallowIn
+	p.disallowIn = false
+	defer func() {
+		p.disallowIn = savedDisallowIn
+	}()
+
+	return p.parseTypeWorker(false)
+}
+
+func (p *nativeFileParser) parseTypeWorker(noConditionalTypes bool) *nativeNode {
+	if p.isStartOfFunctionOrConstructorType() {
+		return p.parseFunctionOrConstructorType()
+	}
+
+	pos := p.fullStart
+	typ := p.parseUnionOrIntersectionType("|", p.kinds.UnionType, func() *nativeNode {
+		return p.parseUnionOrIntersectionType("&", p.kinds.IntersectionType, p.parseTypeOperator)
+	})
+
+	// conditional types, like `T extends string ? 'text' : never`
+	if !noConditionalTypes && !p.lineBreak && p.atWord("extends") {
+		node := p.newNode(p.kinds.ConditionalType, pos)
+		p.next()
+		p.parseTypeWorker(true)
+		p.expect("?")
+		p.parseTypeWorker(false)
+		p.expect(":")
+		p.parseTypeWorker(false)
+
+		return p.finish(node)
+	}
+
+	return typ
+}
+
+// Parse a type, or a type predicate like `value is string`, as used
+// for the return types of functions.
+func (p *nativeFileParser) parseTypeOrTypePredicate() *nativeNode {
+	pos := p.fullStart
+
+	if p.isIdentifier() && p.nextIsWord("is", true) {
+		node := p.newNode(p.kinds.FirstTypeNode, pos)
+		p.parseIdentifier()
+		p.next()
+		node.Type = p.parseType()
+
+		return p.finish(node)
+	}
+
+	return p.parseType()
+}
+
+func (p *nativeFileParser) isStartOfFunctionOrConstructorType() bool {
+	if p.at("<") || p.atWord("new") {
+		return true
+	}
+
+	if p.atWord("abstract") {
+		return p.nextIsWord("new", false)
+	}
+
+	return p.at("(") && p.lookAhead(p.isUnambiguouslyStartOfFunctionType)
+}
+
+// Check if the `(` of the current token starts the parameters of a
+// function type, rather than a parenthesized type.
+func (p *nativeFileParser) isUnambiguouslyStartOfFunctionType() bool {
+	p.next()
+	if p.at(")") || p.at("...") {
+		return true
+	}
+
+	for p.token == tokenIdentifier && nativeModifiers[p.value] && p.nextIsIdentifierOrPattern() {
+		p.next()
+	}
+
+	switch {
+	case p.isIdentifier() || p.atWord("this"):
+		p.next()
+
+	case p.at("[") || p.at("{"):
+		p.parseBindingName()
+
+	default:
+		return false
+	}
+
+	if p.at(":") || p.at(",") || p.at("?") || p.at("=") {
+		return true
+	}
+
+	if p.at(")") {
+		p.next()
+		return p.at("=>")
+	}
+
+	return false
+}
+
+func (p *nativeFileParser) nextIsIdentifierOrPattern() bool {
+	return p.lookAhead(func() bool {
+		p.next()
+		return p.isIdentifier() || p.at("[") || p.at("{")
+	})
+}
+
+// Parse a function type, like `(value: string) => void`, or a
+// constructor type, like `new () => Component`.
+func (p *nativeFileParser) parseFunctionOrConstructorType() *nativeNode {
+	pos := p.fullStart
+
+	var modifiers *nativeNodes
+	if p.atWord("abstract") {
+		modifiers = listOf(nativeNodes{p.parseTokenNode(p.keywords["abstract"])})
+	}
+
+	kind := p.kinds.FunctionType
+	if p.eatWord("new") {
+		kind = p.kinds.ConstructorType
+	}
+
+	node := p.newNode(kind, pos)
+	node.Modifiers = modifiers
+	p.parseSignature(node, "=>", false, false)
+
+	return p.finish(node)
+}
+
+// Parse the types separated by the operator, creating a union or
+// intersection type if there is more than one, or a leading operator.
+func (p *nativeFileParser) parseUnionOrIntersectionType(operator string, kind int, parseConstituent func() *nativeNode) *nativeNode {
+	pos := p.fullStart
+	hasLeadingOperator := p.eat(operator)
+
+	parseType := func() *nativeNode {
+		if p.isStartOfFunctionOrConstructorType() {
+			p.error("Function type notation must be parenthesized when used in a union type.", 1385)
+			return p.parseFunctionOrConstructorType()
+		}
+
+		return parseConstituent()
+	}
+
+	typ := parseType()
+	if !p.at(operator) && !hasLeadingOperator {
+		return typ
+	}
+
+	types := nativeNodes{typ}
+	for p.eat(operator) {
+		types = append(types, parseType())
+	}
+
+	node := p.newNode(kind, pos)
+	node.Types = listOf(types)
+
+	return p.finish(node)
+}
+
+// Parse the type operators, like `keyof T` or `readonly string[]`.
+func (p *nativeFileParser) parseTypeOperator() *nativeNode {
+	pos := p.fullStart
+
+	switch {
+	case p.atWord("keyof"), p.atWord("unique"), p.atWord("readonly"):
+		node := p.newNode(p.kinds.TypeOperator, pos)
+		p.next()
+		node.Type = p.parseTypeOperator()
+		return p.finish(node)
+
+	case p.atWord("infer"):
+		node := p.newNode(p.kinds.InferType, pos)
+		p.next()
+
+		typeParameter := p.newNode(p.kinds.TypeParameter, p.fullStart)
+		typeParameter.Name = p.parseIdentifier()
+		p.finish(typeParameter)
+
+		return p.finish(node)
+	}
+
+	return p.parsePostfixType()
+}
+
+// Parse the array types and indexed access types following a type,
+// like `string[]` or `Props['size']`, as well as the JSDoc postfix
+// types, like `string?` and `string!`.
+func (p *nativeFileParser) parsePostfixType() *nativeNode {
+	pos := p.fullStart
+	typ := p.parseNonArrayType()
+
+	for !p.lineBreak {
+		switch {
+		case p.at("!"):
+			node := p.newNode(p.kinds.JSDocNonNullableType, pos)
+			p.next()
+			node.Type = typ
+			typ = p.finish(node)
+
+		case p.at("?"):
+			// the `?` of a conditional type
+			if p.lookAhead(func() bool {
+				p.next()
+				return p.isStartOfType()
+			}) {
+				return typ
+			}
+
+			node := p.newNode(p.kinds.JSDocNullableType, pos)
+			p.next()
+			node.Type = typ
+			typ = p.finish(node)
+
+		case p.at("["):
+			p.next()
+
+			if p.at("]") {
+				node := p.newNode(p.kinds.ArrayType, pos)
+				p.next()
+				node.ElementType = typ
+				typ = p.finish(node)
+			} else {
+				node := p.newNode(p.kinds.IndexedAccessType, pos)
+				p.parseType()
+				p.expect("]")
+				typ = p.finish(node)
+			}
+
+		default:
+			return typ
+		}
+	}
+
+	return typ
+}
+
+// Check if the current token may start a type.
+func (p *nativeFileParser) isStartOfType() bool {
+	switch p.token {
+	case tokenIdentifier:
+		if !nativeReservedWords[p.value] || p.escaped {
+			return true
+		}
+
+		switch p.value {
+		case "null", "this", "typeof", "void", "new", "true", "false", "import":
+			return true
+		}
+
+	case tokenString, tokenNumber, tokenBigInt, tokenTemplate:
+		return true
+
+	case tokenPunctuation:
+		switch p.value {
+		case "{", "[", "<", "|", "&", "*", "?", "!", "...", "(":
+			return true
+
+		case "-":
+			return p.lookAhead(func() bool {
+				p.next()
+				return p.token == tokenNumber || p.token == tokenBigInt
+			})
+		}
+	}
+
+	return false
+}
+
+// Parse a type that is not an array type, like a type reference,
+// a literal type or a type literal.
+func (p *nativeFileParser) parseNonArrayType() *nativeNode {
+	pos := p.fullStart
+
+	switch p.token {
+	case tokenString, tokenNumber, tokenBigInt:
+		node := p.newNode(p.kinds.LiteralType, pos)
+		node.Literal = p.parseLiteral()
+		return p.finish(node)
+
+	case tokenTemplate:
+		if p.templateTail {
+			node := p.newNode(p.kinds.LiteralType, pos)
+			node.Literal = p.parseTemplate()
+			return p.finish(node)
+		}
+
+		return p.parseTemplateLiteralType()
+
+	case tokenIdentifier:
+		if p.escaped {
+			break
+		}
+
+		switch {
+		case nativeTypeKeywords[p.value] && !p.nextIs(".", false), p.value == "void":
+			return p.parseTokenNode(p.keywords[p.value])
+
+		case p.value == "true", p.value == "false", p.value == "null":
+			node := p.newNode(p.kinds.LiteralType, pos)
+			node.Literal = p.parseTokenNode(p.keywords[p.value])
+			return p.finish(node)
+
+		case p.value == "this":
+			thisType := p.parseTokenNode(p.kinds.ThisType)
+			if p.atWord("is") && !p.lineBreak {
+				node := p.newNode(p.kinds.FirstTypeNode, pos)
+				p.next()
+				node.Type = p.parseType()
+				return p.finish(node)
+			}
+
+			return thisType
+
+		case p.value == "typeof":
+			if p.nextIsWord("import", false) {
+				return p.parseImportType()
+			}
+
+			node := p.newNode(p.kinds.TypeQuery, pos)
+			p.next()
+			p.parseEntityName(true)
+			if p.at("<") && !p.lineBreak {
+				node.TypeArguments = p.parseTypeArguments()
+			}
+
+			return p.finish(node)
+
+		case p.value == "import":
+			return p.parseImportType()
+
+		case p.value == "asserts" && p.lookAhead(func() bool {
+			p.next()
+			return p.token == tokenIdentifier && !p.lineBreak
+		}):
+			node := p.newNode(p.kinds.FirstTypeNode, pos)
+			p.next()
+			p.parseIdentifierName()
+			if p.eatWord("is") {
+				node.Type = p.parseType()
+			}
+
+			return p.finish(node)
+
+		case p.value == "function" && p.nextIs("(", false):
+			return p.parseJsDocFunctionType()
+		}
+
+	case tokenPunctuation:
+		switch p.value {
+		case "-":
+			if p.lookAhead(func() bool {
+				p.next()
+				return p.token == tokenNumber || p.token == tokenBigInt
+			}) {
+				node := p.newNode(p.kinds.LiteralType, pos)
+				literal := p.newNode(p.kinds.PrefixUnaryExpression, pos)
+				p.next()
+				p.parseLiteral()
+				node.Literal = p.finish(literal)
+				return p.finish(node)
+			}
+
+		case "{":
+			if p.isStartOfMappedType() {
+				return p.parseMappedType()
+			}
+
+			node := p.newNode(p.kinds.TypeLiteral, pos)
+			node.Members = p.parseObjectTypeMembers()
+			return p.finish(node)
+
+		case "[":
+			return p.parseTupleType()
+
+		case "(":
+			node := p.newNode(p.kinds.ParenthesizedType, pos)
+			p.next()
+			node.Type = p.parseType()
+			p.expect(")")
+			return p.finish(node)
+
+		case "*":
+			return p.parseTokenNode(p.kinds.JSDocAllType)
+
+		case "?", "??":
+			// `?` alone is the unknown type, while `?string` is nullable
+			p.next()
+			switch {
+			case p.at(","), p.at("}"), p.at(")"), p.at(">"), p.at("="), p.at("|"):
+				return p.finish(p.newNode(p.kinds.JSDocUnknownType, pos))
+			}
+
+			node := p.newNode(p.kinds.JSDocNullableType, pos)
+			node.Type = p.parseType()
+			return p.finish(node)
+
+		case "!":
+			node := p.newNode(p.kinds.JSDocNonNullableType, pos)
+			p.next()
+			node.Type = p.parseNonArrayType()
+			return p.finish(node)
+		}
+	}
+
+	return p.parseTypeReference()
+}
+
+// Parse a reference to a type, like `string`, `Array<T>` or `React.ReactNode`.
+func (p *nativeFileParser) parseTypeReference() *nativeNode {
+	node := p.newNode(p.kinds.TypeReference, p.fullStart)
+
+	if p.token != tokenIdentifier {
+		p.error("Type expected.", 1110)
+		node.TypeName = p.newMissingIdentifier()
+		return p.finish(node)
+	}
+
+	node.TypeName = p.parseEntityName(true)
+
+	// JSDoc allows a dot before the type arguments, like `Array.<string>`
+	if p.inJsDocType && p.at(".") && p.nextIs("<", false) {
+		p.next()
+	}
+
+	if p.at("<") && !p.lineBreak {
+		node.TypeArguments = p.parseTypeArguments()
+	}
+
+	return p.finish(node)
+}
+
+// Parse a name that may be qualified, like `React.FC`.
+func (p *nativeFileParser) parseEntityName(allowReservedWords bool) *nativeNode {
+	pos := p.fullStart
+
+	var entity *nativeNode
+	if allowReservedWords {
+		entity = p.parseIdentifierName()
+	} else {
+		entity = p.parseIdentifier()
+	}
+
+	for p.at(".") {
+		if p.inJsDocType && p.nextIs("<", false) {
+			break
+		}
+
+		p.next()
+		node := p.newNode(p.kinds.FirstNode, pos)
+		node.Left = entity
+		node.Right = p.parseIdentifierName()
+		entity = p.finish(node)
+	}
+
+	return entity
+}
+
+// Parse the type arguments of a type or call, like `<string, number>`.
+func (p *nativeFileParser) parseTypeArguments() *nativeNodes {
+	var typeArguments nativeNodes
+	p.expect("<")
+
+	p.parseDelimitedList(">", func() {
+		typeArguments = append(typeArguments, p.parseType())
+	})
+
+	p.expect(">")
+	return listOf(typeArguments)
+}
+
+// Parse an import type, like `import('./types').Props` or `typeof import('./module')`.
+func (p *nativeFileParser) parseImportType() *nativeNode {
+	node := p.newNode(p.kinds.LastTypeNode, p.fullStart)
+
+	p.eatWord("typeof")
+	p.expectWord("import")
+	p.expect("(")
+	p.parseType()
+	p.expect(")")
+
+	if p.eat(".") {
+		p.parseEntityName(true)
+	}
+
+	if p.at("<") && !p.lineBreak {
+		node.TypeArguments = p.parseTypeArguments()
+	}
+
+	return p.finish(node)
+}
+
+// Parse a template literal type, like `${Size}-button`.
+func (p *nativeFileParser) parseTemplateLiteralType() *nativeNode {
+	node := p.newNode(p.kinds.TemplateLiteralType, p.fullStart)
+
+	for !p.templateTail {
+		p.next()
+		p.parseType()
+
+		if !p.at("}") {
+			p.error("'}' expected.", 1005)
+			return p.finish(node)
+		}
+
+		p.reScanTemplate()
+	}
+
+	p.next()
+	return p.finish(node)
+}
+
+// Check if the `{` of the current token starts a mapped type, like
+// `{ [K in keyof T]: string }`.
+func (p *nativeFileParser) isStartOfMappedType() bool {
+	return p.lookAhead(func() bool {
+		p.next()
+		if p.at("+") || p.at("-") {
+			p.next()
+			return p.atWord("readonly")
+		}
+
+		if p.atWord("readonly") {
+			p.next()
+		}
+
+		if !p.at("[") {
+			return false
+		}
+
+		p.next()
+		if !p.isIdentifier() {
+			return false
+		}
+
+		p.next()
+		return p.atWord("in")
+	})
+}
+
+func (p *nativeFileParser) parseMappedType() *nativeNode {
+	node := p.newNode(p.kinds.MappedType, p.fullStart)
+	p.expect("{")
+
+	if p.at("+") || p.at("-") {
+		p.next()
+		p.expectWord("readonly")
+	} else {
+		p.eatWord("readonly")
+	}
+
+	p.expect("[")
+	typeParameter := p.newNode(p.kinds.TypeParameter, p.fullStart)
+	typeParameter.Name = p.parseIdentifier()
+	p.expectWord("in")
+	typeParameter.Constraint = p.parseType()
+	p.finish(typeParameter)
+
+	if p.eatWord("as") {
+		p.parseType()
+	}
+
+	p.expect("]")
+
+	if p.at("+") || p.at("-") {
+		node.QuestionToken = p.parseTokenNode(p.operators[p.value])
+		p.expect("?")
+	} else if p.at("?") {
+		node.QuestionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	}
+
+	node.Type = p.parseTypeAnnotation()
+	p.parseSemicolon()
+	p.expect("}")
+
+	return p.finish(node)
+}
+
+// Parse a tuple type, like `[string, number?, ...boolean[]]` or
+// `[name: string, age?: number]`.
+func (p *nativeFileParser) parseTupleType() *nativeNode {
+	node := p.newNode(p.kinds.TupleType, p.fullStart)
+	p.expect("[")
+
+	var elements nativeNodes
+	p.parseDelimitedList("]", func() {
+		elements = append(elements, p.parseTupleElement())
+	})
+
+	node.Elements = listOf(elements)
+	p.expect("]")
+
+	return p.finish(node)
+}
+
+func (p *nativeFileParser) parseTupleElement() *nativeNode {
+	pos := p.fullStart
+
+	if p.lookAhead(func() bool {
+		p.eat("...")
+		if p.token != tokenIdentifier {
+			return false
+		}
+
+		p.next()
+		if p.at("?") {
+			p.next()
+		}
+
+		return p.at(":")
+	}) {
+		node := p.newNode(p.kinds.NamedTupleMember, pos)
+		if p.at("...") {
+			node.DotDotDotToken = p.parseTokenNode(p.kinds.DotDotDotToken)
+		}
+
+		node.Name = p.parseIdentifierName()
+		if p.at("?") {
+			node.QuestionToken = p.parseTokenNode(p.kinds.QuestionToken)
+		}
+
+		p.expect(":")
+		node.Type = p.parseType()
+
+		return p.finish(node)
+	}
+
+	if p.eat("...") {
+		node := p.newNode(p.kinds.RestType, pos)
+		node.Type = p.parseType()
+		return p.finish(node)
+	}
+
+	// `string?` within a tuple is an optional element
+	typ := p.parseType()
+	if typ.Kind == p.kinds.JSDocNullableType && typ.Type != nil && typ.pos == typ.Type.pos {
+		node := p.newNode(p.kinds.OptionalType, pos)
+		node.Type = typ.Type
+		return p.finish(node)
+	}
+
+	return typ
+}
+
+// Parse the members of an interface or type literal, within braces.
+func (p *nativeFileParser) parseObjectTypeMembers() *nativeNodes {
+	var members nativeNodes
+
+	if p.expect("{") {
+		for !p.at("}") && p.token != tokenEndOfFile {
+			if !p.isStartOfTypeMember() {
+				p.error("Property or signature expected.", 1131)
+				p.next()
+				continue
+			}
+
+			start := p.start
+			members = append(members, p.attachJsDoc(p.parseTypeMember()))
+
+			if p.start == start {
+				p.next()
+			}
+		}
+
+		p.expect("}")
+	}
+
+	return listOf(members)
+}
+
+func (p *nativeFileParser) isStartOfTypeMember() bool {
+	return p.at("(") || p.at("<") || p.at("[") || p.isLiteralPropertyName()
+}
+
+// Parse a member of an interface or type literal, like a property,
+// a method, or a call or index signature.
+func (p *nativeFileParser) parseTypeMember() *nativeNode {
+	pos := p.fullStart
+
+	if p.at("(") || p.at("<") {
+		node := p.newNode(p.kinds.CallSignature, pos)
+		p.parseSignature(node, ":", false, false)
+		p.parseTypeMemberSemicolon()
+		return p.finish(node)
+	}
+
+	if p.atWord("new") && p.lookAhead(func() bool {
+		p.next()
+		return p.at("(") || p.at("<")
+	}) {
+		node := p.newNode(p.kinds.ConstructSignature, pos)
+		p.next()
+		p.parseSignature(node, ":", false, false)
+		p.parseTypeMemberSemicolon()
+		return p.finish(node)
+	}
+
+	modifiers := p.parseModifiers(false)
+
+	if node := p.tryParseAccessor(pos, modifiers); node != nil {
+		return node
+	}
+
+	if p.isIndexSignature() {
+		return p.parseIndexSignature(pos, modifiers)
+	}
+
+	name := p.parsePropertyName()
+
+	var questionToken *nativeNode
+	if p.at("?") {
+		questionToken = p.parseTokenNode(p.kinds.QuestionToken)
+	}
+
+	var node *nativeNode
+	if p.at("(") || p.at("<") {
+		node = p.newNode(p.kinds.MethodSignature, pos)
+		node.Modifiers = modifiers
+		node.Name = name
+		node.QuestionToken = questionToken
+		p.parseSignature(node, ":", false, false)
+	} else {
+		node = p.newNode(p.kinds.PropertySignature, pos)
+		node.Modifiers = modifiers
+		node.Name = name
+		node.QuestionToken = questionToken
+		node.Type = p.parseTypeAnnotation()
+
+		if p.eat("=") {
+			node.Initializer = p.parseAssignmentExpressionAllowIn()
+		}
+	}
+
+	p.parseTypeMemberSemicolon()
+	return p.finish(node)
+}
+
+// Consume the `,` or `;` separating the members of a type.
+func (p *nativeFileParser) parseTypeMemberSemicolon() {
+	if p.eat(",") {
+		return
+	}
+
+	p.parseSemicolon()
+}
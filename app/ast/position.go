@@ -0,0 +1,105 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"sort"
+	"unicode/utf16"
+)
+
+// This file contains functions to convert the `pos` and `end`
+// offsets of the AST nodes into line numbers. Offsets are as
+// reported by Typescript, that is, in UTF-16 code units.
+
+// Compute the offsets at which each line starts in the source
+// file. This is done lazily when a line is first requested, but
+// may be called upfront so that copies of the source file share
+// the computed offsets.
+func (sf *SourceFile) ComputeLineStarts() {
+	if sf.lineStarts != nil {
+		return
+	}
+
+	sf.units = utf16.Encode([]rune(sf.Text))
+	sf.lineStarts = []int{0}
+	for index, unit := range sf.units {
+		if unit == '\n' {
+			sf.lineStarts = append(sf.lineStarts, index+1)
+		}
+	}
+}
+
+// Return the 1-based line and column where a node starts, given
+// its `pos`. Leading whitespace and comments, which Typescript
+// includes in the node, are skipped.
+func (sf *SourceFile) GetStartLineAndColumn(pos int) (int, int) {
+	sf.ComputeLineStarts()
+	return sf.getLineAndColumn(sf.skipTrivia(pos))
+}
+
+// Return the 1-based line where a node ends, given its `end`.
+func (sf *SourceFile) GetEndLine(end int) int {
+	sf.ComputeLineStarts()
+
+	// `end` is exclusive, the last character is just before it
+	if end > 0 {
+		end--
+	}
+
+	line, _ := sf.getLineAndColumn(end)
+	return line
+}
+
+func (sf *SourceFile) getLineAndColumn(offset int) (int, int) {
+	// find the last line that starts at or before the offset
+	line := sort.Search(len(sf.lineStarts), func(index int) bool {
+		return sf.lineStarts[index] > offset
+	})
+
+	if line == 0 {
+		return 1, 1
+	}
+
+	return line, offset - sf.lineStarts[line-1] + 1
+}
+
+// Skip all whitespace and comments starting at the given offset
+// and return the offset of the first significant character.
+func (sf *SourceFile) skipTrivia(offset int) int {
+	units := sf.units
+	length := len(units)
+
+	for offset < length {
+		switch {
+		case units[offset] == ' ' || units[offset] == '\t' || units[offset] == '\r' || units[offset] == '\n':
+			offset++
+
+		case units[offset] == '/' && offset+1 < length && units[offset+1] == '/':
+			for offset < length && units[offset] != '\n' {
+				offset++
+			}
+
+		case units[offset] == '/' && offset+1 < length && units[offset+1] == '*':
+			offset += 2
+			for offset+1 < length && !(units[offset] == '*' && units[offset+1] == '/') {
+				offset++
+			}
+			offset += 2
+
+		default:
+			return offset
+		}
+	}
+
+	return offset
+}
@@ -0,0 +1,79 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// This file contains functions to prune the Typescript AST inside
+// the QuickJS runtime, before it is converted to JSON and read into
+// Go. Only the properties the Go types read are kept, which leaves
+// out parent pointers, flags, trivia and other internal properties
+// of the Typescript nodes.
+
+// Properties read from raw JSON rather than from Go types, such
+// as the chain of messages of a diagnostic
+var extraAstKeys = []string{"next"}
+
+// Return the names of all properties read by the Go types that
+// make up a `SourceFile`, as the JSON array used to whitelist the
+// properties when calling `JSON.stringify` in the QuickJS runtime.
+func getAstKeysJson() string {
+	keys := make(map[string]bool)
+	collectJsonKeys(reflect.TypeOf(SourceFile{}), keys, make(map[reflect.Type]bool))
+
+	for _, key := range extraAstKeys {
+		keys[key] = true
+	}
+
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	keysJson, _ := json.Marshal(names)
+	return string(keysJson)
+}
+
+// Collect the JSON property names of the given type and all
+// types reachable from its fields.
+func collectJsonKeys(typ reflect.Type, keys map[string]bool, seen map[reflect.Type]bool) {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for index := 0; index < typ.NumField(); index++ {
+		field := typ.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		keys[name] = true
+		collectJsonKeys(field.Type, keys, seen)
+	}
+}
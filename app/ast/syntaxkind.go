@@ -80,6 +80,7 @@ type SyntaxKind struct {
 	QuestionQuestionToken                        int
 	BacktickToken                                int
 	HashToken                                    int
+	EqualsToken                                  int
 	FirstAssignment                              int
 	FirstCompoundAssignment                      int
 	MinusEqualsToken                             int
@@ -364,6 +365,7 @@ type SyntaxKind struct {
 	JSDocTypeTag                                 int
 	JSDocTemplateTag                             int
 	JSDocTypedefTag                              int
+	JSDocPropertyTag                             int
 	JSDocSeeTag                                  int
 	LastJSDocTagNode                             int
 	SyntaxList                                   int
@@ -418,6 +420,14 @@ func (sk *SyntaxKind) IsImportDeclaration(node AstNode) bool {
 	return node.GetKind() == sk.ImportDeclaration
 }
 
+func (sk *SyntaxKind) IsModuleDeclaration(node AstNode) bool {
+	return node.GetKind() == sk.ModuleDeclaration
+}
+
+func (sk *SyntaxKind) IsExportDeclaration(node AstNode) bool {
+	return node.GetKind() == sk.ExportDeclaration
+}
+
 func (sk *SyntaxKind) IsPropertyAccessExpression(node AstNode) bool {
 	return node.GetKind() == sk.PropertyAccessExpression
 }
@@ -460,7 +470,41 @@ func (sk *SyntaxKind) HasMethodOfName(statement Statement, methodName string) bo
 	return false
 }
 
-func GetJsDoc(jsDoc []AstObject) string {
+// Return the name of the class declared by the statement, or an
+// empty string if the statement is not a class declaration.
+func (sk *SyntaxKind) GetClassName(statement *Statement) string {
+	if !sk.IsClassDeclaration(statement) || statement.Name == nil {
+		return ""
+	}
+
+	return statement.Name.EscapedText
+}
+
+func (sk *SyntaxKind) HasExportModifier(statement *Statement) bool {
+	return sk.hasModifier(statement.Modifiers, sk.ExportKeyword)
+}
+
+func (sk *SyntaxKind) HasDefaultModifier(statement *Statement) bool {
+	return sk.hasModifier(statement.Modifiers, sk.DefaultKeyword)
+}
+
+// Check if the member has a `static` modifier applied to it or not.
+// This is usually checked when reading default props.
+func (sk *SyntaxKind) HasStaticModifier(member *Member) bool {
+	return sk.hasModifier(member.Modifiers, sk.StaticKeyword)
+}
+
+func (sk *SyntaxKind) hasModifier(modifiers []AstObject, kind int) bool {
+	for _, modifier := range modifiers {
+		if modifier.Kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func GetJsDoc(jsDoc []JsDoc) string {
 	if len(jsDoc) == 0 {
 		return ""
 	}
@@ -580,3 +624,267 @@ func (sk *SyntaxKind) IsCallExpression(node AstNode) bool {
 func (sk *SyntaxKind) IsParenthesizedExpression(node AstNode) bool {
 	return node.GetKind() == sk.ParenthesizedExpression
 }
+
+func (sk *SyntaxKind) IsBinaryExpression(node AstNode) bool {
+	return node.GetKind() == sk.BinaryExpression
+}
+
+func (sk *SyntaxKind) IsPropertyDeclaration(node AstNode) bool {
+	return node.GetKind() == sk.PropertyDeclaration
+}
+
+// Check if the expression is a simple assignment of the form
+// `Parent.Child = Value`, where the left hand side is a property
+// access on an identifier.
+func (sk *SyntaxKind) IsPropertyAssignmentExpression(expr *Expression) bool {
+	if expr == nil || !sk.IsBinaryExpression(expr) {
+		return false
+	}
+
+	if expr.OperatorToken == nil || expr.OperatorToken.Kind != sk.EqualsToken {
+		return false
+	}
+
+	if expr.Left == nil || !sk.IsPropertyAccessExpression(expr.Left) {
+		return false
+	}
+
+	return expr.Left.Expression != nil && sk.IsIdentifier(expr.Left.Expression) && expr.Left.Name != nil
+}
+
+func (sk *SyntaxKind) IsTypeReference(node AstNode) bool {
+	return node.GetKind() == sk.TypeReference
+}
+
+func (sk *SyntaxKind) IsMethodSignature(node AstNode) bool {
+	return node.GetKind() == sk.MethodSignature
+}
+
+// Render the given type node as readable source text, for example
+// `Array<string>`, `React.MouseEvent<T>` or `(value?: string) => void`.
+// Returns the unknown type marker if the type cannot be rendered.
+func (sk *SyntaxKind) GetTypeText(node *TypeReference) string {
+	return sk.GetSubstitutedTypeText(node, nil)
+}
+
+// Render the given type node as readable source text, replacing
+// any type parameter named in `substitutions` with its type argument.
+// This is used when a generic props interface such as `SelectProps<V>`
+// is referenced as `SelectProps<T>`, so that `V` renders as `T`.
+func (sk *SyntaxKind) GetSubstitutedTypeText(node *TypeReference, substitutions map[string]string) string {
+	if node == nil {
+		return ""
+	}
+
+	switch node.Kind {
+	case sk.TypeReference:
+		name := GetEntityName(node.TypeName)
+		if len(node.TypeArguments) == 0 {
+			if substitute, exists := substitutions[name]; exists {
+				return substitute
+			}
+
+			return name
+		}
+
+		return name + "<" + sk.joinTypeTexts(node.TypeArguments, ", ", substitutions) + ">"
+
+	case sk.ArrayType:
+		return sk.GetSubstitutedTypeText(node.ElementType, substitutions) + "[]"
+
+	case sk.UnionType:
+		return sk.joinTypeTexts(node.Types, " | ", substitutions)
+
+	case sk.IntersectionType:
+		return sk.joinTypeTexts(node.Types, " & ", substitutions)
+
+	case sk.ParenthesizedType:
+		return "(" + sk.GetSubstitutedTypeText(node.TypeValue, substitutions) + ")"
+
+	case sk.LiteralType:
+		return sk.GetLiteralText(node.Literal)
+
+	case sk.FunctionType:
+		return "(" + sk.getParametersText(node.Parameters, substitutions) + ") => " + sk.GetSubstitutedTypeText(node.TypeValue, substitutions)
+
+	case sk.JSDocAllType:
+		return "any"
+
+	case sk.JSDocUnknownType:
+		return "unknown"
+
+	case sk.JSDocNullableType:
+		return sk.GetSubstitutedTypeText(node.TypeValue, substitutions) + " | null"
+
+	case sk.JSDocNonNullableType, sk.JSDocOptionalType:
+		return sk.GetSubstitutedTypeText(node.TypeValue, substitutions)
+
+	case sk.JSDocVariadicType:
+		return "..." + sk.GetSubstitutedTypeText(node.TypeValue, substitutions)
+
+	case sk.JSDocFunctionType:
+		return "(" + sk.getParametersText(node.Parameters, substitutions) + ") => " + sk.GetSubstitutedTypeText(node.TypeValue, substitutions)
+
+	case sk.TypeLiteral:
+		parts := make([]string, 0, len(node.Members))
+		for _, member := range node.Members {
+			if member.Name == nil {
+				continue
+			}
+
+			name := member.Name.EscapedText
+			if member.QuestionToken != nil {
+				name += "?"
+			}
+
+			parts = append(parts, name+": "+sk.GetSubstitutedTypeText(member.TypeReference, substitutions))
+		}
+
+		if len(parts) == 0 {
+			return "{}"
+		}
+
+		return "{ " + strings.Join(parts, "; ") + " }"
+	}
+
+	return sk.GetType(node)
+}
+
+// Render a list of function parameters as source text, such as
+// `value: string, index?: number, ...rest: any[]`.
+func (sk *SyntaxKind) GetParametersText(params []Parameter) string {
+	return sk.getParametersText(params, nil)
+}
+
+func (sk *SyntaxKind) getParametersText(params []Parameter, substitutions map[string]string) string {
+	parts := make([]string, 0, len(params))
+	for _, param := range params {
+		var sb strings.Builder
+		if param.DotDotDotToken != nil {
+			sb.WriteString("...")
+		}
+
+		if param.Name != nil {
+			sb.WriteString(param.Name.EscapedText)
+		}
+
+		if param.QuestionToken != nil {
+			sb.WriteRune('?')
+		}
+
+		if param.TypeReference != nil {
+			sb.WriteString(": ")
+			sb.WriteString(sk.GetSubstitutedTypeText(param.TypeReference, substitutions))
+		}
+
+		parts = append(parts, sb.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Collect the names of all types referenced within the given type
+// node, including type arguments, union members, array elements and
+// function parameters. Qualified names are returned in full.
+func (sk *SyntaxKind) GetReferencedTypeNames(node *TypeReference) []string {
+	if node == nil {
+		return nil
+	}
+
+	var names []string
+	if node.Kind == sk.TypeReference {
+		names = append(names, GetEntityName(node.TypeName))
+	}
+
+	for index := range node.TypeArguments {
+		names = append(names, sk.GetReferencedTypeNames(&node.TypeArguments[index])...)
+	}
+
+	for index := range node.Types {
+		names = append(names, sk.GetReferencedTypeNames(&node.Types[index])...)
+	}
+
+	for _, param := range node.Parameters {
+		names = append(names, sk.GetReferencedTypeNames(param.TypeReference)...)
+	}
+
+	for _, member := range node.Members {
+		names = append(names, sk.GetReferencedTypeNames(member.TypeReference)...)
+	}
+
+	names = append(names, sk.GetReferencedTypeNames(node.ElementType)...)
+	names = append(names, sk.GetReferencedTypeNames(node.TypeValue)...)
+
+	return names
+}
+
+// Return the text of a literal value as it appears in source, with
+// string literals quoted.
+func (sk *SyntaxKind) GetLiteralText(literal *AstObject) string {
+	if literal == nil {
+		return ""
+	}
+
+	switch literal.Kind {
+	case sk.StringLiteral:
+		return "'" + literal.Text + "'"
+
+	case sk.TrueKeyword:
+		return "true"
+
+	case sk.FalseKeyword:
+		return "false"
+
+	case sk.NullKeyword:
+		return "null"
+	}
+
+	return literal.Text
+}
+
+func (sk *SyntaxKind) joinTypeTexts(types []TypeReference, separator string, substitutions map[string]string) string {
+	parts := make([]string, len(types))
+	for index := range types {
+		parts[index] = sk.GetSubstitutedTypeText(&types[index], substitutions)
+	}
+
+	return strings.Join(parts, separator)
+}
+
+// Return the full name of an identifier or a qualified name, such
+// as `React.MouseEvent`.
+func GetEntityName(name *AstObject) string {
+	if name == nil {
+		return ""
+	}
+
+	if name.Left != nil && name.Right != nil {
+		return GetEntityName(name.Left) + "." + GetEntityName(name.Right)
+	}
+
+	return name.EscapedText
+}
+
+func (sk *SyntaxKind) IsObjectLiteralExpression(node AstNode) bool {
+	return node.GetKind() == sk.ObjectLiteralExpression
+}
+
+func (sk *SyntaxKind) IsArrayLiteralExpression(node AstNode) bool {
+	return node.GetKind() == sk.ArrayLiteralExpression
+}
+
+func (sk *SyntaxKind) IsJsDocParameterTag(node AstNode) bool {
+	return node.GetKind() == sk.JSDocParameterTag
+}
+
+func (sk *SyntaxKind) IsJsDocTypedefTag(node AstNode) bool {
+	return node.GetKind() == sk.JSDocTypedefTag
+}
+
+func (sk *SyntaxKind) IsJsDocOptionalType(node AstNode) bool {
+	return node.GetKind() == sk.JSDocOptionalType
+}
+
+func (sk *SyntaxKind) IsJsDocFunctionType(node AstNode) bool {
+	return node.GetKind() == sk.JSDocFunctionType
+}
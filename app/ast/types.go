@@ -21,11 +21,13 @@ type AstNode interface {
 }
 
 type AstObject struct {
-	EscapedText              string `json:"escapedText"`
-	Comment                  string `json:"comment"`
-	Text                     string `json:"text"`
-	HasExtendedUnicodeEscape bool   `json:"hasExtendedUnicodeEscape"`
-	Kind                     int    `json:"kind"`
+	EscapedText              string     `json:"escapedText"`
+	Comment                  string     `json:"comment"`
+	Text                     string     `json:"text"`
+	HasExtendedUnicodeEscape bool       `json:"hasExtendedUnicodeEscape"`
+	Left                     *AstObject `json:"left"`
+	Right                    *AstObject `json:"right"`
+	Kind                     int        `json:"kind"`
 }
 
 type AstType struct {
@@ -56,15 +58,44 @@ type Expression struct {
 	Children                 []AstObject  `json:"children"`
 	ClosingElement           *JsxElement  `json:"closingElement"`
 	Arguments                []Expression `json:"arguments"`
+	Left                     *Expression  `json:"left"`
+	OperatorToken            *AstObject   `json:"operatorToken"`
+	Right                    *Expression  `json:"right"`
+	Properties               []Property   `json:"properties"`
+	Elements                 []Expression `json:"elements"`
+}
+
+type JsDoc struct {
+	Comment string     `json:"comment"`
+	Tags    []JsDocTag `json:"tags"`
+	Kind    int        `json:"kind"`
+}
+
+type JsDocTag struct {
+	TagName        *AstObject           `json:"tagName"`
+	Name           *AstObject           `json:"name"`
+	TypeExpression *JsDocTypeExpression `json:"typeExpression"`
+	Comment        string               `json:"comment"`
+	IsBracketed    bool                 `json:"isBracketed"`
+	Pos            int                  `json:"pos"`
+	End            int                  `json:"end"`
+	Kind           int                  `json:"kind"`
+}
+
+type JsDocTypeExpression struct {
+	TypeValue         *TypeReference `json:"type"`
+	JsDocPropertyTags []JsDocTag     `json:"jsDocPropertyTags"`
+	Kind              int            `json:"kind"`
 }
 
 type JsxElement struct {
 	TagName    *AstObject     `json:"tagName"`
-	Attributes []JsxAttribute `json:"attributes"`
+	Attributes *JsxAttributes `json:"attributes"`
 	Kind       int            `json:"kind"`
 }
 
-type JsxAttribute struct {
+// The attributes of a JSX element, held by a single node
+type JsxAttributes struct {
 	Properties []Member `json:"properties"`
 	Kind       int      `json:"kind"`
 }
@@ -82,8 +113,9 @@ type ImportClause struct {
 }
 
 type Initializer struct {
-	Properties []Property `json:"properties"`
-	Kind       int        `json:"kind"`
+	Properties  []Property `json:"properties"`
+	EscapedText string     `json:"escapedText"`
+	Kind        int        `json:"kind"`
 }
 
 type LiteralType struct {
@@ -95,9 +127,12 @@ type Member struct {
 	Name          *AstObject     `json:"name"`
 	TypeReference *TypeReference `json:"type"`
 	QuestionToken *AstObject     `json:"questionToken"`
-	JsDoc         []AstObject    `json:"jsDoc"`
+	JsDoc         []JsDoc        `json:"jsDoc"`
 	Modifiers     []AstObject    `json:"modifiers"`
 	Initializer   *Initializer   `json:"initializer"`
+	Parameters    []Parameter    `json:"parameters"`
+	Pos           int            `json:"pos"`
+	End           int            `json:"end"`
 	Kind          int            `json:"kind"`
 }
 
@@ -113,39 +148,65 @@ type NamedBindings struct {
 }
 
 type Parameter struct {
-	Name          *AstObject     `json:"name"`
-	TypeReference *TypeReference `json:"type"`
-	Kind          int            `json:"kind"`
+	Name           *AstObject     `json:"name"`
+	TypeReference  *TypeReference `json:"type"`
+	QuestionToken  *AstObject     `json:"questionToken"`
+	DotDotDotToken *AstObject     `json:"dotDotDotToken"`
+	Initializer    *AstObject     `json:"initializer"`
+	Kind           int            `json:"kind"`
 }
 
 type Property struct {
-	Name        *AstObject `json:"name"`
-	Initializer *AstObject `json:"initializer"`
-	Kind        int        `json:"kind"`
+	Name        *AstObject  `json:"name"`
+	Initializer *Expression `json:"initializer"`
+	JsDoc       []JsDoc     `json:"jsDoc"`
+	Pos         int         `json:"pos"`
+	End         int         `json:"end"`
+	Kind        int         `json:"kind"`
 }
 
 type SourceFile struct {
-	Statements []Statement `json:"statements"`
-	Kind       int         `json:"kind"`
+	FileName         string         `json:"fileName"`
+	Statements       []Statement    `json:"statements"`
+	ParseDiagnostics []TsDiagnostic `json:"parseDiagnostics"`
+	Text             string         `json:"text"`
+	Kind             int            `json:"kind"`
 
 	importsResolved bool
 	imports         map[string]string
+	importNames     map[string]string        // the names of imported symbols, keyed by their local name
+	modules         *Modules                 // the modules that may be imported by this file
+	checkedProps    map[string][]CheckedProp // props resolved by the type checker, keyed by component name
+	units           []uint16                 // the source text as UTF-16 code units
+	lineStarts      []int                    // offsets at which each line starts
+	syntax          *SyntaxKind              // the syntax kinds of the parser that created this file
 }
 
 type Statement struct {
 	ImportClause    *ImportClause    `json:"importClause"`
 	ModuleSpecifier *ModuleSpecifier `json:"moduleSpecifier"`
+	ExportClause    *NamedBindings   `json:"exportClause"`
 	Name            *AstObject       `json:"name"`
 	Body            *Block           `json:"body"`
 	Expression      *Expression      `json:"expression"`
 	HeritageClauses []HeritageClause `json:"heritageClauses"`
 	Modifiers       []AstObject      `json:"modifiers"`
 	Members         []Member         `json:"members"`
-	JsDoc           []AstObject      `json:"jsDoc"`
+	JsDoc           []JsDoc          `json:"jsDoc"`
 	Parameters      []Parameter      `json:"parameters"`
+	TypeParameters  []TypeParameter  `json:"typeParameters"`
+	Pos             int              `json:"pos"`
+	End             int              `json:"end"`
 	Kind            int              `json:"kind"`
 }
 
+type TypeParameter struct {
+	Name       *AstObject     `json:"name"`
+	Constraint *TypeReference `json:"constraint"`
+	Default    *TypeReference `json:"default"`
+	Kind       int            `json:"kind"`
+}
+
 type TypeValue struct {
 	Expression    *Expression     `json:"expression"`
 	TypeArguments []TypeReference `json:"typeArguments"`
@@ -153,12 +214,16 @@ type TypeValue struct {
 }
 
 type TypeReference struct {
-	TypeName   *AstObject      `json:"typeName"`
-	TypeValue  *AstObject      `json:"type"`
-	Types      []TypeReference `json:"types"`
-	Parameters []Parameter     `json:"parameters"`
-	Literal    *AstObject      `json:"literal"`
-	Kind       int             `json:"kind"`
+	TypeName          *AstObject      `json:"typeName"`
+	TypeValue         *TypeReference  `json:"type"`
+	TypeArguments     []TypeReference `json:"typeArguments"`
+	ElementType       *TypeReference  `json:"elementType"`
+	Types             []TypeReference `json:"types"`
+	Members           []Member        `json:"members"`
+	Parameters        []Parameter     `json:"parameters"`
+	Literal           *AstObject      `json:"literal"`
+	JsDocPropertyTags []JsDocTag      `json:"jsDocPropertyTags"`
+	Kind              int             `json:"kind"`
 }
 
 // implement AstNode interface
@@ -183,6 +248,18 @@ func (ast *Expression) GetKind() int {
 	return ast.Kind
 }
 
+func (ast *JsDoc) GetKind() int {
+	return ast.Kind
+}
+
+func (ast *JsDocTag) GetKind() int {
+	return ast.Kind
+}
+
+func (ast *JsDocTypeExpression) GetKind() int {
+	return ast.Kind
+}
+
 func (ast *ImportClause) GetKind() int {
 	return ast.Kind
 }
@@ -227,6 +304,10 @@ func (ast *Statement) GetKind() int {
 	return ast.Kind
 }
 
+func (ast *TypeParameter) GetKind() int {
+	return ast.Kind
+}
+
 func (ast *TypeValue) GetKind() int {
 	return ast.Kind
 }
@@ -237,38 +318,16 @@ func (ast *TypeReference) GetKind() int {
 
 // convenience methods
 
-func (st *Statement) GetClassName() string {
-	if !Syntax.IsClassDeclaration(st) {
-		panic("Expected a class declaration")
-	}
-
-	return st.Name.EscapedText
-}
-
-func (st *Statement) HasExportModifier() bool {
-	for _, modifier := range st.Modifiers {
-		if modifier.Kind == Syntax.ExportKeyword {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (st *Statement) HasDefaultModifier() bool {
-	for _, modifier := range st.Modifiers {
-		if modifier.Kind == Syntax.DefaultKeyword {
-			return true
-		}
-	}
-
-	return false
-}
-
 func (st *Statement) HasHeritageClauses() bool {
 	return len(st.HeritageClauses) > 0
 }
 
+// Return the syntax kinds of the parser that created the source
+// file, which are needed to tell the kinds of its nodes apart.
+func (sf *SourceFile) GetSyntaxKind() *SyntaxKind {
+	return sf.syntax
+}
+
 func (sf *SourceFile) GetImportPath(key string) string {
 	if !sf.importsResolved {
 		sf.resolveImports()
@@ -286,8 +345,8 @@ func (sf *SourceFile) IsNameExported(name string) bool {
 
 	for _, st := range sf.Statements {
 		// check we this is of form `export MyComponent`
-		if Syntax.IsExpressionStatement(&st) {
-			if st.Expression != nil && Syntax.IsIdentifier(st.Expression) && st.Expression.EscapedText == name {
+		if sf.syntax.IsExpressionStatement(&st) {
+			if st.Expression != nil && sf.syntax.IsIdentifier(st.Expression) && st.Expression.EscapedText == name {
 				return true
 			}
 
@@ -296,19 +355,19 @@ func (sf *SourceFile) IsNameExported(name string) bool {
 
 		// check if we have an export assignment
 		// of the form is `export injectIntl(MyComponent)`
-		if Syntax.IsExportAssignment(&st) {
+		if sf.syntax.IsExportAssignment(&st) {
 			if st.Expression != nil {
 				// for `export default injectIntl(MyComponent)`
-				if Syntax.IsCallExpression(st.Expression) && len(st.Expression.Arguments) > 0 {
+				if sf.syntax.IsCallExpression(st.Expression) && len(st.Expression.Arguments) > 0 {
 					for _, arg := range st.Expression.Arguments {
-						if Syntax.IsIdentifier(&arg) && arg.EscapedText == name {
+						if sf.syntax.IsIdentifier(&arg) && arg.EscapedText == name {
 							return true
 						}
 					}
 				}
 
 				// for simple `export MyComponent`
-				if Syntax.IsIdentifier(st.Expression) && st.Expression.EscapedText == name {
+				if sf.syntax.IsIdentifier(st.Expression) && st.Expression.EscapedText == name {
 					return true
 				}
 			}
@@ -327,10 +386,12 @@ func (sf *SourceFile) resolveImports() {
 
 	if sf.imports == nil {
 		sf.imports = make(map[string]string, 0)
+		sf.importNames = make(map[string]string, 0)
 	}
 
 	for _, st := range sf.Statements {
-		if !Syntax.IsImportDeclaration(&st) {
+		// skip side-effect imports like `import './styles.css'`
+		if !sf.syntax.IsImportDeclaration(&st) || st.ImportClause == nil || st.ModuleSpecifier == nil {
 			continue
 		}
 
@@ -338,6 +399,7 @@ func (sf *SourceFile) resolveImports() {
 		library := st.ModuleSpecifier.Text
 		if st.ImportClause.Name != nil {
 			sf.imports[st.ImportClause.Name.EscapedText] = library
+			sf.importNames[st.ImportClause.Name.EscapedText] = "default"
 		}
 
 		if st.ImportClause.NamedBindings != nil {
@@ -347,6 +409,7 @@ func (sf *SourceFile) resolveImports() {
 
 			for _, element := range st.ImportClause.NamedBindings.Elements {
 				sf.imports[element.Name.EscapedText] = library
+				sf.importNames[element.Name.EscapedText] = element.GetImportedName()
 			}
 		}
 	}
@@ -358,7 +421,7 @@ func (sf *SourceFile) resolveImports() {
 // class declaration found returns `true`.
 func (sf *SourceFile) HasClassDeclaration() bool {
 	for _, statement := range sf.Statements {
-		if Syntax.IsClassDeclaration(&statement) {
+		if sf.syntax.IsClassDeclaration(&statement) {
 			return true
 		}
 	}
@@ -370,17 +433,113 @@ func (sf *SourceFile) HasClassDeclaration() bool {
 // The type represented here is mostly an interface and specifies
 // the props of the React component
 func (sf *SourceFile) GetMembersOfType(typeName string) []Member {
+	_, declaration := sf.FindInterface(typeName)
+	if declaration == nil {
+		return nil
+	}
+
+	return declaration.Members
+}
+
+// Given a type name find the type parameters declared by the type in
+// the source file, such as `T` in `interface SelectProps<T>`. Returns
+// `nil` if the type is not generic or not declared in this file.
+func (sf *SourceFile) GetTypeParametersOfType(typeName string) []TypeParameter {
+	_, declaration := sf.FindInterface(typeName)
+	if declaration == nil {
+		return nil
+	}
+
+	return declaration.TypeParameters
+}
+
+// Find the interface declaration of the given name, either in this
+// source file or, if the name is imported, in the file it is imported
+// from. Returns the source file declaring the interface along with the
+// declaration, or `nil` values if the interface cannot be found.
+func (sf *SourceFile) FindInterface(typeName string) (*SourceFile, *Statement) {
+	return sf.findInterface(typeName, 0)
+}
+
+func (sf *SourceFile) findInterface(typeName string, depth int) (*SourceFile, *Statement) {
 	// is this an imported typeName
 	importLibrary := sf.GetImportPath(typeName)
+	if len(importLibrary) > 0 {
+		return sf.findInterfaceInLibrary(importLibrary, sf.importNames[typeName], depth)
+	}
+
+	if declaration := sf.findInterfaceInStatements(sf.Statements, typeName); declaration != nil {
+		return sf, declaration
+	}
+
+	return nil, nil
+}
+
+// Find the interface declaration of the given name in the statements,
+// including those within namespaces such as `declare namespace React`,
+// as used by the type declarations of many packages.
+func (sf *SourceFile) findInterfaceInStatements(statements []Statement, typeName string) *Statement {
+	for index := range statements {
+		statement := &statements[index]
+		if sf.syntax.IsInterfaceDeclaration(statement) {
+			if statement.Name != nil && typeName == statement.Name.EscapedText {
+				return statement
+			}
+		}
+	}
+
+	for index := range statements {
+		statement := &statements[index]
+		if sf.syntax.IsModuleDeclaration(statement) && statement.Body != nil {
+			if declaration := sf.findInterfaceInStatements(statement.Body.Statements, typeName); declaration != nil {
+				return declaration
+			}
+		}
+	}
+
+	return nil
+}
+
+// Find the interface declaration referred to by the expression of
+// a heritage clause, such as `BaseProps` or `React.HTMLAttributes`
+// in `interface ButtonProps extends BaseProps, React.HTMLAttributes<T>`.
+func (sf *SourceFile) FindInterfaceOfExpression(expr *Expression) (*SourceFile, *Statement) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	if sf.syntax.IsIdentifier(expr) {
+		return sf.FindInterface(expr.EscapedText)
+	}
+
+	if !sf.syntax.IsPropertyAccessExpression(expr) || expr.Name == nil || expr.Expression == nil || !sf.syntax.IsIdentifier(expr.Expression) {
+		return nil, nil
+	}
 
+	// a member of an imported namespace, like `React` in `import * as React from 'react'`
+	importLibrary := sf.GetImportPath(expr.Expression.EscapedText)
 	if len(importLibrary) > 0 {
-		return sf.GetMembersOfTypeFromLibrary(importLibrary, typeName)
+		return sf.findInterfaceInLibrary(importLibrary, expr.Name.EscapedText, 0)
 	}
 
+	// a member of a namespace declared in this file
+	if declaration := sf.findInterfaceInStatements(sf.Statements, expr.Name.EscapedText); declaration != nil {
+		return sf, declaration
+	}
+
+	return nil, nil
+}
+
+// Find the `@typedef` of the given name in any of the JSDoc comments
+// of the source file, such as `@typedef {Object} ButtonProps`.
+// Returns `nil` if no such type is defined.
+func (sf *SourceFile) GetJsDocTypedef(typeName string) *JsDocTag {
 	for _, statement := range sf.Statements {
-		if Syntax.IsInterfaceDeclaration(&statement) {
-			if statement.Name != nil && typeName == statement.Name.EscapedText {
-				return statement.Members
+		for _, jsDoc := range statement.JsDoc {
+			for index, tag := range jsDoc.Tags {
+				if sf.syntax.IsJsDocTypedefTag(&tag) && tag.Name != nil && tag.Name.EscapedText == typeName {
+					return &jsDoc.Tags[index]
+				}
 			}
 		}
 	}
@@ -392,17 +551,34 @@ func (sf *SourceFile) GetMembersOfType(typeName string) []Member {
 // or import path. This usually happens when we want to pull props or extend
 // props from an interface defined else where in the code.
 func (sf *SourceFile) GetMembersOfTypeFromLibrary(importLibrary string, typeName string) []Member {
-	return nil
+	_, declaration := sf.findInterfaceInLibrary(importLibrary, typeName, 0)
+	if declaration == nil {
+		return nil
+	}
+
+	return declaration.Members
 }
 
-// Check if the member has a `static` modifier applied to it or not.
-// This is usually checked when reading default props.
-func (member *Member) HasStaticModifier() bool {
-	for _, modifier := range member.Modifiers {
-		if modifier.Kind == Syntax.StaticKeyword {
-			return true
-		}
+// Return the name of the symbol being imported, which differs from
+// the local name for imports like `import { Props as ButtonProps }`.
+func (element *Element) GetImportedName() string {
+	if element.PropertyName.EscapedText != "" {
+		return element.PropertyName.EscapedText
 	}
 
-	return false
+	return element.Name.EscapedText
+}
+
+// Return the name of the property, which may be an identifier
+// or a string literal such as `'aria-label'`.
+func (property *Property) GetName() string {
+	if property.Name == nil {
+		return ""
+	}
+
+	if property.Name.EscapedText != "" {
+		return property.Name.EscapedText
+	}
+
+	return property.Name.Text
 }
@@ -0,0 +1,401 @@
+//go:build cgo
+
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quickjs-go/quickjs-go"
+)
+
+// This file contains the parser that runs the Typescript compiler
+// in the QuickJS runtime, which needs cgo.
+
+/**
+ * The values of the Typescript `ScriptKind` enum, read from
+ * the Typescript code once initialized in QuickJS runtime.
+ */
+type ScriptKind struct {
+	JS  int
+	JSX int
+	TS  int
+	TSX int
+}
+
+/**
+ * A `struct` to store and pass various QuickJS runtime objects
+ * down the function chain.
+ */
+type tsParser struct {
+	runtime          *quickjs.Runtime
+	context          *quickjs.Context
+	globals          *quickjs.Value
+	codeParser       *quickjs.Value
+	stringify        *quickjs.Value
+	circularReplacer *quickjs.Value
+	astKeys          *quickjs.Value    // the properties to keep when converting the AST to JSON
+	keepFullAst      bool              // convert the full AST to JSON, used to measure the gain of pruning
+	syntaxKind       *SyntaxKind       // the syntax kinds as defined by Typescript
+	scriptKind       ScriptKind        // the script kinds as defined by Typescript
+	scriptTargets    map[string]int    // the script targets as defined by Typescript, keyed by lower-case name
+	scriptTarget     int               // the script target to parse all files with
+	options          *ParseOptions     // the options the parser was created with, may be `nil`
+	limits           *runtimeLimits    // the limits put on the QuickJS runtime
+	failed           error             // why the runtime could not be replaced, after which nothing can be parsed
+	libFiles         map[string]string // the declaration files of the default library, keyed by path, read when first needed
+}
+
+// Create a parser running the Typescript compiler in the QuickJS
+// runtime, using the script target from the options. Returns an error
+// if the Typescript compiler cannot be loaded.
+func newTypescriptParser(options *ParseOptions) (Parser, error) {
+	parser := &tsParser{options: options}
+	err := parser.init()
+	if err != nil {
+		parser.Free()
+		return nil, err
+	}
+
+	// use the configured target, if any
+	if options != nil {
+		parser.setScriptTarget(options.Target)
+	}
+
+	return parser, nil
+}
+
+func (parser *tsParser) ParseFile(ctx context.Context, fileName string, contents string) (*SourceFile, []Diagnostic) {
+	if parser.failed != nil {
+		return nil, []Diagnostic{newErrorDiagnostic(fileName, parser.failed)}
+	}
+
+	var sourceFile *SourceFile
+	var diagnostics []Diagnostic
+
+	err := parser.limits.run(ctx, func() error {
+		var err error
+		sourceFile, diagnostics, err = parseSingleFileContents(fileName, contents, parser)
+		return err
+	})
+
+	if err != nil {
+		// a runtime that was stopped midway, or ran out of memory
+		// or stack, is replaced before parsing the next file
+		if errors.Is(err, errTimedOut) || isRuntimeExhausted(err) {
+			parser.recycle()
+		}
+
+		return nil, []Diagnostic{newErrorDiagnostic(fileName, err)}
+	}
+
+	return sourceFile, diagnostics
+}
+
+func (parser *tsParser) GetSyntaxKind() *SyntaxKind {
+	return parser.syntaxKind
+}
+
+// Parse the contents of a file/or supplied from memory
+// using the Typescript parser and return the `SourceFile` AST,
+// along with any problems Typescript found when parsing it.
+// The file name decides the script kind the file is parsed as.
+// If the file could not be parsed at all, the error is returned.
+func parseSingleFileContents(fileName string, sourceCode string, parser *tsParser) (*SourceFile, []Diagnostic, error) {
+	// create argument list to call the method
+	args := make([]quickjs.Value, 5)
+	args[0] = parser.context.String(fileName)
+	args[1] = parser.context.String(string(sourceCode))
+	args[2] = parser.context.Int32(int32(parser.scriptTarget))
+	args[3] = parser.context.Bool(true)
+	args[4] = parser.context.Int32(int32(parser.getScriptKind(fileName)))
+
+	// invoke the "createSourceFile" method
+	result, err := parser.context.Call(*parser.globals, *parser.codeParser, args)
+	defer result.Free()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// keep only the properties read into Go, which also leaves
+	// out the parent pointers that make the AST circular
+	args = make([]quickjs.Value, 2)
+	args[0] = result
+	args[1] = *parser.astKeys
+	if parser.keepFullAst {
+		args[1] = *parser.circularReplacer
+	}
+	codeJson, err := parser.context.Call(*parser.globals, *parser.stringify, args)
+	defer codeJson.Free()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceFileAsString := codeJson.String()
+
+	// now convert the "result" represented as AST in QJS objects
+	// to the pure objects that we require
+	// fmt.Println(sourceFileAsString)
+
+	sourceFile := SourceFile{}
+
+	// a tree that cannot be read is reported as an error of the
+	// file, without stopping the parsing of other files
+	err = json.Unmarshal([]byte(sourceFileAsString), &sourceFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sourceFile.syntax = parser.syntaxKind
+
+	// convert the syntax errors found by Typescript
+	diagnostics := make([]Diagnostic, 0, len(sourceFile.ParseDiagnostics))
+	for _, tsDiagnostic := range sourceFile.ParseDiagnostics {
+		diagnostics = append(diagnostics, tsDiagnostic.toDiagnostic(fileName, &sourceFile))
+	}
+
+	return &sourceFile, diagnostics, nil
+}
+
+// Return the Typescript script kind to parse the given file
+// as, based on its extension. Files with an unknown extension
+// are parsed as TSX.
+func (parser *tsParser) getScriptKind(fileName string) int {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ts", ".mts", ".cts":
+		return parser.scriptKind.TS
+
+	case ".js", ".mjs", ".cjs":
+		return parser.scriptKind.JS
+
+	case ".jsx":
+		return parser.scriptKind.JSX
+	}
+
+	return parser.scriptKind.TSX
+}
+
+// Set the script target to parse all files with, given its
+// name as used in `tsconfig.json`, such as `es6` or `ESNext`.
+// Unknown or empty names leave the target unchanged, and are
+// reported when the configuration is read.
+func (parser *tsParser) setScriptTarget(target string) {
+	if target == "" {
+		return
+	}
+
+	name, _ := NormalizeScriptTarget(target)
+	if value, exists := parser.scriptTargets[name]; exists {
+		parser.scriptTarget = value
+	}
+}
+
+/**
+ * Free all created objects, including those of a runtime
+ * that was only partly initialized
+ */
+func (parser *tsParser) Free() {
+	for _, value := range []*quickjs.Value{parser.stringify, parser.circularReplacer, parser.astKeys, parser.codeParser} {
+		if value != nil {
+			value.Free()
+		}
+	}
+	parser.stringify, parser.circularReplacer, parser.astKeys, parser.codeParser = nil, nil, nil, nil
+
+	if parser.context != nil {
+		parser.context.Free()
+		parser.context = nil
+	}
+
+	// finally free the runtime, and then the limits it holds on to
+	if parser.runtime != nil {
+		parser.runtime.Free()
+		parser.runtime = nil
+	}
+
+	if parser.limits != nil {
+		parser.limits.free()
+		parser.limits = nil
+	}
+}
+
+// Replace the QuickJS runtime with a new one, after the code running
+// in it was stopped midway, or it ran out of memory or stack. The
+// syntax kinds are kept, as the source files already parsed refer to
+// them. If the runtime cannot be replaced, the parser is marked as
+// failed, and no more files are parsed.
+func (parser *tsParser) recycle() {
+	syntaxKind := parser.syntaxKind
+	scriptTarget := parser.scriptTarget
+
+	parser.Free()
+	if err := parser.init(); err != nil {
+		parser.Free()
+		parser.failed = err
+		return
+	}
+
+	parser.syntaxKind = syntaxKind
+	parser.scriptTarget = scriptTarget
+}
+
+/**
+ * Initialize the Typescript parser based on QuickJS runtime.
+ * Returns an error if the Typescript compiler cannot be read
+ * or loaded, in which case the parser must be freed.
+ */
+func (parser *tsParser) init() error {
+	// read typescript code to be used
+	typeScript, err := os.ReadFile(parser.options.getTypescriptFile())
+	if err != nil {
+		return fmt.Errorf("unable to read the Typescript compiler: %w", err)
+	}
+
+	// build quick js runtime
+	runtime := quickjs.NewRuntime()
+	parser.runtime = &runtime
+	parser.limits = newRuntimeLimits(parser.runtime, parser.options)
+
+	context := runtime.NewContext()
+	parser.context = context
+
+	// load TS source code
+	result, err := context.EvalFile(string(typeScript), 0, "typescript.js")
+	defer result.Free()
+	if err != nil {
+		return parser.loadError(err)
+	}
+
+	// never free this - throws cgo error at app termination
+	globals := context.Globals()
+	parser.globals = &globals
+
+	ts := globals.Get("ts")
+	defer ts.Free()
+	if !ts.IsObject() {
+		return errors.New("unable to load the Typescript compiler: " + parser.options.getTypescriptFile() + " does not define `ts`")
+	}
+
+	// read syntax kind
+	sk := ts.Get("SyntaxKind")
+	defer sk.Free()
+
+	// get JSON.stringify function
+	jsJson := globals.Get("JSON")
+	defer jsJson.Free()
+
+	stringify := jsJson.Get("stringify")
+	parser.stringify = &stringify
+
+	stringifyArgs := make([]quickjs.Value, 1)
+	stringifyArgs[0] = sk
+
+	syntaxKind := SyntaxKind{}
+	syntaxKindJson, err := context.Call(globals, stringify, stringifyArgs)
+	if err == nil {
+		_ = json.Unmarshal([]byte(syntaxKindJson.String()), &syntaxKind)
+	}
+
+	parser.syntaxKind = &syntaxKind
+
+	// read script kind
+	scriptKind := ts.Get("ScriptKind")
+	defer scriptKind.Free()
+
+	stringifyArgs[0] = scriptKind
+	scriptKindJson, err := context.Call(globals, stringify, stringifyArgs)
+	if err == nil {
+		_ = json.Unmarshal([]byte(scriptKindJson.String()), &parser.scriptKind)
+	}
+
+	// read script target, defaulting to the latest
+	scriptTarget := ts.Get("ScriptTarget")
+	defer scriptTarget.Free()
+
+	stringifyArgs[0] = scriptTarget
+	scriptTargetJson, err := context.Call(globals, stringify, stringifyArgs)
+	if err == nil {
+		// the enum maps both names to values and values to names
+		var targets map[string]interface{}
+		_ = json.Unmarshal([]byte(scriptTargetJson.String()), &targets)
+
+		parser.scriptTargets = make(map[string]int, len(targets))
+		for name, value := range targets {
+			if number, ok := value.(float64); ok {
+				parser.scriptTargets[strings.ToLower(name)] = int(number)
+			}
+		}
+	}
+
+	parser.scriptTarget = parser.scriptTargets["latest"]
+
+	// read parsing function
+	parseCode := ts.Get("createSourceFile")
+	parser.codeParser = &parseCode
+
+	// craeate a circular replacer
+	replacerCode := `const ____getCircularReplacer = () => {
+		const seen = new WeakSet();
+		return (key, value) => {
+		  if (typeof value === 'object' && value !== null) {
+			if (seen.has(value)) {
+			  return;
+			}
+			seen.add(value);
+		  }
+		  return value;
+		};
+	  };
+	  `
+
+	replacerCodeResult, err := context.Eval(replacerCode, quickjs.EVAL_GLOBAL)
+	defer replacerCodeResult.Free()
+	if err != nil {
+		return parser.loadError(err)
+	}
+
+	replacer, err := context.Eval("____getCircularReplacer()", quickjs.EVAL_GLOBAL)
+	parser.circularReplacer = &replacer
+	if err != nil {
+		return parser.loadError(err)
+	}
+
+	// the properties to keep when converting the AST to JSON
+	astKeys, err := context.Eval(getAstKeysJson(), quickjs.EVAL_GLOBAL)
+	parser.astKeys = &astKeys
+	if err != nil {
+		return parser.loadError(err)
+	}
+
+	return nil
+}
+
+/**
+ * Log the QuickJS error, along with its stack, and return
+ * the error of loading the Typescript compiler
+ */
+func (parser *tsParser) loadError(err error) error {
+	var evalErr *quickjs.Error
+	if errors.As(err, &evalErr) {
+		parser.options.getLogger().Error("Unable to load Typescript", "cause", evalErr.Cause, "stack", evalErr.Stack)
+	}
+
+	return fmt.Errorf("unable to load the Typescript compiler: %w", err)
+}
@@ -0,0 +1,272 @@
+//go:build cgo
+
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/quickjs-go/quickjs-go"
+)
+
+// This file contains the code to run the Typescript type checker
+// in the QuickJS runtime, which needs cgo.
+
+// The input passed to the checker code in the QuickJS runtime
+type checkerInput struct {
+	Files       map[string]string            `json:"files"`       // contents of all files, keyed by path
+	Resolutions map[string]map[string]string `json:"resolutions"` // resolved imports, keyed by importing file and specifier
+	RootFiles   []string                     `json:"rootFiles"`   // the files to read components from
+	Target      int                          `json:"target"`      // the script target
+	LibFolder   string                       `json:"libFolder"`   // the folder of the default library files, empty if there are none
+}
+
+// The checker code that runs in the QuickJS runtime. It builds a
+// `ts.Program` over an in-memory compiler host, and for every top
+// level class and function declaration of the root files reads the
+// properties of its props type. Imports are resolved as resolved on
+// the Go side. The default library for the target, along with the
+// libraries it refers to, is read from the library files passed in.
+const checkerCode = `globalThis.____getCheckedProps = (input) => {
+	const { files, resolutions, rootFiles, target, libFolder } = JSON.parse(input);
+	const options = { noLib: !libFolder, noEmit: true, allowJs: true, jsx: ts.JsxEmit.Preserve, target: target };
+
+	const sourceFiles = {};
+	const host = {
+		getSourceFile: (fileName, languageVersion) => {
+			if (!(fileName in files)) {
+				return undefined;
+			}
+			if (!sourceFiles[fileName]) {
+				sourceFiles[fileName] = ts.createSourceFile(fileName, files[fileName], languageVersion, true);
+			}
+			return sourceFiles[fileName];
+		},
+		getDefaultLibFileName: (options) => libFolder + '/' + ts.getDefaultLibFileName(options),
+		writeFile: () => {},
+		getCurrentDirectory: () => '/',
+		getCanonicalFileName: (fileName) => fileName,
+		useCaseSensitiveFileNames: () => true,
+		getNewLine: () => '\n',
+		fileExists: (fileName) => fileName in files,
+		readFile: (fileName) => files[fileName],
+		resolveModuleNames: (moduleNames, containingFile) => moduleNames.map((name) => {
+			const resolved = (resolutions[containingFile] || {})[name];
+			if (!resolved) {
+				return undefined;
+			}
+			return { resolvedFileName: resolved, extension: ts.extensionFromPath(resolved), isExternalLibraryImport: false };
+		}),
+	};
+
+	const program = ts.createProgram(rootFiles, options, host);
+	const checker = program.getTypeChecker();
+
+	const getPropsType = (statement) => {
+		// the type argument of ` + "`React.Component<Props>`" + `
+		if (ts.isClassDeclaration(statement)) {
+			const clause = (statement.heritageClauses || [])[0];
+			const base = clause && clause.types[0];
+			if (base && base.typeArguments && base.typeArguments.length > 0) {
+				return checker.getTypeFromTypeNode(base.typeArguments[0]);
+			}
+			return undefined;
+		}
+
+		// the type of the first parameter of a function
+		if (ts.isFunctionDeclaration(statement) && statement.parameters.length > 0) {
+			return checker.getTypeAtLocation(statement.parameters[0]);
+		}
+
+		return undefined;
+	};
+
+	const getProps = (propsType, statement) => {
+		const type = checker.getApparentType(propsType);
+		return checker.getPropertiesOfType(type).map((symbol) => {
+			const declaration = symbol.valueDeclaration || (symbol.declarations || [])[0];
+			const propType = checker.getTypeOfSymbolAtLocation(symbol, declaration || statement);
+			const parent = declaration && declaration.parent;
+
+			return {
+				name: symbol.getName(),
+				type: checker.typeToString(propType, undefined, ts.TypeFormatFlags.NoTruncation),
+				optional: (symbol.flags & ts.SymbolFlags.Optional) !== 0,
+				description: ts.displayPartsToString(symbol.getDocumentationComment(checker)),
+				declaredIn: parent && parent.name ? parent.name.text : '',
+				fileName: declaration ? declaration.getSourceFile().fileName : '',
+			};
+		});
+	};
+
+	const result = {};
+	for (const fileName of rootFiles) {
+		const sourceFile = program.getSourceFile(fileName);
+		if (!sourceFile) {
+			continue;
+		}
+
+		const components = {};
+		for (const statement of sourceFile.statements) {
+			if (!statement.name) {
+				continue;
+			}
+
+			const propsType = getPropsType(statement);
+			if (propsType) {
+				components[statement.name.text] = getProps(propsType, statement);
+			}
+		}
+		result[fileName] = components;
+	}
+
+	return JSON.stringify(result);
+};
+`
+
+// Run the type checker over all files, reading the props of the
+// components declared in the root files. The checked props are
+// attached to the root source files. Returns an error if the checker
+// could not be run, in which case the source files are unchanged.
+func (parser *tsParser) checkTypes(ctx context.Context, rootFiles map[string]SourceFile, allFiles map[string]SourceFile, resolve ModuleResolver) error {
+	if parser.failed != nil {
+		return parser.failed
+	}
+
+	input := checkerInput{
+		Files:       make(map[string]string, len(allFiles)),
+		Resolutions: make(map[string]map[string]string, len(allFiles)),
+		RootFiles:   make([]string, 0, len(rootFiles)),
+		Target:      parser.scriptTarget,
+	}
+
+	for file, sourceFile := range allFiles {
+		input.Files[file] = sourceFile.Text
+
+		resolutions := make(map[string]string)
+		for _, specifier := range sourceFile.GetModuleSpecifiers() {
+			if path := resolve(file, specifier); path != "" {
+				resolutions[specifier] = path
+			}
+		}
+		input.Resolutions[file] = resolutions
+	}
+
+	for file := range rootFiles {
+		input.RootFiles = append(input.RootFiles, file)
+	}
+
+	// types such as `Promise`, `Pick` or `HTMLElement` are declared
+	// by the default library
+	libFolder, libFiles := parser.getLibFiles()
+	for file, contents := range libFiles {
+		input.Files[file] = contents
+	}
+	if len(libFiles) > 0 {
+		input.LibFolder = libFolder
+	}
+
+	inputJson, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	resultJson, err := parser.runChecker(ctx, string(inputJson))
+	if err != nil {
+		// a runtime that was stopped midway, or ran out of memory
+		// or stack, is replaced before it is used again
+		if errors.Is(err, errTimedOut) || isRuntimeExhausted(err) {
+			parser.recycle()
+		}
+
+		return err
+	}
+
+	checked := make(map[string]map[string][]CheckedProp)
+	err = json.Unmarshal([]byte(resultJson), &checked)
+	if err != nil {
+		return err
+	}
+
+	for file, components := range checked {
+		sourceFile, exists := rootFiles[file]
+		if !exists {
+			continue
+		}
+
+		sourceFile.checkedProps = components
+		rootFiles[file] = sourceFile
+	}
+
+	return nil
+}
+
+// Return the folder of the declaration files of the default library,
+// such as `lib.es2020.d.ts` and `lib.dom.d.ts`, which are installed
+// next to `typescript.js`, along with the files keyed by their path.
+// The files are read once, and none are returned if they are missing,
+// in which case the types they declare are read as `any`.
+func (parser *tsParser) getLibFiles() (string, map[string]string) {
+	libFolder := filepath.Dir(parser.options.getTypescriptFile())
+
+	if parser.libFiles == nil {
+		parser.libFiles = make(map[string]string)
+
+		paths, _ := filepath.Glob(filepath.Join(libFolder, "lib*.d.ts"))
+		for _, path := range paths {
+			contents, err := os.ReadFile(path)
+			if err == nil {
+				parser.libFiles[filepath.ToSlash(path)] = string(contents)
+			}
+		}
+	}
+
+	return filepath.ToSlash(libFolder), parser.libFiles
+}
+
+// Define the checker function in the runtime and call it with the
+// input, within the limits of the runtime. Returns the JSON result.
+func (parser *tsParser) runChecker(ctx context.Context, inputJson string) (string, error) {
+	code, err := parser.context.Eval(checkerCode, quickjs.EVAL_GLOBAL)
+	defer code.Free()
+	if err != nil {
+		return "", err
+	}
+
+	checker := parser.globals.Get("____getCheckedProps")
+	defer checker.Free()
+	if !checker.IsFunction() {
+		return "", errors.New("type checker could not be initialized")
+	}
+
+	args := []quickjs.Value{parser.context.String(inputJson)}
+	var resultJson string
+
+	err = parser.limits.run(ctx, func() error {
+		result, err := parser.context.Call(*parser.globals, checker, args)
+		defer result.Free()
+		if err != nil {
+			return err
+		}
+
+		resultJson = result.String()
+		return nil
+	})
+
+	return resultJson, err
+}
@@ -0,0 +1,181 @@
+//go:build cgo
+
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+/*
+#include <stdlib.h>
+
+// The QuickJS functions below are not exposed by the Go bindings, and
+// are declared here to be linked against the same QuickJS library.
+typedef struct JSRuntime JSRuntime;
+typedef int JSInterruptHandler(JSRuntime *rt, void *opaque);
+void JS_SetInterruptHandler(JSRuntime *rt, JSInterruptHandler *cb, void *opaque);
+void JS_SetMaxStackSize(JSRuntime *rt, size_t stack_size);
+
+// Set from Go to interrupt the code running in QuickJS, which checks
+// the flag every few thousand instructions.
+typedef struct {
+	int interrupted;
+} interruptFlag;
+
+static int interruptHandler(JSRuntime *rt, void *opaque) {
+	return __atomic_load_n(&((interruptFlag *) opaque)->interrupted, __ATOMIC_SEQ_CST);
+}
+
+static void setInterruptHandler(JSRuntime *rt, interruptFlag *flag) {
+	JS_SetInterruptHandler(rt, interruptHandler, flag);
+}
+
+static void setInterrupted(interruptFlag *flag, int interrupted) {
+	__atomic_store_n(&flag->interrupted, interrupted, __ATOMIC_SEQ_CST);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/quickjs-go/quickjs-go"
+)
+
+// This file contains the limits put on the QuickJS runtime, so that a
+// pathological or enormous file cannot hang or exhaust the memory of
+// the whole run.
+
+const (
+	DEFAULT_PARSE_TIMEOUT = 30 * time.Second // the longest time to parse a single file, or run the type checker
+	DEFAULT_MEMORY_LIMIT  = 1024             // the most memory the QuickJS runtime may use, in megabytes
+	DEFAULT_STACK_LIMIT   = 1024             // the largest stack the QuickJS runtime may use, in kilobytes
+)
+
+// Returned, wrapped along with the timeout, when the code running in
+// the runtime takes longer than the timeout.
+var errTimedOut = errors.New("stopped for taking too long")
+
+// Interrupts the code running in a QuickJS runtime when it takes too
+// long or the context is done. The flag is allocated in C memory, as
+// QuickJS holds on to it for the lifetime of the runtime.
+type runtimeLimits struct {
+	flag    *C.interruptFlag
+	timeout time.Duration
+}
+
+// Apply the memory and stack limits of the options to the runtime, and
+// install the interrupt handler used to enforce the timeout.
+func newRuntimeLimits(runtime *quickjs.Runtime, options *ParseOptions) *runtimeLimits {
+	memoryLimit := uint32(DEFAULT_MEMORY_LIMIT)
+	stackLimit := uint32(DEFAULT_STACK_LIMIT)
+	timeout := DEFAULT_PARSE_TIMEOUT
+
+	if options != nil {
+		if options.MemoryLimit > 0 {
+			memoryLimit = options.MemoryLimit
+		}
+		if options.StackLimit > 0 {
+			stackLimit = options.StackLimit
+		}
+		if options.Timeout > 0 {
+			timeout = options.Timeout
+		}
+	}
+
+	limits := &runtimeLimits{
+		flag:    (*C.interruptFlag)(C.calloc(1, C.size_t(unsafe.Sizeof(C.interruptFlag{})))),
+		timeout: timeout,
+	}
+
+	// the bindings keep the runtime pointer unexported, as the only
+	// field of the runtime
+	ref := *(**C.JSRuntime)(unsafe.Pointer(runtime))
+
+	runtime.SetMemoryLimit(clampMegabytes(memoryLimit) << 20)
+	C.JS_SetMaxStackSize(ref, C.size_t(stackLimit)<<10)
+	C.setInterruptHandler(ref, limits.flag)
+
+	return limits
+}
+
+// Keep the limit in megabytes within what fits in 32 bits once
+// converted to bytes.
+func clampMegabytes(megabytes uint32) uint32 {
+	if megabytes >= 4096 {
+		return 4095
+	}
+
+	return megabytes
+}
+
+// Run the function, interrupting the code it runs in QuickJS if the
+// timeout passes or the context is done. Returns the error the
+// function returned, or why it was interrupted if it failed.
+func (limits *runtimeLimits) run(ctx context.Context, fn func() error) error {
+	stop := make(chan struct{})
+	stopped := make(chan error, 1)
+
+	go func() {
+		timer := time.NewTimer(limits.timeout)
+		defer timer.Stop()
+
+		select {
+		case <-stop:
+			stopped <- nil
+			return
+
+		case <-timer.C:
+			C.setInterrupted(limits.flag, 1)
+			stopped <- fmt.Errorf("%w, the limit is %s", errTimedOut, limits.timeout)
+
+		case <-ctx.Done():
+			C.setInterrupted(limits.flag, 1)
+			stopped <- ctx.Err()
+		}
+	}()
+
+	err := fn()
+
+	close(stop)
+	interrupted := <-stopped
+	C.setInterrupted(limits.flag, 0)
+
+	// the code may have completed just as it was interrupted
+	if err != nil && interrupted != nil {
+		return interrupted
+	}
+
+	return err
+}
+
+// Free the memory of the interrupt flag, once the runtime holding
+// on to it has been freed.
+func (limits *runtimeLimits) free() {
+	C.free(unsafe.Pointer(limits.flag))
+	limits.flag = nil
+}
+
+// Check if the error means that the runtime ran out of memory or
+// stack, after which it can no longer be relied upon.
+func isRuntimeExhausted(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := err.Error()
+	return strings.Contains(message, "out of memory") || strings.Contains(message, "stack overflow")
+}
@@ -0,0 +1,23 @@
+//go:build !cgo
+
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package ast
+
+import "errors"
+
+// The Typescript compiler runs in the QuickJS runtime, which needs
+// cgo. Builds without cgo can only use the native Go parser.
+func newTypescriptParser(options *ParseOptions) (Parser, error) {
+	return nil, errors.New("the typescript parser needs a build with cgo, use the go parser instead")
+}
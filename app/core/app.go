@@ -13,13 +13,18 @@ that can be found in LICENSE file in the code repository.
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	ast "sangupta.com/redefine/ast"
 	"sangupta.com/redefine/model"
@@ -31,6 +36,17 @@ type RedefineApp struct {
 	RunMode    string
 	Config     *RedefineConfig
 	BaseFolder string
+	Strict     bool         // fail the run if any source file has errors
+	Logger     *slog.Logger // the logger to report progress to, nothing is logged when `nil`
+	ConfigFile string       // the configuration file given with `--config`, if any
+	Overrides  []string     // the `key=value` pairs given with `--set`
+	Package    string       // the only workspace package to document, given with `--package`
+}
+
+// Return the logger of the app, or one that discards all records
+// if there is none.
+func (app *RedefineApp) getLogger() *slog.Logger {
+	return getLogger(app.Logger)
 }
 
 func (app *RedefineApp) IsBuildMode() bool {
@@ -41,83 +57,244 @@ func (app *RedefineApp) IsPublishMode() bool {
 	return strings.EqualFold("publish", app.RunMode)
 }
 
+func (app *RedefineApp) IsValidateMode() bool {
+	return strings.EqualFold("validate", app.RunMode)
+}
+
+func (app *RedefineApp) IsInitMode() bool {
+	return strings.EqualFold("init", app.RunMode)
+}
+
+func (app *RedefineApp) IsTypesMode() bool {
+	return strings.EqualFold("types", app.RunMode)
+}
+
 func (app *RedefineApp) IsServeMode() bool {
-	return !(app.IsBuildMode() || app.IsPublishMode())
+	return !(app.IsBuildMode() || app.IsPublishMode() || app.IsValidateMode() || app.IsTypesMode() || app.IsInitMode())
 }
 
 // Value object to define how the component JSON
 // should be written to disk and/or served for client
 type jsonPayload struct {
-	Title       string            `json:"title"`       // the title that is recognized from the package.json file or a user supplied string
-	Favicon     string            `json:"favicon"`     // favicon defined in redefine config
-	Description string            `json:"description"` // description read from package.json file
-	Index       string            `json:"libDocs"`     // index file as defined in docs folder
-	Version     string            `json:"version"`     // version read from package.json file
-	HomePage    string            `json:"homePage"`    // home page read from package.json file
-	Author      PackageAuthor     `json:"author"`      // author read from package.json file
-	License     string            `json:"license"`     // license read from package.json file
-	Components  []model.Component `json:"components"`  // the extracted components
-	CustomCss   string            `json:"customCSS"`   // custom css that needs to be included in page
-	Lib         string            `json:"library"`     // the actual component library JS
-	Fonts       []string          `json:"fonts"`       // the fonts that need to be loaded
-	JsFiles     []string          `json:"js"`          // JS files to be loaded inside
-}
-
-func (app *RedefineApp) ExtractAndWriteComponents() ([]byte, error) {
+	Schema        string            `json:"$schema"`          // the schema of the file, relative to the file
+	SchemaVersion int               `json:"schemaVersion"`    // the version of the format, see COMPONENTS_SCHEMA_VERSION
+	Title         string            `json:"title"`            // the title that is recognized from the package.json file or a user supplied string
+	Favicon       string            `json:"favicon"`          // favicon defined in redefine config
+	Description   string            `json:"description"`      // description read from package.json file
+	Index         string            `json:"libDocs"`          // index file as defined in docs folder
+	Version       string            `json:"version"`          // version read from package.json file
+	Name          string            `json:"name"`             // the package name read from package.json file, which components are imported from
+	HomePage      string            `json:"homePage"`         // home page read from package.json file
+	Repository    string            `json:"repository"`       // web URL of the source code repository
+	Bugs          string            `json:"bugs"`             // link to report issues at, read from package.json file
+	Keywords      []string          `json:"keywords"`         // keywords read from package.json file
+	Author        PackageAuthor     `json:"author"`           // author read from package.json file
+	License       string            `json:"license"`          // license read from package.json file
+	Peers         map[string]string `json:"peerDependencies"` // version ranges of the peer dependencies, read from package.json file
+	Components    []model.Component `json:"components"`       // the extracted components
+	CustomCss     string            `json:"customCSS"`        // custom css that needs to be included in page
+	Lib           string            `json:"library"`          // the actual component library JS
+	Fonts         []string          `json:"fonts"`            // the fonts that need to be loaded
+	JsFiles       []string          `json:"js"`               // JS files to be loaded inside
+	Packages      []packagePayload  `json:"packages"`         // the packages of the workspace, if components are read from one
+}
+
+// Extract all components from the source folder and write the
+// final components JSON. Problems found when parsing the source
+// files are returned as diagnostics. In strict mode, any error
+// in the source files fails the extraction. Parsing stops early
+// once the context is done.
+func (app *RedefineApp) ExtractAndWriteComponents(ctx context.Context) ([]byte, []ast.Diagnostic, error) {
 	config := app.Config
+	logger := app.getLogger()
+
+	// the packages of a workspace are each extracted with
+	// their own configuration, and read their own docs
+	if len(config.packages) > 0 {
+		components, diagnostics, err := app.extractPackages(ctx)
+		if err != nil {
+			return nil, diagnostics, err
+		}
 
-	// scan the base folder for all files present
-	files, err := config.scanFolder()
+		jsonBytes, err := app.writeFinalJsonFile(components)
+		return jsonBytes, diagnostics, err
+	}
+
+	components, diagnostics, err := app.extractComponents(ctx)
 	if err != nil {
-		return nil, err
+		return nil, diagnostics, err
 	}
 
-	// parse AST for each file
-	astMap, syntaxKind := ast.BuildAstForFiles(files)
+	// add documentation if available to component
+	start := time.Now()
+	config.readDocs(components)
+	logger.Debug("Read component docs", "duration", time.Since(start))
+
+	start = time.Now()
+	jsonBytes, err := app.writeFinalJsonFile(components)
+	logger.Debug("Created components JSON", "bytes", len(jsonBytes), "duration", time.Since(start))
+
+	return jsonBytes, diagnostics, err
+}
+
+// Extract all components from the source folder, with source
+// paths relative to the source folder and unique ids assigned.
+// Problems found when parsing the source files are returned as
+// diagnostics. In strict mode, any error in the source files fails
+// the extraction.
+func (app *RedefineApp) extractComponents(ctx context.Context) ([]model.Component, []ast.Diagnostic, error) {
+	config := app.Config
+	logger := app.getLogger()
+
+	// scan the base folder for all files present
+	start := time.Now()
+	files, diagnostics := config.scanFolder()
+	logger.Debug("Scanned source folders", "files", len(files), "duration", time.Since(start))
+
+	// parse AST for each file, along with the files they import
+	resolver := config.getModuleResolver()
+	astMap, modules, syntaxKind, parseDiagnostics := ast.BuildAstForFilesAndImports(ctx, files, config.getParseOptions(), resolver.resolve)
+	diagnostics = append(diagnostics, parseDiagnostics...)
+	if app.Strict && ast.HasErrors(diagnostics) {
+		return nil, diagnostics, errors.New("source files have errors, refusing to continue in strict mode")
+	}
 
 	// extract components
-	components := model.GetComponents(astMap, syntaxKind)
+	components := model.GetComponents(astMap, syntaxKind, modules, config.getExtractOptions())
+	logger.Info("Extracted components", "components", len(components), "files", len(astMap))
 
-	// sort components
+	// make source paths relative to the source folder
+	for index := range components {
+		components[index].SourcePath = config.getRelativeSourcePath(components[index].SourcePath)
+	}
+
+	// make source files relative and link them to the repository
+	config.setSourceLocations(components)
+
+	// sort components, keeping components that share a name
+	// in a stable order so that their ids do not change across runs
 	sort.SliceStable(components, func(i, j int) bool {
-		return components[i].Name < components[j].Name
+		first, second := components[i], components[j]
+		if first.Name != second.Name {
+			return first.Name < second.Name
+		}
+
+		if first.SourcePath != second.SourcePath {
+			return first.SourcePath < second.SourcePath
+		}
+
+		if first.SourceFile != second.SourceFile {
+			return first.SourceFile < second.SourceFile
+		}
+
+		return first.Line < second.Line
 	})
 
-	// fix source path in components
-	if len(components) > 0 {
-		baseLen := len(config.SrcFolder.Root)
+	// assign unique ids, and warn of components sharing a name
+	diagnostics = append(diagnostics, assignComponentIds(components)...)
+	model.SetSubComponentIds(components)
 
-		for index := range components {
-			if strings.HasPrefix(components[index].SourcePath, config.SrcFolder.Root) {
-				components[index].SourcePath = components[index].SourcePath[baseLen+1:]
-			}
+	return components, diagnostics, nil
+}
+
+// Assign each component a unique id made of its relative source
+// path and name, such as `menu/Item`. Components that share a name
+// are reported as warnings, as the client displays them by name.
+// If two components end up with the same id, which happens when
+// files in the same folder declare the same name, a numeric suffix
+// is added to keep the ids unique.
+func assignComponentIds(components []model.Component) []ast.Diagnostic {
+	var diagnostics []ast.Diagnostic
+
+	ids := make(map[string]int, len(components))
+	byName := make(map[string][]string)
+
+	for index := range components {
+		component := &components[index]
+
+		id := path.Join(component.SourcePath, component.Name)
+		ids[id]++
+		if ids[id] > 1 {
+			diagnostics = append(diagnostics, ast.Diagnostic{
+				File:     component.SourceFile,
+				Line:     component.Line,
+				Column:   1,
+				Severity: ast.SEVERITY_WARNING,
+				Message:  "component " + id + " is declared more than once in the same folder",
+			})
+
+			id = id + "~" + strconv.Itoa(ids[id])
 		}
+
+		component.Id = id
+		byName[component.Name] = append(byName[component.Name], id)
 	}
 
-	// add documentation if available to component
-	if config.DocsFolder != nil && config.DocsFolder.Root != "" {
-		for index := range components {
-			// build path to doc file
-			docFile := path.Join(config.DocsFolder.Root, components[index].SourcePath, components[index].Name)
-			ext := path.Ext(docFile)
-			fileNameWithoutExt := docFile[0 : len(docFile)-len(ext)]
-
-			mdExists, mdFile := readFileWithExtension(fileNameWithoutExt, ".md")
-			if mdExists {
-				components[index].Docs = mdFile
-				components[index].DocFileName = filepath.Base(fileNameWithoutExt + ".md")
-				continue
-			}
+	// report duplicate names in a stable order
+	names := make([]string, 0, len(byName))
+	for name, componentIds := range byName {
+		if len(componentIds) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		componentIds := byName[name]
+		sort.Strings(componentIds)
 
-			txtExists, txtFile := readFileWithExtension(fileNameWithoutExt, ".txt")
-			if txtExists {
-				components[index].Docs = txtFile
-				components[index].DocFileName = filepath.Base(fileNameWithoutExt + ".txt")
+		diagnostics = append(diagnostics, ast.Diagnostic{
+			Severity: ast.SEVERITY_WARNING,
+			Message:  "component name " + name + " is used by multiple components: " + strings.Join(componentIds, ", "),
+		})
+	}
+
+	return diagnostics
+}
+
+// Read the documentation of each component from the docs folder,
+// if one exists.
+func (config *RedefineConfig) readDocs(components []model.Component) {
+	if config.DocsFolder == nil || config.DocsFolder.Root == "" {
+		return
+	}
+
+	names := make(map[string]int, len(components))
+	for _, component := range components {
+		names[component.Name]++
+	}
+
+	for index := range components {
+		component := &components[index]
+
+		// look up the doc file by id, such as `docs/menu/Item.md`,
+		// and fall back to the name for components with unique names
+		docFiles := []string{path.Join(config.DocsFolder.Root, component.Id)}
+		if names[component.Name] == 1 && component.Id != component.Name {
+			docFiles = append(docFiles, path.Join(config.DocsFolder.Root, component.Name))
+		}
+
+		for _, fileNameWithoutExt := range docFiles {
+			if readComponentDocs(component, fileNameWithoutExt) {
+				break
 			}
 		}
 	}
+}
 
-	return app.writeFinalJsonFile(components)
+// Read the `.md` or `.txt` documentation file for the component,
+// if one exists at the given path without extension.
+func readComponentDocs(component *model.Component, fileNameWithoutExt string) bool {
+	for _, extension := range []string{".md", ".txt"} {
+		exists, contents := readFileWithExtension(fileNameWithoutExt, extension)
+		if exists {
+			component.Docs = contents
+			component.DocFileName = filepath.Base(fileNameWithoutExt + extension)
+			return true
+		}
+	}
+
+	return false
 }
 
 func readFileWithExtension(fileNameWithoutExt string, extension string) (bool, string) {
@@ -163,7 +340,7 @@ func (app *RedefineApp) writeFinalJsonFile(components []model.Component) ([]byte
 	var builder strings.Builder
 	if len(config.Build.CssFiles) > 0 {
 		for _, css := range config.Build.CssFiles {
-			fmt.Println("Reading custom CSS file from: " + css)
+			app.getLogger().Debug("Reading custom CSS file", "path", css)
 			cssData, err := os.ReadFile(css)
 			if err != nil {
 				continue
@@ -176,19 +353,27 @@ func (app *RedefineApp) writeFinalJsonFile(components []model.Component) ([]byte
 
 	// write the JSON file
 	payload := jsonPayload{
-		Title:       config.Template.Title,
-		Favicon:     config.Template.FavIcon,
-		Index:       string(libDocs),
-		Components:  components,
-		Description: pkgJson.Description,
-		HomePage:    pkgJson.HomePage,
-		Version:     pkgJson.Version,
-		Author:      pkgJson.Author,
-		License:     pkgJson.License,
-		CustomCss:   builder.String(),
-		Lib:         config.Build.Lib,
-		Fonts:       config.Build.FontFiles,
-		JsFiles:     config.Build.JsFiles,
+		Schema:        COMPONENTS_SCHEMA_FILE,
+		SchemaVersion: COMPONENTS_SCHEMA_VERSION,
+		Title:         config.Template.Title,
+		Favicon:       config.Template.FavIcon,
+		Index:         string(libDocs),
+		Components:    components,
+		Description:   pkgJson.Description,
+		Name:          pkgJson.Name,
+		HomePage:      pkgJson.HomePage,
+		Repository:    config.Repository.Url,
+		Bugs:          pkgJson.Bugs.getLink(),
+		Keywords:      pkgJson.Keywords,
+		Version:       pkgJson.Version,
+		Author:        pkgJson.Author,
+		License:       pkgJson.License,
+		Peers:         pkgJson.PeerDependencies,
+		CustomCss:     builder.String(),
+		Lib:           config.Build.Lib,
+		Fonts:         config.Build.FontFiles,
+		JsFiles:       config.Build.JsFiles,
+		Packages:      config.getPackagesPayload(),
 	}
 
 	// create JSON byte array
@@ -199,28 +384,81 @@ func (app *RedefineApp) writeFinalJsonFile(components []model.Component) ([]byte
 
 	// find the output folder
 	if app.IsBuildMode() {
-		// only write the file when we are in build mode
+		// only write the file when we are in build mode, next to
+		// the package, which is a single package of a workspace
+		// when only one is asked for
 		var outFolder string
 		if pkgJson.MainFile != "" {
 			outFolder = path.Dir(pkgJson.MainFile)
-			outFolder = path.Join(app.BaseFolder, outFolder)
+			outFolder = path.Join(config.baseFolder, outFolder)
 		} else {
-			outFolder = app.BaseFolder
+			outFolder = config.baseFolder
 		}
 
 		// write the file to disk
 		jsonFile := path.Join(outFolder, "components.json")
-		fmt.Println("Components JSON written to: " + jsonFile)
-		os.WriteFile(jsonFile, jsonStr, 0644)
+		err = os.WriteFile(jsonFile, jsonStr, 0644)
+		if err != nil {
+			return nil, err
+		}
+		app.getLogger().Info("Components JSON written", "path", jsonFile)
+
+		// along with the schemas, so that the reference from
+		// the file resolves, and the Typescript declarations
+		err = app.writeFormatFiles(outFolder)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return jsonStr, nil
 }
 
+// Write the schemas of `components.json` and the configuration,
+// and the Typescript declarations of `components.json`, to the
+// output folder.
+func (app *RedefineApp) writeFormatFiles(outFolder string) error {
+	for _, fileName := range []string{COMPONENTS_SCHEMA_FILE, CONFIG_SCHEMA_FILE, DECLARATIONS_FILE} {
+		contents, err := GetFormatFile(fileName)
+		if err != nil {
+			return err
+		}
+
+		formatFile := path.Join(outFolder, fileName)
+		err = os.WriteFile(formatFile, contents, 0644)
+		if err != nil {
+			return err
+		}
+
+		app.getLogger().Debug("Format file written", "path", formatFile)
+	}
+
+	return nil
+}
+
+// Return the contents of the schema or declarations file with the
+// given name, or `nil` if there is no such file.
+func GetFormatFile(fileName string) ([]byte, error) {
+	switch fileName {
+	case COMPONENTS_SCHEMA_FILE:
+		return GetComponentsSchema()
+
+	case CONFIG_SCHEMA_FILE:
+		return GetConfigSchema()
+
+	case DECLARATIONS_FILE:
+		return []byte(GetTypeDeclarations()), nil
+	}
+
+	return nil, nil
+}
+
 func (app *RedefineApp) PrintComponentsFromSingleFile(absoluteFilePath string) {
 	files := []string{absoluteFilePath}
-	astMap, syntaxKind := ast.BuildAstForFiles(files)
-	components := model.GetComponents(astMap, syntaxKind)
+	resolver := app.Config.getModuleResolver()
+	astMap, modules, syntaxKind, diagnostics := ast.BuildAstForFilesAndImports(context.Background(), files, app.Config.getParseOptions(), resolver.resolve)
+	LogDiagnostics(app.Logger, diagnostics)
+	components := model.GetComponents(astMap, syntaxKind, modules, app.Config.getExtractOptions())
 	jsonStr, _ := json.MarshalIndent(components, "", "  ")
 	fmt.Println(string(jsonStr))
 }
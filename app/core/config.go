@@ -14,13 +14,20 @@ package core
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	ast "sangupta.com/redefine/ast"
+	"sangupta.com/redefine/model"
 )
 
 // Structure format for the folder configuration
@@ -28,7 +35,11 @@ import (
 type ConfigFolder struct {
 	Root           string   `json:"root"`           // root folder relative to base folder
 	Includes       []string `json:"includes"`       // what files are included
-	Index          string   `json:"index"`          // the index file, if applicable
+	Excludes       []string `json:"excludes"`       // what files and folders are excluded
+	Roots          []string `json:"roots"`          // additional root folders, for sources spread across folders
+	GitIgnore      *bool    `json:"gitignore"`      // whether files ignored by git are excluded, defaults to true
+	FollowSymlinks bool     `json:"followSymlinks"` // whether folders linked via symbolic links are scanned
+	Index          string   `json:"index"`          // the index file, within the docs folder
 	HasFrontMatter bool     `json:"hasFrontMatter"` // whether the documentation has front matter or not
 }
 
@@ -53,13 +64,40 @@ type BuildConfig struct {
 // and other user supplied configuration when
 // invoking the redefine app.
 type RedefineConfig struct {
-	baseFolder  string            // the folder where redefine was run
-	packageJson *PackageJson      // the final package json that is read
-	libraryMap  map[string]string // map which stores the final library paths
-	SrcFolder   *ConfigFolder     `json:"src"`      // the base folder from where all components are read
-	DocsFolder  *ConfigFolder     `json:"docs"`     // folder from where docs are to be read
-	Build       *BuildConfig      `json:"build"`    // folder where output is written
-	Template    *ConfigTemplate   `json:"template"` // template configuration for view page
+	baseFolder   string              // the folder where redefine was run
+	logger       *slog.Logger        // the logger to report progress to
+	packageJson  *PackageJson        // the final package json that is read
+	libraryMap   map[string]string   // map which stores the final library paths
+	sources      map[string]string   // where each value was set, keyed by its dotted path such as `src.root`
+	packages     []*workspacePackage // the packages of the workspace, each with its own configuration
+	Schema       string              `json:"$schema"`      // the schema of the file, used by editors to check and complete it
+	Extends      string              `json:"extends"`      // a configuration file, or package, whose values this configuration takes precedence over
+	SrcFolder    *ConfigFolder       `json:"src"`          // the base folder from where all components are read
+	DocsFolder   *ConfigFolder       `json:"docs"`         // folder from where docs are to be read
+	Build        *BuildConfig        `json:"build"`        // folder where output is written
+	Template     *ConfigTemplate     `json:"template"`     // template configuration for view page
+	Target       string              `json:"target"`       // the ECMAScript target to parse source files with
+	Repository   *RepositoryConfig   `json:"repository"`   // repository details to generate "view source" links
+	Dependencies *DependenciesConfig `json:"dependencies"` // how types declared by dependencies are read
+	TypeChecker  bool                `json:"typeChecker"`  // resolve props types using the Typescript type checker
+	Parser       string              `json:"parser"`       // the parser to read source files with, `typescript` or `go`
+	Typescript   string              `json:"typescript"`   // the `typescript.js` file of the Typescript compiler, run by the `typescript` parser
+	Limits       *LimitsConfig       `json:"limits"`       // limits on the Typescript parser, for files that hang or exhaust memory
+}
+
+// Limits on the Typescript parser, beyond which a file is skipped
+// and reported. Zero values use the defaults of the parser.
+type LimitsConfig struct {
+	ParseTimeout int    `json:"parseTimeout"` // seconds to parse a single file, or run the type checker
+	Memory       uint32 `json:"memory"`       // megabytes of memory the parser may use
+	Stack        uint32 `json:"stack"`        // kilobytes of stack the parser may use
+}
+
+// Configuration for reading props types that are declared
+// by the packages the project depends on
+type DependenciesConfig struct {
+	ResolveTypes        bool `json:"resolveTypes"`        // read types from the `.d.ts` files of packages in `node_modules`
+	ExpandDomAttributes bool `json:"expandDomAttributes"` // list inherited DOM attributes one by one, instead of a single entry
 }
 
 func (config *RedefineConfig) HasLibraryFile(id string) bool {
@@ -86,83 +124,419 @@ func (config *RedefineConfig) GetLibraryBytes(id string) []byte {
 	return bytes
 }
 
-// Extract redefine configuration params using the
-// OS arguments and/or redefine.config file present
-// in the current folder
-func GetRedefineConfig(baseFolder string) *RedefineConfig {
+// Where the configuration is read from
+type ConfigOptions struct {
+	BaseFolder  string       // the folder with `package.json`, that all paths are relative to
+	ConfigFile  string       // the configuration file to read, instead of looking for one in the base folder
+	Environment []string     // `KEY=value` pairs to read `REDEFINE_*` variables from, usually `os.Environ()`
+	Overrides   []string     // `key=value` pairs given on the command line, such as `src.root=lib`
+	Logger      *slog.Logger // the logger to report progress to, nothing is logged when `nil`
+	Package     string       // the name, or folder, of the only workspace package to read, all when empty
+}
+
+// Extract redefine configuration params from all the places
+// it may be given in, with those later in the list taking
+// precedence over those before:
+//
+//   - defaults
+//   - the `redefine` key of `package.json`
+//   - the configuration file, `redefine.config.{json,yaml,yml,toml}`
+//     or the one given in the options
+//   - `REDEFINE_*` environment variables
+//   - `key=value` overrides given on the command line
+//
+// Configuration files, and the `redefine` key, may extend another
+// file, whose values they take precedence over. Progress is reported
+// to the logger of the options.
+//
+// The packages of a workspace are each read with their own
+// configuration. If a single package is asked for, its configuration
+// is returned, as if it was not part of the workspace.
+//
+// Returns all problems found in the configuration. The
+// configuration is `nil` if any of them is an error.
+func GetRedefineConfig(options ConfigOptions) (*RedefineConfig, []ast.Diagnostic) {
+	logger := getLogger(options.Logger)
+	baseFolder := options.BaseFolder
+
+	loader := &configLoader{
+		options: &options,
+		logger:  logger,
+		values:  map[string]any{},
+		sources: map[string]string{},
+		loading: map[string]bool{},
+	}
+
 	// check if we have a package.json file in there
 	packageJsonFilePath := path.Join(baseFolder, "package.json")
-	fmt.Println("Reading package.json from: " + packageJsonFilePath)
-	packageJsonExists := FileExists(packageJsonFilePath)
-
-	// this is where we store all our configuration
-	var config *RedefineConfig
+	logger.Debug("Reading package.json", "path", packageJsonFilePath)
 
 	// read package.json file
 	var packageJson PackageJson
-	if packageJsonExists {
+	if FileExists(packageJsonFilePath) {
 		packageJsonFileContents, err := os.ReadFile(packageJsonFilePath)
-
-		// error is eaten as we can work on defaults
-		if err == nil {
+		if err != nil {
+			loader.diagnostics = append(loader.diagnostics, newConfigError(packageJsonFilePath, err))
+		} else {
+			// fields other than the redefine configuration are
+			// read as best as possible, warning of those ignored,
+			// while the problems of the redefine configuration are
+			// reported when it is read
 			json.Unmarshal(packageJsonFileContents, &packageJson)
+			ignored := slices.DeleteFunc(packageJson.unreadFields, func(field string) bool { return field == "redefine" })
+			loader.diagnostics = append(loader.diagnostics, checkIgnoredKeys(packageJsonFilePath, packageJsonFileContents, ignored)...)
 
 			// read redefine configuration from here
-			config = packageJson.Redefine
+			loader.readPackageJson(packageJsonFilePath, packageJsonFileContents)
 		}
 	}
 
-	// if nothing is present in package.json file
-	// let's check if we have `redefine.config.json` file
-	if config == nil {
-		config = readRedefineConfig(baseFolder)
+	// values in the configuration file take precedence
+	// over those in package.json
+	if configFilePath := loader.findConfigFile(); configFilePath != "" {
+		logger.Debug("Reading configuration file", "path", configFilePath)
+		loader.readFile(configFilePath, configFilePath)
 	}
 
-	// will this happen?
-	if config == nil {
-		fmt.Println("No configuration available, will use defaults")
-		config = &RedefineConfig{}
+	// followed by the environment and command line
+	loader.readEnvironment(options.Environment)
+	loader.readOverrides(options.Overrides)
+
+	diagnostics := loader.diagnostics
+	if len(loader.values) == 0 && !ast.HasErrors(diagnostics) {
+		logger.Info("No configuration available, will use defaults")
+	}
+
+	// the values were checked when read, and can be decoded. When
+	// problems were found, the values are decoded as far as they can
+	// be, so that the rest of the configuration is checked all the same
+	config := &RedefineConfig{}
+	merged, _ := json.Marshal(loader.values)
+	if err := json.Unmarshal(merged, config); err != nil && !ast.HasErrors(diagnostics) {
+		return nil, append(diagnostics, newConfigError("", err))
 	}
 
 	// setup base folder
 	config.packageJson = &packageJson
 	config.baseFolder = baseFolder
+	config.logger = logger
+	config.sources = loader.sources
+
+	// check the paths as given, before defaults are applied
+	diagnostics = append(diagnostics, validateConfigPaths(config)...)
 
 	// normalize configuration
 	normalizeConfiguration(config, &packageJson)
+	diagnostics = append(diagnostics, validateConfigTarget(config)...)
+	diagnostics = append(diagnostics, validateConfigParser(config)...)
+	if ast.HasErrors(diagnostics) {
+		return nil, diagnostics
+	}
+
+	// read the packages of a workspace
+	packages, problems := config.readWorkspaces(options)
+	diagnostics = append(diagnostics, problems...)
+	if ast.HasErrors(diagnostics) {
+		return nil, diagnostics
+	}
+
+	if options.Package != "" {
+		return packages[0].config, diagnostics
+	}
+
+	// the libraries of the packages are served along with that
+	// of the workspace
+	config.packages = packages
+	for _, workspacePackage := range packages {
+		for id, libraryPath := range workspacePackage.config.libraryMap {
+			if config.libraryMap == nil {
+				config.libraryMap = make(map[string]string)
+			}
+			config.libraryMap[id] = libraryPath
+		}
+	}
 
 	// all done
-	return config
+	return config, diagnostics
 }
 
-// Read `redefine.config.json` from the given folder, if present.
-// If file is not present, return a simple default structure so
-// that we can populate same with package.json and other sensible
-// defaults.
-func readRedefineConfig(baseFolder string) *RedefineConfig {
-	fmt.Println("No redefine config was found inside package.json, looking for redefine.config.json...")
-	config := RedefineConfig{}
-
-	// check if the path passed is to a folder containing redefine.config.json
-	// file or to the place that we need to scan
-	configFilePath := path.Join(baseFolder, "redefine.config.json")
-	configFile := FileExists(configFilePath)
-
-	if configFile {
-		fmt.Println("Found redefine.config.json at: " + configFilePath)
-		// read the JSON file and populate the structure
-		configFileContents, err := os.ReadFile(configFilePath)
-		if err != nil {
-			fmt.Println("redefine.config.json file present, unable to read file.")
-			return nil
+// Reads the configuration from all the places it is given in,
+// merging the values of each into those read before.
+type configLoader struct {
+	options     *ConfigOptions
+	logger      *slog.Logger
+	values      map[string]any    // the merged values, keyed as in configuration files
+	sources     map[string]string // where each value was read from, keyed by its dotted path
+	diagnostics []ast.Diagnostic
+	loading     map[string]bool // the files being read, to detect files that extend themselves
+}
+
+// Return the configuration file given in the options or environment,
+// or the first one present in the base folder. Returns an empty string
+// if there is none.
+func (loader *configLoader) findConfigFile() string {
+	if loader.options.ConfigFile != "" {
+		return loader.options.ConfigFile
+	}
+
+	for _, variable := range loader.options.Environment {
+		name, value, _ := strings.Cut(variable, "=")
+		if name == ENV_CONFIG_FILE && value != "" {
+			return value
+		}
+	}
+
+	found := ""
+	for _, fileName := range configFileNames {
+		configFilePath := path.Join(loader.options.BaseFolder, fileName)
+		if !FileExists(configFilePath) {
+			continue
+		}
+
+		if found == "" {
+			found = configFilePath
+			continue
+		}
+
+		loader.diagnostics = append(loader.diagnostics, ast.Diagnostic{
+			File:     configFilePath,
+			Severity: ast.SEVERITY_WARNING,
+			Message:  "ignored, as the configuration is read from " + found,
+		})
+	}
+
+	return found
+}
+
+// Read the `redefine` key of `package.json`, if present.
+func (loader *configLoader) readPackageJson(file string, contents []byte) {
+	var config *RedefineConfig
+	problems, _ := decodeConfig(file, contents, "redefine", &config)
+	loader.diagnostics = append(loader.diagnostics, problems...)
+
+	// values of the wrong type are merged all the same, and dropped
+	// when the configuration is decoded
+	var packageJson struct {
+		Redefine map[string]any `json:"redefine"`
+	}
+	if err := json.Unmarshal(contents, &packageJson); err != nil || packageJson.Redefine == nil {
+		return
+	}
+
+	loader.merge(packageJson.Redefine, file, loader.options.BaseFolder)
+}
+
+// Read the configuration file, which is JSON, YAML or TOML depending
+// on its extension. The source is the file that refers to it, used to
+// report a file that is missing.
+func (loader *configLoader) readFile(file string, source string) {
+	absolutePath, _ := filepath.Abs(file)
+	if loader.loading[absolutePath] {
+		loader.diagnostics = append(loader.diagnostics, newConfigError(source, errors.New("extends "+file+", which extends this file again")))
+		return
+	}
+
+	loader.loading[absolutePath] = true
+	defer delete(loader.loading, absolutePath)
+
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		loader.diagnostics = append(loader.diagnostics, newConfigError(source, err))
+		return
+	}
+
+	// YAML and TOML files are converted to JSON, and the problems
+	// found in it moved to where they are in the file
+	jsonContents, lines, problems := convertConfigFile(file, contents)
+	loader.diagnostics = append(loader.diagnostics, problems...)
+	if jsonContents == nil {
+		return
+	}
+
+	var config RedefineConfig
+	problems, _ = decodeConfig(file, jsonContents, "", &config)
+	mapConfigDiagnostics(problems, lines)
+	loader.diagnostics = append(loader.diagnostics, problems...)
+
+	var values map[string]any
+	if err := json.Unmarshal(jsonContents, &values); err != nil {
+		return
+	}
+
+	loader.merge(values, file, filepath.Dir(file))
+}
+
+// Merge the values read from the source into those read before,
+// after the values of the file it extends, if any. The folder is
+// where the file it extends is looked up from.
+func (loader *configLoader) merge(values map[string]any, source string, folder string) {
+	for key, value := range values {
+		extends, isString := value.(string)
+		if strings.EqualFold(key, "extends") && isString && extends != "" {
+			loader.readFile(loader.resolveExtends(folder, extends), source)
 		}
+	}
 
-		// unmarshal the file
-		fmt.Println("Init using redefine.config.json...")
-		json.Unmarshal(configFileContents, &config)
+	mergeConfigValues(loader.values, values, reflect.TypeOf(RedefineConfig{}), "", source, loader.sources)
+}
+
+// Return the path of the file to extend. Paths starting with `.`
+// are relative to the folder of the file extending them. Otherwise,
+// this is a package in `node_modules`, or a file within one, such as
+// `@company/redefine-config`, with the configuration file at its root.
+func (loader *configLoader) resolveExtends(folder string, extends string) string {
+	if strings.HasPrefix(extends, ".") || filepath.IsAbs(extends) {
+		return filepath.Join(folder, extends)
+	}
+
+	packagePath := filepath.Join(loader.options.BaseFolder, "node_modules", extends)
+	if info, err := os.Stat(packagePath); err == nil && info.IsDir() {
+		for _, fileName := range configFileNames {
+			configFilePath := filepath.Join(packagePath, fileName)
+			if FileExists(configFilePath) {
+				return configFilePath
+			}
+		}
+
+		return filepath.Join(packagePath, configFileNames[0])
+	}
+
+	return packagePath
+}
+
+// Read the `REDEFINE_*` environment variables, such as
+// `REDEFINE_SRC_ROOT`. Unknown variables are reported as warnings.
+func (loader *configLoader) readEnvironment(environment []string) {
+	keys := map[string]string{}
+	forEachConfigKey(reflect.TypeOf(RedefineConfig{}), "", func(path string, valueType reflect.Type) {
+		keys[getConfigEnvName(path)] = path
+	})
+
+	variables := append([]string{}, environment...)
+	sort.Strings(variables)
+
+	for _, variable := range variables {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, ENV_PREFIX) || name == ENV_CONFIG_FILE {
+			continue
+		}
+
+		path, exists := keys[name]
+		if !exists {
+			names := make([]string, 0, len(keys)+1)
+			for key := range keys {
+				names = append(names, key)
+			}
+			sort.Strings(names)
+
+			message := "unknown environment variable " + name
+			if suggestion := SuggestName(append(names, ENV_CONFIG_FILE), name); suggestion != "" {
+				message += ", did you mean " + suggestion + "?"
+			}
+
+			loader.diagnostics = append(loader.diagnostics, ast.Diagnostic{Severity: ast.SEVERITY_WARNING, Message: message})
+			continue
+		}
+
+		loader.set(path, value, name)
 	}
+}
 
-	return &config
+// Read the `key=value` overrides given on the command line.
+func (loader *configLoader) readOverrides(overrides []string) {
+	for _, override := range overrides {
+		key, value, found := strings.Cut(override, "=")
+		if !found {
+			loader.diagnostics = append(loader.diagnostics, newConfigError("--set", errors.New("expected `key=value`, found "+override)))
+			continue
+		}
+
+		path, _, exists := findConfigKey(strings.TrimSpace(key))
+		if !exists {
+			loader.diagnostics = append(loader.diagnostics, newConfigError("--set", errors.New("unknown key `"+key+"`")))
+			continue
+		}
+
+		loader.set(path, value, "--set "+path)
+	}
+}
+
+// Set the value at the dotted path from its text.
+func (loader *configLoader) set(path string, text string, source string) {
+	_, valueType, _ := findConfigKey(path)
+	value, err := parseConfigValue(text, valueType)
+	if err != nil {
+		loader.diagnostics = append(loader.diagnostics, newConfigError(source, errors.New("`"+path+"` "+err.Error())))
+		return
+	}
+
+	keys := strings.Split(path, ".")
+	values := loader.values
+	for _, key := range keys[:len(keys)-1] {
+		child, isMap := values[key].(map[string]any)
+		if !isMap {
+			child = map[string]any{}
+			values[key] = child
+		}
+
+		values = child
+	}
+
+	values[keys[len(keys)-1]] = value
+	loader.sources[path] = source
+}
+
+// Merge the values into the target, keyed by the JSON names of the
+// fields of the struct type they are decoded into. Objects are merged
+// key by key, while all other values, including arrays, replace those
+// in the target. Unknown keys, which were reported when the values
+// were read, are dropped.
+func mergeConfigValues(target map[string]any, values map[string]any, structType reflect.Type, path string, source string, sources map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		field, found := findJsonField(structType, key)
+		if !found || value == nil || fileOnlyConfigKeys[getJsonName(field)] {
+			continue
+		}
+
+		name := getJsonName(field)
+		fieldPath := joinConfigPath(path, name)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		object, isObject := value.(map[string]any)
+		if isObject && fieldType.Kind() == reflect.Struct && !reflect.PointerTo(fieldType).Implements(jsonUnmarshalerType) {
+			child, isMap := target[name].(map[string]any)
+			if !isMap {
+				child = map[string]any{}
+				target[name] = child
+			}
+
+			mergeConfigValues(child, object, fieldType, fieldPath, source, sources)
+			continue
+		}
+
+		target[name] = value
+		sources[fieldPath] = source
+	}
+}
+
+// Create an error diagnostic for a configuration file that could
+// not be read.
+func newConfigError(file string, err error) ast.Diagnostic {
+	return ast.Diagnostic{
+		File:     file,
+		Severity: ast.SEVERITY_ERROR,
+		Message:  err.Error(),
+	}
 }
 
 // this method normalizes configuration based
@@ -177,15 +551,7 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 
 	// normalize the base folder path
 	if config.SrcFolder.Root == "" {
-		// see if we have 'src', 'lib', or 'packages' folder existing
-		// if yes, we will default to it
-		if FileExists(config.NormalizeFolderPath("src")) {
-			config.SrcFolder.Root = "src"
-		} else if FileExists(config.NormalizeFolderPath("lib")) {
-			config.SrcFolder.Root = "src"
-		} else if FileExists(config.NormalizeFolderPath("packages")) {
-			config.SrcFolder.Root = "src"
-		}
+		config.SrcFolder.Root = config.detectSourceRoot()
 	}
 	config.SrcFolder.Root = config.NormalizeFolderPath(config.SrcFolder.Root)
 
@@ -194,6 +560,16 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 		config.SrcFolder.Includes = []string{"*.ts", "*.tsx", "*.js", "*.jsx"}
 	}
 
+	// for excludes
+	if config.SrcFolder.Excludes == nil {
+		config.SrcFolder.Excludes = append([]string{}, defaultSourceExcludes...)
+	}
+
+	// additional roots
+	for index, root := range config.SrcFolder.Roots {
+		config.SrcFolder.Roots[index] = config.NormalizeFolderPath(root)
+	}
+
 	// -----------------------------------------------
 	// normalize the docs folder path
 	if config.DocsFolder == nil {
@@ -208,10 +584,11 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 	// base path
 	config.DocsFolder.Root = config.NormalizeFolderPath(config.DocsFolder.Root)
 
-	// the index file
+	// the index file, within the docs folder
 	if config.DocsFolder.Index == "" {
 		config.DocsFolder.Index = "index.md"
 	}
+	config.DocsFolder.Index = getAbsolutePath(config.DocsFolder.Root, config.DocsFolder.Index)
 
 	// what constitutes documentation file
 	if len(config.DocsFolder.Includes) == 0 {
@@ -251,8 +628,9 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 	}
 
 	// normalize the library by replacing the JS name with a UUID
-	if config.Build.Lib == "" {
+	if config.Build.Lib == "" && packageJson.MainFile != "" {
 		config.Build.Lib = packageJson.MainFile
+		config.setSource("build.lib", "package.json main")
 	}
 	if config.Build.Lib != "" {
 		config.libraryMap = make(map[string]string)
@@ -269,6 +647,45 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 		}
 	}
 
+	// -----------------------------------------------
+	// read the parsing target from tsconfig.json, if not specified
+	if config.Target == "" {
+		tsConfig := readTsConfig(config.baseFolder)
+		if tsConfig != nil && tsConfig.CompilerOptions != nil && tsConfig.CompilerOptions.Target != "" {
+			config.Target = tsConfig.CompilerOptions.Target
+			config.setSource("target", "tsconfig.json")
+		}
+	}
+
+	// -----------------------------------------------
+	// the parser to read source files with
+	if config.Parser == "" {
+		config.Parser = ast.PARSER_TYPESCRIPT
+	}
+
+	// the Typescript compiler installed in the project, or in a
+	// folder above it such as the root of a workspace
+	if config.Typescript == "" {
+		baseFolder, _ := filepath.Abs(config.baseFolder)
+		config.Typescript = findInNodeModules(baseFolder, "typescript/lib/typescript.js")
+	} else {
+		config.Typescript = getAbsolutePath(config.NormalizeFolderPath(""), config.Typescript)
+	}
+
+	if config.Limits == nil {
+		config.Limits = &LimitsConfig{}
+	}
+
+	// -----------------------------------------------
+	// reading types of dependencies
+	if config.Dependencies == nil {
+		config.Dependencies = &DependenciesConfig{}
+	}
+
+	// -----------------------------------------------
+	// details to link to the source code repository
+	normalizeRepositoryConfig(config, packageJson)
+
 	// -----------------------------------------------
 	// normalize template details
 	if config.Template == nil {
@@ -284,6 +701,19 @@ func normalizeConfiguration(config *RedefineConfig, packageJson *PackageJson) {
 	}
 }
 
+// Return the folder components are most likely in, which is
+// the first of `src`, `lib` or `packages` that exists, or an
+// empty string for the base folder itself.
+func (config *RedefineConfig) detectSourceRoot() string {
+	for _, folder := range []string{"src", "lib", "packages"} {
+		if FileExists(config.NormalizeFolderPath(folder)) {
+			return folder
+		}
+	}
+
+	return ""
+}
+
 // Function to normalize a folder path by prefixing
 // the base folder path and joining if with the given
 // child path
@@ -297,54 +727,115 @@ func (config *RedefineConfig) NormalizeFolderPath(childPath string) string {
 	return folder
 }
 
-// Scan a folder for all files that match a given pattern.
-// Returns an array of absolute paths, along with an `error`
-// object which is `nil` if successful.
-//
-// @param baseFolder the base location that needs to be scanned
-//
-// @param includes an array of wildcard patterns that select files
-func (config *RedefineConfig) scanFolder() ([]string, error) {
-	totalFiles := []string{}
-
-	filepath.Walk(config.SrcFolder.Root, func(path string, fileInfo os.FileInfo, err error) error {
-		if err != nil {
-			log.Fatal("Unable to read files from path: " + path)
-			return nil
+// Return the folder path relative to the source root containing
+// it, using forward slashes. A root itself is returned as an empty
+// string, and folders outside of all roots are returned unchanged.
+func (config *RedefineConfig) getRelativeSourcePath(folder string) string {
+	for _, root := range config.SrcFolder.GetRoots() {
+		relative, err := filepath.Rel(root, folder)
+		if err != nil || strings.HasPrefix(relative, "..") {
+			continue
 		}
 
-		// skip if this is a folder
-		if fileInfo.IsDir() {
-			return nil
+		if relative == "." {
+			return ""
 		}
 
-		// get absolute path for path
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			log.Fatal(err)
-		}
+		return filepath.ToSlash(relative)
+	}
 
-		// check if the file is included or not
-		if !isFileIncluded(absPath, config.SrcFolder.Includes) {
-			return nil
-		}
+	return folder
+}
+
+// Return all root folders, the primary root followed by
+// any additional roots.
+func (folder *ConfigFolder) GetRoots() []string {
+	roots := make([]string, 0, 1+len(folder.Roots))
+	if folder.Root != "" {
+		roots = append(roots, folder.Root)
+	}
 
-		// add file to list
-		totalFiles = append(totalFiles, absPath)
+	return append(roots, folder.Roots...)
+}
+
+// Return the options to extract components with
+func (config *RedefineConfig) getExtractOptions() *model.ExtractOptions {
+	if config == nil || config.Dependencies == nil {
 		return nil
-	})
+	}
+
+	return &model.ExtractOptions{
+		ExpandDomAttributes: config.Dependencies.ExpandDomAttributes,
+		Logger:              config.logger,
+	}
+}
+
+// Return the options to parse the source files with
+func (config *RedefineConfig) getParseOptions() *ast.ParseOptions {
+	if config == nil {
+		return nil
+	}
+
+	options := &ast.ParseOptions{
+		Target:      config.Target,
+		TypeChecker: config.TypeChecker,
+		Parser:      config.Parser,
+		Logger:      config.logger,
+
+		TypescriptFile: config.Typescript,
+	}
+
+	if config.Limits != nil {
+		options.Timeout = time.Duration(config.Limits.ParseTimeout) * time.Second
+		options.MemoryLimit = config.Limits.Memory
+		options.StackLimit = config.Limits.Stack
+	}
 
-	return totalFiles, nil
+	return options
 }
 
-// Simple debug function to print information
-// regarding what is being used
-func (config *RedefineConfig) PrintInfo() {
-	fmt.Println("\nUsing following configuration:")
-	fmt.Println("    Src folder: " + config.SrcFolder.Root)
-	fmt.Printf("    Src includes: %v\n", config.SrcFolder.Includes)
-	fmt.Println("    Lib file: " + config.Build.Lib)
-	fmt.Println("    Docs folder: " + config.DocsFolder.Root)
-	fmt.Println("    Docs index: " + config.DocsFolder.Index)
-	fmt.Println()
+// Return where the value at the dotted path, such as `src.root`,
+// was set, or `default` if it was not set.
+func (config *RedefineConfig) getSource(path string) string {
+	if source, exists := config.sources[path]; exists {
+		return source
+	}
+
+	return "default"
+}
+
+func (config *RedefineConfig) setSource(path string, source string) {
+	if config.sources == nil {
+		config.sources = make(map[string]string)
+	}
+
+	config.sources[path] = source
+}
+
+// Simple debug function to log information
+// regarding what is being used, along with
+// where each value came from
+func (config *RedefineConfig) LogInfo() {
+	value := func(name string, path string, value any) slog.Attr {
+		return slog.Group(name, "value", value, "from", config.getSource(path))
+	}
+
+	getLogger(config.logger).Info("Using configuration",
+		value("src", "src.root", config.SrcFolder.Root),
+		value("roots", "src.roots", config.SrcFolder.Roots),
+		value("includes", "src.includes", config.SrcFolder.Includes),
+		value("excludes", "src.excludes", config.SrcFolder.Excludes),
+		value("lib", "build.lib", config.Build.Lib),
+		value("docs", "docs.root", config.DocsFolder.Root),
+		value("docsIndex", "docs.index", config.DocsFolder.Index),
+		value("target", "target", config.Target),
+		value("typeChecker", "typeChecker", config.TypeChecker),
+		value("parser", "parser", config.Parser),
+		value("typescript", "typescript", config.Typescript),
+	)
+
+	for _, workspacePackage := range config.packages {
+		getLogger(config.logger).Info("Using workspace package", "name", workspacePackage.name, "path", workspacePackage.folder)
+		workspacePackage.config.LogInfo()
+	}
 }
@@ -0,0 +1,285 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	ast "sangupta.com/redefine/ast"
+)
+
+// This file contains the code to read configuration files written
+// in YAML or TOML. These are read into a tree of values that remembers
+// where each value was written, when it is known, which is then
+// converted to JSON so that all files are checked by the same validator.
+
+// The names of the configuration files looked up in the base
+// folder, in the order of preference
+var configFileNames = []string{
+	"redefine.config.json",
+	"redefine.config.yaml",
+	"redefine.config.yml",
+	"redefine.config.toml",
+}
+
+// A position within a configuration file
+type filePosition struct {
+	line   int
+	column int
+}
+
+// The kinds of values in a configuration file
+type configNodeKind int
+
+const (
+	configScalar configNodeKind = iota
+	configArray
+	configObject
+)
+
+// A value read from a configuration file, along with where it was
+// written.
+type configNode struct {
+	kind     configNodeKind
+	scalar   any            // the value of a scalar, a string, bool, int64, float64 or `nil`
+	items    []*configNode  // the items of an array
+	fields   []*configField // the fields of an object, in the order they were written, or by key for TOML
+	position filePosition   // zero when not known, as for TOML
+}
+
+// A field of an object in a configuration file
+type configField struct {
+	key      string
+	position filePosition
+	value    *configNode
+}
+
+// Return the field of the object with the given key, if any.
+func (node *configNode) getField(key string) *configNode {
+	for _, field := range node.fields {
+		if field.key == key {
+			return field.value
+		}
+	}
+
+	return nil
+}
+
+// Convert the contents of a configuration file to JSON, if it is
+// written in YAML or TOML. Returns the JSON, along with the original
+// position of every line of the JSON, which is `nil` for JSON files.
+func convertConfigFile(file string, contents []byte) ([]byte, []filePosition, []ast.Diagnostic) {
+	var root *configNode
+	var err error
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		root, err = parseYamlConfig(contents)
+
+	case ".toml":
+		root, err = parseTomlConfig(contents)
+
+	default:
+		return contents, nil, nil
+	}
+
+	if err != nil {
+		return nil, nil, []ast.Diagnostic{newConfigSyntaxError(file, err)}
+	}
+
+	writer := &configJsonWriter{}
+	err = writer.write(root, "")
+	if err != nil {
+		return nil, nil, []ast.Diagnostic{newConfigSyntaxError(file, err)}
+	}
+
+	return writer.buffer.Bytes(), writer.lines, nil
+}
+
+// Move the diagnostics found in the JSON converted from a YAML or
+// TOML file to where the values were written in the original file.
+func mapConfigDiagnostics(diagnostics []ast.Diagnostic, lines []filePosition) {
+	if lines == nil {
+		return
+	}
+
+	for index := range diagnostics {
+		diagnostic := &diagnostics[index]
+		if diagnostic.Line > 0 && diagnostic.Line <= len(lines) {
+			position := lines[diagnostic.Line-1]
+			diagnostic.Line = position.line
+			diagnostic.Column = position.column
+		}
+	}
+}
+
+// An error found when reading a YAML or TOML file, at a position
+// within the file when it is known.
+type configSyntaxError struct {
+	position filePosition
+	message  string
+}
+
+func (err *configSyntaxError) Error() string {
+	return err.message
+}
+
+// The line number in the errors returned by the YAML decoder
+var yamlLineRegex = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// Create an error diagnostic for a syntax error in a YAML or TOML file.
+func newConfigSyntaxError(file string, err error) ast.Diagnostic {
+	diagnostic := newConfigError(file, err)
+
+	var syntaxError *configSyntaxError
+	if errors.As(err, &syntaxError) {
+		diagnostic.Line = syntaxError.position.line
+		diagnostic.Column = syntaxError.position.column
+	} else if match := yamlLineRegex.FindStringSubmatch(diagnostic.Message); match != nil {
+		diagnostic.Line, _ = strconv.Atoi(match[1])
+		diagnostic.Column = 1
+		diagnostic.Message = "invalid YAML: " + diagnostic.Message[len(match[0]):]
+	}
+
+	return diagnostic
+}
+
+// Read the YAML contents into a tree of values. An empty document
+// is read as an empty object.
+func parseYamlConfig(contents []byte) (*configNode, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return &configNode{kind: configObject, position: filePosition{1, 1}}, nil
+	}
+
+	return convertYamlNode(document.Content[0])
+}
+
+// Convert the YAML node to a tree of values, following aliases.
+func convertYamlNode(node *yaml.Node) (*configNode, error) {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	result := &configNode{position: filePosition{node.Line, node.Column}}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		result.kind = configObject
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key, value := node.Content[index], node.Content[index+1]
+
+			converted, err := convertYamlNode(value)
+			if err != nil {
+				return nil, err
+			}
+
+			result.fields = append(result.fields, &configField{
+				key:      key.Value,
+				position: filePosition{key.Line, key.Column},
+				value:    converted,
+			})
+		}
+
+	case yaml.SequenceNode:
+		result.kind = configArray
+		for _, item := range node.Content {
+			converted, err := convertYamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result.items = append(result.items, converted)
+		}
+
+	default:
+		result.kind = configScalar
+		err := node.Decode(&result.scalar)
+		if err != nil {
+			return nil, &configSyntaxError{result.position, "invalid YAML: " + err.Error()}
+		}
+	}
+
+	return result, nil
+}
+
+// Writes a tree of values as JSON, with every key and value on a
+// line of its own, so that every line can be traced back to where
+// it was written.
+type configJsonWriter struct {
+	buffer bytes.Buffer
+	lines  []filePosition // the original position of every line written
+}
+
+// Write the node, starting its first line with the prefix, which
+// separates it from the previous value.
+func (writer *configJsonWriter) write(node *configNode, prefix string) error {
+	switch node.kind {
+	case configObject:
+		writer.line(prefix+"{", node.position)
+		for index, field := range node.fields {
+			key, _ := json.Marshal(field.key)
+			writer.line(getJsonSeparator(index)+string(key)+":", field.position)
+
+			if err := writer.write(field.value, ""); err != nil {
+				return err
+			}
+		}
+		writer.line("}", node.position)
+
+	case configArray:
+		writer.line(prefix+"[", node.position)
+		for index, item := range node.items {
+			if err := writer.write(item, getJsonSeparator(index)); err != nil {
+				return err
+			}
+		}
+		writer.line("]", node.position)
+
+	default:
+		value, err := json.Marshal(node.scalar)
+		if err != nil {
+			return &configSyntaxError{node.position, "value cannot be used in the configuration: " + err.Error()}
+		}
+
+		writer.line(prefix+string(value), node.position)
+	}
+
+	return nil
+}
+
+func (writer *configJsonWriter) line(text string, position filePosition) {
+	writer.buffer.WriteString(text)
+	writer.buffer.WriteByte('\n')
+	writer.lines = append(writer.lines, position)
+}
+
+func getJsonSeparator(index int) string {
+	if index == 0 {
+		return ""
+	}
+
+	return ","
+}
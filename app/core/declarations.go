@@ -0,0 +1,149 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sangupta.com/redefine/model"
+)
+
+// The file name the Typescript declarations are written to by `build`,
+// and served at by `serve`, next to `components.json`
+const DECLARATIONS_FILE = "components.d.ts"
+
+// Names of the interfaces declared for struct types, where these
+// differ from the Go names, kept as the client has always named them
+var declarationNames = map[reflect.Type]string{
+	reflect.TypeOf(jsonPayload{}):     "RedefinePayload",
+	reflect.TypeOf(PackageAuthor{}):   "Author",
+	reflect.TypeOf(model.Component{}): "ComponentDef",
+	reflect.TypeOf(packagePayload{}):  "PackageDef",
+}
+
+// Typescript types of types that are not described by their Go type alone
+var knownDeclarations = map[reflect.Type]string{
+	reflect.TypeOf(model.ComponentType(0)): strconv.Quote(model.REACT_CLASS_COMPONENT.String()) + " | " + strconv.Quote(model.REACT_FUNCTION_COMPONENT.String()),
+}
+
+// Property names that can be written without quotes
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Writes the interfaces of struct types, along with those of all
+// the struct types they refer to.
+type declarationWriter struct {
+	builder strings.Builder
+	queue   []reflect.Type        // the struct types still to be written
+	names   map[reflect.Type]bool // the struct types written or queued
+}
+
+// Return the Typescript declarations of the `components.json` file,
+// as global interfaces. The client adds its own fields to these by
+// declaring interfaces of the same name.
+func GetTypeDeclarations() string {
+	writer := &declarationWriter{names: map[reflect.Type]bool{}}
+
+	writer.builder.WriteString("/**\n")
+	writer.builder.WriteString(" * Generated by `redefine types` from the Go model of `components.json`,\n")
+	writer.builder.WriteString(" * schema version " + strconv.Itoa(COMPONENTS_SCHEMA_VERSION) + ". Do not edit.\n")
+	writer.builder.WriteString(" */\n")
+
+	writer.getType(reflect.TypeOf(jsonPayload{}))
+	for len(writer.queue) > 0 {
+		structType := writer.queue[0]
+		writer.queue = writer.queue[1:]
+
+		writer.builder.WriteRune('\n')
+		writer.writeInterface(structType)
+	}
+
+	return writer.builder.String()
+}
+
+// Write the interface of the struct type, with a property for
+// every field written to JSON. All fields are always written, and
+// none of the properties are optional.
+func (writer *declarationWriter) writeInterface(structType reflect.Type) {
+	writer.builder.WriteString("interface " + getDeclarationName(structType) + " {\n")
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := getJsonName(field)
+		if !identifierRegex.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+
+		writer.builder.WriteString("    " + name + ": " + writer.getType(field.Type) + ";\n")
+	}
+
+	writer.builder.WriteString("}\n")
+}
+
+// Return the Typescript type of the Go type, queueing the struct
+// types it refers to that have not been written yet.
+func (writer *declarationWriter) getType(valueType reflect.Type) string {
+	// `null` is written for nil pointers, slices and maps
+	if valueType.Kind() == reflect.Pointer {
+		return writer.getType(valueType.Elem()) + " | null"
+	}
+
+	if declaration, exists := knownDeclarations[valueType]; exists {
+		return declaration
+	}
+
+	switch valueType.Kind() {
+	case reflect.Struct:
+		if !writer.names[valueType] {
+			writer.names[valueType] = true
+			writer.queue = append(writer.queue, valueType)
+		}
+
+		return getDeclarationName(valueType)
+
+	case reflect.Map:
+		return "Record<string, " + writer.getType(valueType.Elem()) + "> | null"
+
+	case reflect.Slice, reflect.Array:
+		return "Array<" + writer.getType(valueType.Elem()) + "> | null"
+
+	case reflect.String:
+		return "string"
+
+	case reflect.Bool:
+		return "boolean"
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+
+	// interfaces hold values of any shape
+	return "unknown"
+}
+
+// Return the name of the interface declared for the struct type.
+func getDeclarationName(structType reflect.Type) string {
+	if name, exists := declarationNames[structType]; exists {
+		return name
+	}
+
+	return structType.Name()
+}
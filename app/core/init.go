@@ -0,0 +1,310 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	ast "sangupta.com/redefine/ast"
+	"sangupta.com/redefine/model"
+)
+
+// This file contains the code for the `init` action, which looks
+// at the project to write a configuration file and documentation
+// files to start from. Files that exist are never overwritten.
+
+// The configuration file written by `init`, which is YAML so that
+// it can explain each value
+const INIT_CONFIG_FILE = "redefine.config.yaml"
+
+// Folders the documentation is looked for in, in order of preference
+var docsFolderNames = []string{"docs", "doc", "documentation"}
+
+// Files the library bundle is looked for in, when `package.json`
+// does not name one
+var libraryFileNames = []string{"dist/index.js", "dist/index.mjs", "build/index.js", "lib/index.js"}
+
+// What was found out about the project
+type projectInfo struct {
+	srcRoot    string   // the folder components are read from, relative to the project
+	language   string   // `Typescript` or `Javascript`, or empty when both are used
+	includes   []string // the files read as components, the defaults when empty
+	lib        string   // the library bundle, if found
+	docsRoot   string   // the folder documentation is read from
+	docsExists bool     // whether the documentation folder exists
+	docFiles   int      // the number of markdown files in the documentation folder
+	readme     string   // the readme of the project, if any
+}
+
+// Write a configuration file, along with an index and a file for each
+// component in the documentation folder, all based on what is found
+// in the project. Files that exist are left as they are. Returns the
+// problems found when reading the source files.
+func (app *RedefineApp) InitProject(ctx context.Context) ([]ast.Diagnostic, error) {
+	logger := app.getLogger()
+	config := &RedefineConfig{baseFolder: app.BaseFolder, logger: app.Logger}
+
+	// details of the package are read as best as possible
+	var packageJson PackageJson
+	var packageKeys map[string]json.RawMessage
+	packageJsonContents, err := os.ReadFile(path.Join(app.BaseFolder, "package.json"))
+	if err == nil {
+		json.Unmarshal(packageJsonContents, &packageJson)
+		json.Unmarshal(packageJsonContents, &packageKeys)
+	}
+
+	info := config.inspectProject(&packageJson)
+	logger.Info("Inspected project", "src", info.srcRoot, "language", info.language, "lib", info.lib, "docs", info.docsRoot, "docFiles", info.docFiles)
+
+	// write the configuration, unless there is one already
+	existing := ""
+	for _, fileName := range configFileNames {
+		if FileExists(config.NormalizeFolderPath(fileName)) {
+			existing = fileName
+		}
+	}
+	if _, exists := packageKeys["redefine"]; exists {
+		existing = "package.json"
+	}
+
+	if existing != "" {
+		logger.Warn("Configuration not written, as it is already present", "path", existing)
+	} else if err := app.writeInitFile(INIT_CONFIG_FILE, info.getConfigFile()); err != nil {
+		return nil, err
+	}
+
+	// the index page starts from the readme, if there is one
+	index := info.readme
+	if index == "" {
+		name := packageJson.Name
+		if name == "" {
+			name = filepath.Base(config.NormalizeFolderPath(""))
+		}
+
+		index = "# " + name + "\n\n" + packageJson.Description + "\n"
+	}
+
+	if err := app.writeInitFile(path.Join(info.docsRoot, "index.md"), index); err != nil {
+		return nil, err
+	}
+
+	// find the components, as they would be found with the configuration,
+	// using the Go parser which finds them the same way without needing
+	// anything else to be set up
+	config.SrcFolder = &ConfigFolder{Root: info.srcRoot, Includes: info.includes}
+	config.DocsFolder = &ConfigFolder{Root: info.docsRoot}
+	config.Parser = ast.PARSER_GO
+	normalizeConfiguration(config, &packageJson)
+	app.Config = config
+
+	components, diagnostics, err := app.extractComponents(ctx)
+	if err != nil {
+		return diagnostics, err
+	}
+
+	// and start the documentation of those that have none
+	config.readDocs(components)
+	for _, component := range components {
+		if component.DocFileName != "" {
+			continue
+		}
+
+		err := app.writeInitFile(path.Join(info.docsRoot, component.Id+".md"), getComponentDocStub(component))
+		if err != nil {
+			return diagnostics, err
+		}
+	}
+
+	return diagnostics, nil
+}
+
+// Find out where the source, documentation and library bundle of
+// the project are, and the language it is written in.
+func (config *RedefineConfig) inspectProject(packageJson *PackageJson) *projectInfo {
+	info := &projectInfo{srcRoot: config.detectSourceRoot()}
+
+	// count the source files of each language
+	typescript, javascript := 0, 0
+	filepath.WalkDir(config.NormalizeFolderPath(info.srcRoot), func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if name == "node_modules" || (strings.HasPrefix(name, ".") && name != ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(name, ".d.ts"):
+		case strings.HasSuffix(name, ".ts"), strings.HasSuffix(name, ".tsx"):
+			typescript++
+		case strings.HasSuffix(name, ".js"), strings.HasSuffix(name, ".jsx"):
+			javascript++
+		}
+
+		return nil
+	})
+
+	switch {
+	case typescript > 0 && javascript == 0:
+		info.language = "Typescript"
+		info.includes = []string{"*.ts", "*.tsx"}
+
+	case javascript > 0 && typescript == 0:
+		info.language = "Javascript"
+		info.includes = []string{"*.js", "*.jsx"}
+	}
+
+	// the library bundle
+	info.lib = packageJson.MainFile
+	if info.lib == "" {
+		for _, fileName := range libraryFileNames {
+			if !strings.HasPrefix(fileName, info.srcRoot+"/") && FileExists(config.NormalizeFolderPath(fileName)) {
+				info.lib = fileName
+				break
+			}
+		}
+	}
+
+	// the documentation folder, and the markdown already in it
+	info.docsRoot = docsFolderNames[0]
+	for _, folder := range docsFolderNames {
+		if FileExists(config.NormalizeFolderPath(folder)) {
+			info.docsRoot = folder
+			info.docsExists = true
+			break
+		}
+	}
+
+	if info.docsExists {
+		filepath.WalkDir(config.NormalizeFolderPath(info.docsRoot), func(filePath string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+				info.docFiles++
+			}
+			return nil
+		})
+	}
+
+	for _, fileName := range []string{"README.md", "readme.md", "Readme.md"} {
+		if contents, err := os.ReadFile(config.NormalizeFolderPath(fileName)); err == nil {
+			info.readme = string(contents)
+			break
+		}
+	}
+
+	return info
+}
+
+// Return the contents of the configuration file, explaining each
+// value that was found, and those that may be set.
+func (info *projectInfo) getConfigFile() string {
+	var builder strings.Builder
+	line := func(text string) {
+		builder.WriteString(text)
+		builder.WriteRune('\n')
+	}
+
+	srcRoot := info.srcRoot
+	if srcRoot == "" {
+		srcRoot = "."
+	}
+
+	line("# Configuration for redefine, written by `redefine init` from what was")
+	line("# found in the project. Values that are commented out show the defaults.")
+	line("")
+	line("src:")
+	line("  # the folder components are read from")
+	line("  root: " + strconv.Quote(srcRoot))
+
+	if len(info.includes) > 0 {
+		line("  # the files components are read from, as the project is written in " + info.language)
+		line("  includes:")
+		for _, include := range info.includes {
+			line("    - " + strconv.Quote(include))
+		}
+	} else {
+		line("  # the files components are read from")
+		line("  # includes: [\"*.ts\", \"*.tsx\", \"*.js\", \"*.jsx\"]")
+	}
+
+	line("")
+	line("docs:")
+	line("  # the folder documentation is read from, `index.md` for the first page")
+	line("  # and `<component id>.md` for each component, such as `menu/Item.md`")
+	line("  root: " + strconv.Quote(info.docsRoot))
+	line("")
+	line("build:")
+
+	if info.lib != "" {
+		line("  # the library bundle, loaded in the page to render the examples")
+		line("  lib: " + strconv.Quote(info.lib))
+	} else {
+		line("  # the library bundle, loaded in the page to render the examples,")
+		line("  # which was not found, and defaults to `main` in package.json")
+		line("  # lib: \"dist/index.js\"")
+	}
+
+	line("  # the CSS files loaded in the page")
+	line("  # css: [\"dist/index.css\"]")
+	line("")
+	line("# the parser used to read source files, `typescript` or `go`")
+	line("# parser: \"typescript\"")
+
+	return builder.String()
+}
+
+// Return the documentation to start from for the component, with
+// an example that is also used as its playground.
+func getComponentDocStub(component model.Component) string {
+	example := "<" + component.Name + " />"
+
+	return "# Usage\n\n" +
+		"```jsx\n" + example + "\n```\n\n" +
+		"```js:playground\n" + example + "\n```\n"
+}
+
+// Write the file, relative to the base folder, creating the folders
+// leading to it. Files that exist are left as they are.
+func (app *RedefineApp) writeInitFile(fileName string, contents string) error {
+	logger := app.getLogger()
+	filePath := path.Join(app.BaseFolder, fileName)
+
+	if FileExists(filePath) {
+		logger.Info("Skipped, as the file exists", "path", filePath)
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filePath, []byte(contents), 0644)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Created", "path", filePath)
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	ast "sangupta.com/redefine/ast"
+)
+
+// The formats that logs can be written in
+const (
+	LOG_FORMAT_TEXT = "text" // `key=value` pairs, the default
+	LOG_FORMAT_JSON = "json" // one JSON object per record
+)
+
+// Create a logger that writes records at or above the given level
+// to the writer, in the given format. An empty format is the same
+// as `text`.
+func NewLogger(writer io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case "", LOG_FORMAT_TEXT:
+		return slog.New(slog.NewTextHandler(writer, options)), nil
+
+	case LOG_FORMAT_JSON:
+		return slog.New(slog.NewJSONHandler(writer, options)), nil
+	}
+
+	return nil, errors.New("unknown log format: " + format + ", use text or json")
+}
+
+// Return the given logger, or one that discards all records
+// if it is `nil`.
+func getLogger(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return logger
+}
+
+// Log all diagnostics at the level matching their severity,
+// followed by a count of errors and warnings.
+func LogDiagnostics(logger *slog.Logger, diagnostics []ast.Diagnostic) {
+	if len(diagnostics) == 0 {
+		return
+	}
+
+	logger = getLogger(logger)
+	errorCount := 0
+	warningCount := 0
+
+	for _, diagnostic := range diagnostics {
+		level := slog.LevelInfo
+		if diagnostic.IsError() {
+			level = slog.LevelError
+			errorCount++
+		} else if diagnostic.Severity == ast.SEVERITY_WARNING {
+			level = slog.LevelWarn
+			warningCount++
+		}
+
+		logger.Log(context.Background(), level, diagnostic.Message, getDiagnosticAttrs(diagnostic)...)
+	}
+
+	logger.Info("Found problems", "errors", errorCount, "warnings", warningCount)
+}
+
+// Return the location and code of the diagnostic as attributes
+// to log it with, leaving out those that are not known.
+func getDiagnosticAttrs(diagnostic ast.Diagnostic) []any {
+	attrs := make([]any, 0, 8)
+
+	if diagnostic.File != "" {
+		attrs = append(attrs, "file", diagnostic.File)
+	}
+	if diagnostic.Line > 0 {
+		attrs = append(attrs, "line", diagnostic.Line, "column", diagnostic.Column)
+	}
+	if diagnostic.Code > 0 {
+		attrs = append(attrs, "code", diagnostic.Code)
+	}
+
+	return attrs
+}
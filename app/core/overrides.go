@@ -0,0 +1,157 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file contains the code to set single configuration values
+// from environment variables and command line flags, such as
+// `REDEFINE_SRC_ROOT=lib` or `--set src.root=lib`.
+
+// The prefix of the environment variables that set configuration values
+const ENV_PREFIX = "REDEFINE_"
+
+// The environment variable with the path of the configuration file,
+// the same as the `--config` flag
+const ENV_CONFIG_FILE = ENV_PREFIX + "CONFIG"
+
+// Keys that are part of a configuration file, but are not values
+// that can be set on their own
+var fileOnlyConfigKeys = map[string]bool{
+	"$schema": true,
+	"extends": true,
+}
+
+// Call the function for every value of the configuration that can
+// be set on its own, with its dotted path such as `src.root`.
+func forEachConfigKey(structType reflect.Type, path string, fn func(path string, valueType reflect.Type)) {
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() || field.Tag.Get("json") == "-" || fileOnlyConfigKeys[getJsonName(field)] {
+			continue
+		}
+
+		fieldPath := joinConfigPath(path, getJsonName(field))
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType.Kind() == reflect.Struct && !reflect.PointerTo(fieldType).Implements(jsonUnmarshalerType) {
+			forEachConfigKey(fieldType, fieldPath, fn)
+			continue
+		}
+
+		fn(fieldPath, fieldType)
+	}
+}
+
+// Return the name of the environment variable that sets the value
+// at the dotted path, such as `REDEFINE_LIMITS_PARSE_TIMEOUT` for
+// `limits.parseTimeout`.
+func getConfigEnvName(path string) string {
+	var builder strings.Builder
+	builder.WriteString(ENV_PREFIX)
+
+	for index, c := range path {
+		switch {
+		case c == '.':
+			builder.WriteRune('_')
+
+		case unicode.IsUpper(c):
+			if index > 0 && path[index-1] != '.' {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(c)
+
+		default:
+			builder.WriteRune(unicode.ToUpper(c))
+		}
+	}
+
+	return builder.String()
+}
+
+// Find the value at the dotted path, matching keys without regard
+// to case. Returns the path as written in files, along with the type
+// of the value.
+func findConfigKey(path string) (string, reflect.Type, bool) {
+	var foundPath string
+	var foundType reflect.Type
+
+	forEachConfigKey(reflect.TypeOf(RedefineConfig{}), "", func(keyPath string, valueType reflect.Type) {
+		if foundType == nil && strings.EqualFold(keyPath, path) {
+			foundPath, foundType = keyPath, valueType
+		}
+	})
+
+	return foundPath, foundType, foundType != nil
+}
+
+// Parse the text into a value of the given type, as it would be
+// read from JSON. Lists are separated by commas.
+func parseConfigValue(text string, valueType reflect.Type) (any, error) {
+	switch valueType.Kind() {
+	case reflect.String:
+		return text, nil
+
+	case reflect.Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, errors.New("should be true or false, found " + text)
+		}
+		return value, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(text, 10, valueType.Bits())
+		if err != nil {
+			return nil, errors.New("should be a whole number, found " + text)
+		}
+		return value, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(text, 10, valueType.Bits())
+		if err != nil {
+			return nil, errors.New("should be a whole number, found " + text)
+		}
+		return value, nil
+
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(text, valueType.Bits())
+		if err != nil {
+			return nil, errors.New("should be a number, found " + text)
+		}
+		return value, nil
+
+	case reflect.Slice:
+		if valueType.Elem().Kind() != reflect.String {
+			break
+		}
+
+		values := []string{}
+		for _, value := range strings.Split(text, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values, nil
+	}
+
+	return nil, errors.New("cannot be set outside of a configuration file")
+}
@@ -12,22 +12,202 @@ that can be found in LICENSE file in the code repository.
 
 package core
 
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// A person in package.json, which may either be an object, or
+// a string like `Name <email> (url)` where email and url are optional.
 type PackageAuthor struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 	Url   string `json:"url"`
 }
 
+// The parts of the `Name <email> (url)` shorthand of a person
+var authorRegex = regexp.MustCompile(`^([^<(]*)(?:<([^>]*)>)?\s*(?:\(([^)]*)\))?`)
+
+func (author *PackageAuthor) UnmarshalJSON(data []byte) error {
+	var text string
+	if json.Unmarshal(data, &text) == nil {
+		match := authorRegex.FindStringSubmatch(strings.TrimSpace(text))
+		author.Name = strings.TrimSpace(match[1])
+		author.Email = strings.TrimSpace(match[2])
+		author.Url = strings.TrimSpace(match[3])
+		return nil
+	}
+
+	// use an alias type to avoid recursing into this method
+	type packageAuthor PackageAuthor
+	return json.Unmarshal(data, (*packageAuthor)(author))
+}
+
 // Structure of package.json as defined by NodeJS
 // Refer https://docs.npmjs.com/cli/v8/configuring-npm/package-json
 // for more details
 type PackageJson struct {
-	Name        string          `json:"name"`
-	Version     string          `json:"version"`
-	Description string          `json:"description"`
-	HomePage    string          `json:"homePage"`
-	Author      PackageAuthor   `json:"author"`
-	License     string          `json:"license"`
-	MainFile    string          `json:"main"`
-	Redefine    *RedefineConfig `json:"redefine"`
+	Name             string             `json:"name"`
+	Version          string             `json:"version"`
+	Description      string             `json:"description"`
+	Keywords         []string           `json:"keywords"`
+	HomePage         string             `json:"homepage"`
+	Bugs             *PackageBugs       `json:"bugs"`
+	License          string             `json:"license"`
+	Author           PackageAuthor      `json:"author"`
+	Contributors     []PackageAuthor    `json:"contributors"`
+	MainFile         string             `json:"main"`
+	Module           string             `json:"module"`  // the ES module entry, used by bundlers
+	Source           string             `json:"source"`  // the source entry, used by some bundlers
+	Types            string             `json:"types"`   // the type declarations
+	Typings          string             `json:"typings"` // the type declarations, as older packages name them
+	Exports          any                `json:"exports"` // a path, or paths by condition or sub-path, kept as written
+	Repository       *PackageRepository `json:"repository"`
+	PeerDependencies map[string]string  `json:"peerDependencies"`
+	Workspaces       PackageWorkspaces  `json:"workspaces"`
+	Redefine         *RedefineConfig    `json:"redefine"`
+
+	unreadFields []string // the fields that could not be read, and are left empty
+}
+
+// Read each field on its own, so that a field written in a form
+// that is not understood does not lose all the others, and is kept
+// in the fields that could not be read. Only fails when the contents
+// are not a JSON object.
+func (packageJson *PackageJson) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(packageJson).Elem()
+	for index := 0; index < value.NumField(); index++ {
+		fieldType := value.Type().Field(index)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		name := getJsonName(fieldType)
+		raw, exists := fields[name]
+		if !exists {
+			continue
+		}
+
+		field := reflect.New(value.Field(index).Type())
+		if json.Unmarshal(raw, field.Interface()) != nil {
+			packageJson.unreadFields = append(packageJson.unreadFields, name)
+			continue
+		}
+
+		value.Field(index).Set(field.Elem())
+	}
+
+	return nil
+}
+
+// Return the type declarations of the package, from `types` or
+// `typings`, or the `types` condition of the main export.
+func (packageJson *PackageJson) getTypesFile() string {
+	if packageJson.Types != "" {
+		return packageJson.Types
+	}
+	if packageJson.Typings != "" {
+		return packageJson.Typings
+	}
+
+	exports := packageJson.Exports
+	if subPaths, ok := exports.(map[string]any); ok && subPaths["."] != nil {
+		exports = subPaths["."]
+	}
+
+	// conditions may be nested, such as `import: { types: ... }`
+	for {
+		conditions, ok := exports.(map[string]any)
+		if !ok {
+			return ""
+		}
+
+		if types, ok := conditions["types"].(string); ok {
+			return types
+		}
+
+		exports = conditions["import"]
+		if exports == nil {
+			exports = conditions["require"]
+		}
+	}
+}
+
+// The `repository` field of package.json which may either be
+// an object, or a string like `github:user/repo` or a URL.
+type PackageRepository struct {
+	Type      string `json:"type"`
+	Url       string `json:"url"`
+	Directory string `json:"directory"`
+}
+
+func (repository *PackageRepository) UnmarshalJSON(data []byte) error {
+	var url string
+	if json.Unmarshal(data, &url) == nil {
+		repository.Url = url
+		return nil
+	}
+
+	// use an alias type to avoid recursing into this method
+	type packageRepository PackageRepository
+	return json.Unmarshal(data, (*packageRepository)(repository))
+}
+
+// The `workspaces` field of package.json, which may either be a
+// list of folder patterns, or an object with the list as `packages`.
+type PackageWorkspaces []string
+
+func (workspaces *PackageWorkspaces) UnmarshalJSON(data []byte) error {
+	var patterns []string
+	if json.Unmarshal(data, &patterns) == nil {
+		*workspaces = patterns
+		return nil
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	err := json.Unmarshal(data, &object)
+	*workspaces = object.Packages
+	return err
+}
+
+// The `bugs` field of package.json which may either be an object,
+// or a string with the URL of the issue tracker.
+type PackageBugs struct {
+	Url   string `json:"url"`
+	Email string `json:"email"`
+}
+
+func (bugs *PackageBugs) UnmarshalJSON(data []byte) error {
+	var url string
+	if json.Unmarshal(data, &url) == nil {
+		bugs.Url = url
+		return nil
+	}
+
+	// use an alias type to avoid recursing into this method
+	type packageBugs PackageBugs
+	return json.Unmarshal(data, (*packageBugs)(bugs))
+}
+
+// Return the link to report issues at, which is the URL of the
+// issue tracker, or else a `mailto:` link.
+func (bugs *PackageBugs) getLink() string {
+	if bugs == nil {
+		return ""
+	}
+	if bugs.Url != "" || bugs.Email == "" {
+		return bugs.Url
+	}
+
+	return "mailto:" + bugs.Email
 }
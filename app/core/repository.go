@@ -0,0 +1,185 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sangupta.com/redefine/model"
+)
+
+// Configuration to generate "view source" links for components
+// and props, pointing to the hosted source code repository.
+type RepositoryConfig struct {
+	Url         string `json:"url"`         // the web URL of the repository, defaults to `repository` in package.json
+	Branch      string `json:"branch"`      // the branch to link to, defaults to `main`
+	Directory   string `json:"directory"`   // the folder of this package within the repository, if not at the root
+	UrlTemplate string `json:"urlTemplate"` // the link template using `{url}`, `{branch}`, `{path}`, `{line}` and `{endLine}`
+}
+
+// Default link templates for well known repository hosts
+var repositoryUrlTemplates = map[string]string{
+	"github.com":    "{url}/blob/{branch}/{path}#L{line}-L{endLine}",
+	"gitlab.com":    "{url}/-/blob/{branch}/{path}#L{line}-{endLine}",
+	"bitbucket.org": "{url}/src/{branch}/{path}#lines-{line}:{endLine}",
+}
+
+// Normalize the repository configuration using the `repository`
+// field of package.json where values are not specified. If no
+// link template can be inferred, no links are generated.
+func normalizeRepositoryConfig(config *RedefineConfig, packageJson *PackageJson) {
+	if config.Repository == nil {
+		config.Repository = &RepositoryConfig{}
+	}
+
+	repository := config.Repository
+	if repository.Url == "" && packageJson.Repository != nil {
+		repository.Url = packageJson.Repository.Url
+		if repository.Directory == "" {
+			repository.Directory = packageJson.Repository.Directory
+		}
+	}
+	repository.Url = getRepositoryWebUrl(repository.Url)
+
+	if repository.Branch == "" {
+		repository.Branch = "main"
+	}
+
+	if repository.UrlTemplate == "" && repository.Url != "" {
+		for host, template := range repositoryUrlTemplates {
+			if strings.Contains(repository.Url, "://"+host+"/") {
+				repository.UrlTemplate = template
+				break
+			}
+		}
+	}
+}
+
+// Convert the repository URL as written in package.json into the
+// web URL of the repository. Handles forms such as `github:user/repo`,
+// `user/repo`, `git+https://github.com/user/repo.git` and
+// `git@github.com:user/repo.git`.
+func getRepositoryWebUrl(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return ""
+	}
+
+	// shorthand forms
+	shorthands := map[string]string{
+		"github:":    "https://github.com/",
+		"gitlab:":    "https://gitlab.com/",
+		"bitbucket:": "https://bitbucket.org/",
+	}
+	for prefix, host := range shorthands {
+		if strings.HasPrefix(url, prefix) {
+			url = host + url[len(prefix):]
+		}
+	}
+	if !strings.Contains(url, ":") && strings.Count(url, "/") == 1 {
+		url = "https://github.com/" + url
+	}
+
+	// scp-like git urls
+	if strings.HasPrefix(url, "git@") {
+		url = "https://" + strings.Replace(url[len("git@"):], ":", "/", 1)
+	}
+
+	url = strings.TrimPrefix(url, "git+")
+	url = strings.Replace(url, "git://", "https://", 1)
+	url = strings.Replace(url, "ssh://git@", "https://", 1)
+	url = strings.TrimSuffix(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+
+	return url
+}
+
+// Generate the "view source" link for the given path, relative to
+// the package folder, and line range. Returns an empty string if no
+// link template is available, or the file is not in the repository,
+// such as the type declarations of packages in `node_modules`.
+func (repository *RepositoryConfig) getSourceUrl(relativePath string, line int, endLine int) string {
+	if repository == nil || repository.UrlTemplate == "" || !isRepositoryPath(relativePath) {
+		return ""
+	}
+
+	filePath := path.Join(repository.Directory, relativePath)
+	replacer := strings.NewReplacer(
+		"{url}", repository.Url,
+		"{branch}", repository.Branch,
+		"{path}", filePath,
+		"{line}", strconv.Itoa(line),
+		"{endLine}", strconv.Itoa(endLine),
+	)
+
+	return replacer.Replace(repository.UrlTemplate)
+}
+
+// Check whether the path, relative to the package folder, is one of
+// the files of the repository. Absolute paths are outside of the
+// package folder, and files in `node_modules` are installed.
+func isRepositoryPath(relativePath string) bool {
+	if relativePath == "" || path.IsAbs(relativePath) || filepath.IsAbs(relativePath) {
+		return false
+	}
+
+	for _, segment := range strings.Split(relativePath, "/") {
+		if segment == ".." || segment == "node_modules" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Convert the absolute source file paths of components and their
+// props to paths relative to the base folder, and generate "view
+// source" links for them.
+func (config *RedefineConfig) setSourceLocations(components []model.Component) {
+	baseFolder, err := filepath.Abs(config.baseFolder)
+	if err != nil {
+		baseFolder = config.baseFolder
+	}
+
+	for index := range components {
+		component := &components[index]
+		component.SourceFile = getRelativePath(baseFolder, component.SourceFile)
+		component.SourceUrl = config.Repository.getSourceUrl(component.SourceFile, component.Line, component.EndLine)
+
+		for _, props := range [][]model.PropDef{component.Props, component.Events} {
+			for propIndex := range props {
+				prop := &props[propIndex]
+				prop.SourceFile = getRelativePath(baseFolder, prop.SourceFile)
+				prop.SourceUrl = config.Repository.getSourceUrl(prop.SourceFile, prop.Line, prop.EndLine)
+			}
+		}
+	}
+}
+
+// Return the path relative to the base folder using forward slashes.
+// Paths outside of the base folder are returned unchanged.
+func getRelativePath(baseFolder string, filePath string) string {
+	if filePath == "" || !filepath.IsAbs(filePath) {
+		return filePath
+	}
+
+	relative, err := filepath.Rel(baseFolder, filePath)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		return filePath
+	}
+
+	return filepath.ToSlash(relative)
+}
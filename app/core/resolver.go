@@ -0,0 +1,413 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The extensions tried, in order, when resolving an import
+var moduleExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx"}
+
+// A Typescript project, which is either the project being documented
+// or one of the projects it references.
+type tsProject struct {
+	folder      string    // the absolute folder of the project
+	tsConfig    *TsConfig // the config of the project, may be `nil`
+	packageName string    // the name from the `package.json` of the project
+	entryFile   string    // the types or main file from the `package.json` of the project
+}
+
+// Resolves imports the way Typescript does, using the `baseUrl`,
+// `paths` and `rootDirs` compiler options of the project containing
+// the importing file. Packages of referenced projects resolve to the
+// project folder, so that monorepo packages can see each other's types.
+type moduleResolver struct {
+	projects     []*tsProject // the root project first, followed by referenced projects
+	resolveTypes bool         // whether packages in `node_modules` resolve to their type declarations
+}
+
+// Create the resolver for the project in the base folder, reading
+// its `tsconfig.json` and those of all projects it references.
+func (config *RedefineConfig) getModuleResolver() *moduleResolver {
+	baseFolder := "."
+	if config != nil {
+		baseFolder = config.baseFolder
+	}
+	baseFolder, _ = filepath.Abs(baseFolder)
+
+	resolver := &moduleResolver{}
+	if config != nil && config.Dependencies != nil {
+		resolver.resolveTypes = config.Dependencies.ResolveTypes
+	}
+
+	resolver.addProject(baseFolder, readTsConfig(baseFolder), make(map[string]bool))
+	return resolver
+}
+
+// Add the project and, recursively, the projects it references.
+func (resolver *moduleResolver) addProject(folder string, tsConfig *TsConfig, seen map[string]bool) {
+	if seen[folder] {
+		return
+	}
+	seen[folder] = true
+
+	project := &tsProject{
+		folder:   folder,
+		tsConfig: tsConfig,
+	}
+	project.readPackageJson()
+	resolver.projects = append(resolver.projects, project)
+
+	if tsConfig == nil {
+		return
+	}
+
+	// references are always relative to the project, even
+	// when they do not start with `.`, unlike `extends`
+	for _, reference := range tsConfig.References {
+		tsConfigPath := resolveTsConfigPath(folder, getAbsolutePath(folder, reference.Path))
+		referenced := readTsConfigFile(tsConfigPath, make(map[string]bool))
+		resolver.addProject(filepath.Dir(tsConfigPath), referenced, seen)
+	}
+}
+
+// Read the package name and entry file of the project
+func (project *tsProject) readPackageJson() {
+	contents, err := os.ReadFile(filepath.Join(project.folder, "package.json"))
+	if err != nil {
+		return
+	}
+
+	entry := PackageJson{}
+	if json.Unmarshal(contents, &entry) != nil {
+		return
+	}
+
+	project.packageName = entry.Name
+	for _, file := range []string{entry.getTypesFile(), entry.Source, entry.MainFile} {
+		if file != "" {
+			project.entryFile = filepath.Join(project.folder, file)
+			break
+		}
+	}
+}
+
+// Resolve the module specifier of an import in the given file to the
+// absolute path of the file it refers to. Returns an empty string if
+// the module cannot be resolved.
+func (resolver *moduleResolver) resolve(fromFile string, specifier string) string {
+	project := resolver.getProject(fromFile)
+
+	// relative imports like `./Button` or `../types`
+	if strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") || specifier == "." || specifier == ".." {
+		target := filepath.Join(filepath.Dir(fromFile), specifier)
+		if file := resolveModuleFile(target); file != "" {
+			return file
+		}
+
+		return project.resolveInRootDirs(target)
+	}
+
+	// aliases like `@/types/button` via `paths` and `baseUrl`
+	if file := project.resolveNonRelative(specifier); file != "" {
+		return file
+	}
+
+	// packages of the referenced projects, like `@acme/ui-core`
+	for _, referenced := range resolver.projects {
+		if file := referenced.resolvePackage(specifier); file != "" {
+			return file
+		}
+	}
+
+	// type declarations of dependencies, like `react`
+	if resolver.resolveTypes {
+		return resolvePackageTypes(filepath.Dir(fromFile), specifier)
+	}
+
+	return ""
+}
+
+// Resolve the type declarations of a package in `node_modules`, looking
+// in the package itself and then in its `@types` package, in the folder
+// and all its parents. Returns an empty string if none are found.
+func resolvePackageTypes(folder string, specifier string) string {
+	name, subPath := splitPackageSpecifier(specifier)
+	if name == "" {
+		return ""
+	}
+
+	// `@scope/name` is typed by `@types/scope__name`
+	typesName := "@types/" + strings.Replace(strings.TrimPrefix(name, "@"), "/", "__", 1)
+
+	for current := folder; ; current = filepath.Dir(current) {
+		for _, packageName := range []string{name, typesName} {
+			packageFolder := filepath.Join(current, "node_modules", packageName)
+			if info, err := os.Stat(packageFolder); err != nil || !info.IsDir() {
+				continue
+			}
+
+			if file := resolvePackageDeclarations(packageFolder, subPath); file != "" {
+				return file
+			}
+		}
+
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return ""
+}
+
+// Find the declaration file within the package folder, either for
+// the given path within the package or for the `types` entry of
+// the package itself.
+func resolvePackageDeclarations(packageFolder string, subPath string) string {
+	if subPath != "" {
+		return resolveDeclarationFile(filepath.Join(packageFolder, subPath))
+	}
+
+	contents, err := os.ReadFile(filepath.Join(packageFolder, "package.json"))
+	if err == nil {
+		entry := PackageJson{}
+		if json.Unmarshal(contents, &entry) == nil {
+			if file := entry.getTypesFile(); file != "" {
+				if declarations := resolveDeclarationFile(filepath.Join(packageFolder, file)); declarations != "" {
+					return declarations
+				}
+			}
+		}
+	}
+
+	return resolveDeclarationFile(filepath.Join(packageFolder, "index"))
+}
+
+// Find the `.d.ts` file for the module path, which may name the
+// declaration file itself, the compiled `.js` file, or a folder
+// with an `index.d.ts` file.
+func resolveDeclarationFile(modulePath string) string {
+	candidates := []string{
+		modulePath,
+		modulePath + ".d.ts",
+		strings.TrimSuffix(modulePath, filepath.Ext(modulePath)) + ".d.ts",
+		filepath.Join(modulePath, "index.d.ts"),
+	}
+
+	for _, candidate := range candidates {
+		if !strings.HasSuffix(candidate, ".d.ts") {
+			continue
+		}
+
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+// Split the specifier into the package name and the path within
+// the package, such as `@scope/name` and `sub/path` for
+// `@scope/name/sub/path`.
+func splitPackageSpecifier(specifier string) (string, string) {
+	name, subPath, _ := strings.Cut(specifier, "/")
+	if !strings.HasPrefix(name, "@") {
+		return name, subPath
+	}
+
+	// scoped packages have two parts to the name
+	scopedName, scopedSubPath, _ := strings.Cut(subPath, "/")
+	if scopedName == "" {
+		return "", ""
+	}
+
+	return name + "/" + scopedName, scopedSubPath
+}
+
+// Return the project containing the file, which is the project with
+// the deepest folder containing it, or the root project otherwise.
+func (resolver *moduleResolver) getProject(file string) *tsProject {
+	found := resolver.projects[0]
+	for _, project := range resolver.projects[1:] {
+		if isWithinFolder(project.folder, file) && len(project.folder) > len(found.folder) {
+			found = project
+		}
+	}
+
+	return found
+}
+
+// Resolve a non-relative import using the `paths` and `baseUrl`
+// compiler options of the project.
+func (project *tsProject) resolveNonRelative(specifier string) string {
+	if project.tsConfig == nil || project.tsConfig.CompilerOptions == nil {
+		return ""
+	}
+
+	options := project.tsConfig.CompilerOptions
+	if len(options.Paths) > 0 {
+		for _, target := range matchTsPaths(options.Paths, specifier) {
+			if file := resolveModuleFile(filepath.Join(project.tsConfig.pathsBase, target)); file != "" {
+				return file
+			}
+		}
+	}
+
+	if options.BaseUrl != "" {
+		return resolveModuleFile(filepath.Join(options.BaseUrl, specifier))
+	}
+
+	return ""
+}
+
+// Resolve an import that failed to resolve relative to the importing
+// file against the other `rootDirs`, which Typescript treats as if
+// their contents were merged into a single folder.
+func (project *tsProject) resolveInRootDirs(target string) string {
+	if project.tsConfig == nil || project.tsConfig.CompilerOptions == nil {
+		return ""
+	}
+
+	rootDirs := project.tsConfig.CompilerOptions.RootDirs
+	for _, rootDir := range rootDirs {
+		if !isWithinFolder(rootDir, target) {
+			continue
+		}
+
+		relative, _ := filepath.Rel(rootDir, target)
+		for _, otherRootDir := range rootDirs {
+			if otherRootDir == rootDir {
+				continue
+			}
+
+			if file := resolveModuleFile(filepath.Join(otherRootDir, relative)); file != "" {
+				return file
+			}
+		}
+	}
+
+	return ""
+}
+
+// Resolve an import of this project's package, either the package
+// itself such as `@acme/ui-core`, or a path within it.
+func (project *tsProject) resolvePackage(specifier string) string {
+	name := project.packageName
+	if name == "" || (specifier != name && !strings.HasPrefix(specifier, name+"/")) {
+		return ""
+	}
+
+	subPath := strings.TrimPrefix(specifier[len(name):], "/")
+	if subPath == "" {
+		if project.entryFile != "" {
+			if file := resolveModuleFile(project.entryFile); file != "" {
+				return file
+			}
+		}
+
+		subPath = "index"
+	}
+
+	// the package may be imported by its source, or by its build output
+	for _, folder := range []string{"", "src"} {
+		if file := resolveModuleFile(filepath.Join(project.folder, folder, subPath)); file != "" {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// Return the targets of the `paths` entry that best matches the
+// specifier, with the wildcard substituted. An exact pattern wins
+// over wildcard patterns, and of wildcard patterns the one with the
+// longest prefix wins, as in Typescript.
+func matchTsPaths(paths map[string][]string, specifier string) []string {
+	if targets, exists := paths[specifier]; exists {
+		return targets
+	}
+
+	bestPattern := ""
+	bestMatch := ""
+	for pattern := range paths {
+		star := strings.Index(pattern, "*")
+		if star < 0 {
+			continue
+		}
+
+		prefix, suffix := pattern[:star], pattern[star+1:]
+		if len(specifier) < len(prefix)+len(suffix) || !strings.HasPrefix(specifier, prefix) || !strings.HasSuffix(specifier, suffix) {
+			continue
+		}
+
+		if bestPattern == "" || len(prefix) > strings.Index(bestPattern, "*") {
+			bestPattern = pattern
+			bestMatch = specifier[len(prefix) : len(specifier)-len(suffix)]
+		}
+	}
+
+	if bestPattern == "" {
+		return nil
+	}
+
+	targets := make([]string, 0, len(paths[bestPattern]))
+	for _, target := range paths[bestPattern] {
+		targets = append(targets, strings.Replace(target, "*", bestMatch, 1))
+	}
+
+	return targets
+}
+
+// Find the file for the module path, trying the known extensions
+// and `index` files in folders. Imports may also use the `.js`
+// extension of the compiled file, such as `./Button.js` for the
+// source `./Button.tsx`. Returns an empty string if none exists.
+func resolveModuleFile(modulePath string) string {
+	if info, err := os.Stat(modulePath); err == nil && !info.IsDir() {
+		return modulePath
+	}
+
+	candidates := []string{modulePath}
+	extension := filepath.Ext(modulePath)
+	if extension == ".js" || extension == ".jsx" {
+		candidates = append(candidates, strings.TrimSuffix(modulePath, extension))
+	}
+
+	for _, candidate := range candidates {
+		for _, extension := range moduleExtensions {
+			if info, err := os.Stat(candidate + extension); err == nil && !info.IsDir() {
+				return candidate + extension
+			}
+		}
+	}
+
+	for _, extension := range moduleExtensions {
+		indexFile := filepath.Join(modulePath, "index"+extension)
+		if info, err := os.Stat(indexFile); err == nil && !info.IsDir() {
+			return indexFile
+		}
+	}
+
+	return ""
+}
+
+// Check if the path is the folder itself or within the folder
+func isWithinFolder(folder string, path string) bool {
+	relative, err := filepath.Rel(folder, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,137 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The project in `testdata/resolver` has:
+//   - a `tsconfig.json` with `rootDirs` and a reference to `packages/core`,
+//     extending `configs/base.json`
+//   - `configs/base.json` with the `baseUrl`, extending the `@acme/tsconfig`
+//     package and `configs/paths.json`
+//   - `configs/paths.json` with the `target` and `paths`
+func TestTsConfigExtends(t *testing.T) {
+	folder, _ := filepath.Abs(filepath.Join("testdata", "resolver"))
+	tsConfig := readTsConfig(folder)
+	assert.NotNil(t, tsConfig)
+
+	// later configs in `extends` take precedence over earlier ones,
+	// and paths are relative to the config declaring them
+	options := tsConfig.CompilerOptions
+	assert.Equal(t, "es2019", options.Target)
+	assert.Equal(t, folder, options.BaseUrl)
+	assert.Equal(t, []string{filepath.Join(folder, "src"), filepath.Join(folder, "generated")}, options.RootDirs)
+	assert.Equal(t, []string{"src/*"}, options.Paths["@/*"])
+	assert.Equal(t, folder, tsConfig.pathsBase)
+	assert.Equal(t, []TsProjectReference{{Path: "packages/core"}}, tsConfig.References)
+}
+
+func TestTsConfigAbsolutePaths(t *testing.T) {
+	folder := t.TempDir()
+	shared := t.TempDir()
+	writeTestFiles(t, shared, map[string]string{
+		"tsconfig.base.json": `{ "compilerOptions": { "target": "es2020", "baseUrl": "` + filepath.ToSlash(filepath.Join(folder, "lib")) + `" } }`,
+	})
+	writeTestFiles(t, folder, map[string]string{
+		"tsconfig.json": `{ "extends": "` + filepath.ToSlash(filepath.Join(shared, "tsconfig.base.json")) + `" }`,
+	})
+
+	// absolute paths are used as they are, not within the folder
+	assert.Equal(t, filepath.Join(shared, "tsconfig.base.json"), resolveTsConfigPath(folder, filepath.Join(shared, "tsconfig.base.json")))
+
+	tsConfig := readTsConfig(folder)
+	assert.Equal(t, "es2020", tsConfig.CompilerOptions.Target)
+	assert.Equal(t, filepath.Join(folder, "lib"), tsConfig.CompilerOptions.BaseUrl)
+}
+
+func TestResolveTsConfigPath(t *testing.T) {
+	folder, _ := filepath.Abs(filepath.Join("testdata", "resolver"))
+
+	tests := []struct {
+		name     string
+		from     string
+		expected string
+	}{
+		{"relative file", "./configs/base.json", "configs/base.json"},
+		{"relative file without extension", "./configs/paths", "configs/paths.json"},
+		{"relative folder", "./packages/core", "packages/core/tsconfig.json"},
+		{"package", "@acme/tsconfig", "node_modules/@acme/tsconfig/tsconfig.json"},
+		{"file within a package", "@acme/tsconfig/tsconfig.json", "node_modules/@acme/tsconfig/tsconfig.json"},
+		{"absolute file", filepath.Join(folder, "configs", "paths.json"), "configs/paths.json"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, filepath.Join(folder, test.expected), resolveTsConfigPath(folder, test.from))
+		})
+	}
+}
+
+func TestModuleResolver(t *testing.T) {
+	folder, _ := filepath.Abs(filepath.Join("testdata", "resolver"))
+	config := &RedefineConfig{baseFolder: folder}
+	resolver := config.getModuleResolver()
+
+	// the root project, followed by the one it references
+	assert.Equal(t, 2, len(resolver.projects))
+	assert.Equal(t, "@acme/core", resolver.projects[1].packageName)
+
+	tests := []struct {
+		name      string
+		from      string
+		specifier string
+		expected  string
+	}{
+		{"relative", "src/App.tsx", "./components/Button", "src/components/Button.tsx"},
+		{"relative folder", "src/App.tsx", "./components", "src/components/index.ts"},
+		{"compiled extension", "src/App.tsx", "./utils.js", "src/utils.ts"},
+		{"paths wildcard", "src/App.tsx", "@/utils", "src/utils.ts"},
+		{"paths exact", "src/App.tsx", "@components", "src/components/index.ts"},
+		{"baseUrl", "src/App.tsx", "lib/helpers", "lib/helpers.ts"},
+		{"rootDirs", "src/App.tsx", "./types", "generated/types.ts"},
+		{"referenced package", "src/App.tsx", "@acme/core", "packages/core/src/index.ts"},
+		{"within referenced package", "src/App.tsx", "@acme/core/theme", "packages/core/src/theme.ts"},
+		{"baseUrl of referenced project", "packages/core/src/index.ts", "theme", "packages/core/src/theme.ts"},
+		{"baseUrl of another project", "packages/core/src/index.ts", "lib/helpers", ""},
+		{"dependency", "src/App.tsx", "react", ""},
+		{"missing", "src/App.tsx", "./missing", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := test.expected
+			if expected != "" {
+				expected = filepath.Join(folder, expected)
+			}
+
+			assert.Equal(t, expected, resolver.resolve(filepath.Join(folder, test.from), test.specifier))
+		})
+	}
+}
+
+func TestModuleResolverWithoutTsConfig(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"src/App.tsx":    "",
+		"src/Button.tsx": "",
+	})
+
+	resolver := (&RedefineConfig{baseFolder: folder}).getModuleResolver()
+	assert.Equal(t, filepath.Join(folder, "src", "Button.tsx"), resolver.resolve(filepath.Join(folder, "src", "App.tsx"), "./Button"))
+	assert.Equal(t, "", resolver.resolve(filepath.Join(folder, "src", "App.tsx"), "Button"))
+}
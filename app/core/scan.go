@@ -0,0 +1,329 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	ast "sangupta.com/redefine/ast"
+)
+
+// The files excluded from scanning when no excludes are configured
+var defaultSourceExcludes = []string{
+	"node_modules",
+	"**/*.test.*",
+	"**/*.spec.*",
+	"**/*.stories.*",
+	"**/*.d.ts",
+}
+
+// A single rule read from a `.gitignore` file
+type gitIgnoreRule struct {
+	base    string // the absolute folder containing the `.gitignore` file, with forward slashes
+	pattern string // the pattern relative to the base folder
+	negate  bool   // whether the rule re-includes matching paths, `!pattern`
+	dirOnly bool   // whether the rule only matches folders, `pattern/`
+}
+
+// Keeps state when scanning the source roots for files
+type folderScanner struct {
+	baseFolder     string // the absolute project folder, whose `.gitignore` is read upfront
+	includes       []string
+	excludes       []string
+	useGitIgnore   bool
+	followSymlinks bool
+	gitIgnore      []gitIgnoreRule
+	visited        map[string]bool // real paths of folders already scanned, to avoid symlink cycles
+	seen           map[string]bool // real paths of files already added
+	files          []string
+	diagnostics    []ast.Diagnostic
+}
+
+// Scan all source roots for files that match the include patterns
+// and do not match the exclude patterns or `.gitignore` rules.
+// Returns an array of absolute paths. Folders that cannot be read
+// are skipped and reported as diagnostics.
+func (config *RedefineConfig) scanFolder() ([]string, []ast.Diagnostic) {
+	folder := config.SrcFolder
+	baseFolder, _ := filepath.Abs(config.baseFolder)
+	scanner := &folderScanner{
+		baseFolder:     baseFolder,
+		includes:       folder.Includes,
+		excludes:       folder.Excludes,
+		useGitIgnore:   folder.GitIgnore == nil || *folder.GitIgnore,
+		followSymlinks: folder.FollowSymlinks,
+		visited:        make(map[string]bool),
+		seen:           make(map[string]bool),
+	}
+
+	// rules from the project `.gitignore` apply to all roots
+	if scanner.useGitIgnore {
+		scanner.gitIgnore = readGitIgnore(baseFolder)
+	}
+
+	for _, root := range folder.GetRoots() {
+		scanner.scanRoot(root)
+	}
+
+	return scanner.files, scanner.diagnostics
+}
+
+// Scan a single source root. Patterns are matched against paths
+// relative to the root.
+func (scanner *folderScanner) scanRoot(root string) {
+	info, err := os.Stat(root)
+	if err != nil {
+		scanner.addWarning(root, err)
+		return
+	}
+
+	if !info.IsDir() {
+		scanner.addWarning(root, fs.ErrInvalid)
+		return
+	}
+
+	scanner.walk(root, root, scanner.gitIgnore)
+}
+
+// Walk the given folder, which is either the root itself or a
+// folder reached via a symbolic link from within the root.
+func (scanner *folderScanner) walk(root string, folder string, gitIgnore []gitIgnoreRule) {
+	realPath, err := filepath.EvalSymlinks(folder)
+	if err != nil {
+		scanner.addWarning(folder, err)
+		return
+	}
+
+	if scanner.visited[realPath] {
+		return
+	}
+	scanner.visited[realPath] = true
+
+	// rules of nested `.gitignore` files apply to their own folder only
+	if scanner.useGitIgnore && realPath != scanner.baseFolder {
+		gitIgnore = append(append([]gitIgnoreRule{}, gitIgnore...), readGitIgnore(folder)...)
+	}
+
+	rules := make(map[string][]gitIgnoreRule)
+	rules[folder] = gitIgnore
+
+	// walk the resolved folder, as symbolic links are not followed,
+	// but report paths as within the linked folder
+	filepath.WalkDir(realPath, func(path string, entry fs.DirEntry, err error) error {
+		if relative, err := filepath.Rel(realPath, path); err == nil {
+			path = filepath.Join(folder, relative)
+		}
+
+		if err != nil {
+			scanner.addWarning(path, err)
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// the rules for the folder itself are already known
+		if path == folder {
+			return nil
+		}
+
+		parentRules := rules[filepath.Dir(path)]
+
+		relativePath, _ := filepath.Rel(root, path)
+		relativePath = filepath.ToSlash(relativePath)
+
+		isDir := entry.IsDir()
+		isSymlink := entry.Type()&fs.ModeSymlink != 0
+
+		// resolve what symbolic links point to
+		if isSymlink {
+			info, err := os.Stat(path)
+			if err != nil {
+				scanner.addWarning(path, err)
+				return nil
+			}
+
+			isDir = info.IsDir()
+		}
+
+		if scanner.isExcluded(relativePath) || isGitIgnored(parentRules, path, isDir) {
+			if isDir && !isSymlink {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if isDir {
+			if isSymlink {
+				if scanner.followSymlinks {
+					scanner.walk(root, path, parentRules)
+				}
+
+				return nil
+			}
+
+			folderRules := parentRules
+			if scanner.useGitIgnore {
+				folderRules = append(append([]gitIgnoreRule{}, parentRules...), readGitIgnore(path)...)
+			}
+			rules[path] = folderRules
+			return nil
+		}
+
+		if !scanner.isIncluded(relativePath) {
+			return nil
+		}
+
+		scanner.addFile(path)
+		return nil
+	})
+}
+
+// Add the file to the list of files to be parsed, unless the same
+// file has already been added via another root or a symbolic link.
+func (scanner *folderScanner) addFile(path string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		scanner.addWarning(path, err)
+		return
+	}
+
+	realPath, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		scanner.addWarning(path, err)
+		return
+	}
+
+	if scanner.seen[realPath] {
+		return
+	}
+
+	scanner.seen[realPath] = true
+	scanner.files = append(scanner.files, absPath)
+}
+
+func (scanner *folderScanner) addWarning(path string, err error) {
+	scanner.diagnostics = append(scanner.diagnostics, ast.Diagnostic{
+		File:     path,
+		Severity: ast.SEVERITY_WARNING,
+		Message:  "unable to scan: " + err.Error(),
+	})
+}
+
+func (scanner *folderScanner) isIncluded(relativePath string) bool {
+	return matchesAnyPattern(relativePath, scanner.includes)
+}
+
+func (scanner *folderScanner) isExcluded(relativePath string) bool {
+	return matchesAnyPattern(relativePath, scanner.excludes)
+}
+
+// Check if the path, relative to the source root, matches any of
+// the given glob patterns. Patterns without a slash, such as `*.tsx`
+// or `node_modules`, match the file or folder name at any depth.
+func matchesAnyPattern(relativePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+
+		if matched, _ := doublestar.Match(pattern, relativePath); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Read the rules of the `.gitignore` file in the given folder,
+// if one exists.
+func readGitIgnore(folder string) []gitIgnoreRule {
+	file, err := os.Open(filepath.Join(folder, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	base, _ := filepath.Abs(folder)
+	base = filepath.ToSlash(base)
+
+	var rules []gitIgnoreRule
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := gitIgnoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		// patterns with a slash are relative to the `.gitignore` folder,
+		// others match a name at any depth
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// Check if the given path is ignored by the rules. As in git, the
+// last matching rule decides, so that negated rules can re-include
+// paths excluded by earlier rules.
+func isGitIgnored(rules []gitIgnoreRule, path string, isDir bool) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absPath = filepath.ToSlash(absPath)
+
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if !strings.HasPrefix(absPath, rule.base+"/") {
+			continue
+		}
+
+		relativePath := absPath[len(rule.base)+1:]
+		if matched, _ := doublestar.Match(rule.pattern, relativePath); matched {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
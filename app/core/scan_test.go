@@ -0,0 +1,167 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write the files, keyed by their path relative to the folder,
+// creating the folders they are in.
+func writeTestFiles(t *testing.T, folder string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(folder, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+}
+
+// Scan the source folder of the project, with the given overrides
+// of its configuration, and return the files found relative to the
+// project folder, sorted.
+func scanTestFolder(t *testing.T, folder string, overrides ...string) []string {
+	config, diagnostics := GetRedefineConfig(ConfigOptions{BaseFolder: folder, Overrides: overrides})
+	assert.Equal(t, 0, len(diagnostics))
+
+	files, diagnostics := config.scanFolder()
+	assert.Equal(t, 0, len(diagnostics))
+
+	relativeFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		relative, err := filepath.Rel(folder, file)
+		assert.Nil(t, err)
+		relativeFiles = append(relativeFiles, filepath.ToSlash(relative))
+	}
+
+	sort.Strings(relativeFiles)
+	return relativeFiles
+}
+
+func TestScanDefaultExcludes(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"package.json":                    `{ "name": "acme" }`,
+		"src/Button.tsx":                  "",
+		"src/Button.test.tsx":             "",
+		"src/Button.spec.tsx":             "",
+		"src/Button.stories.tsx":          "",
+		"src/Button.css":                  "",
+		"src/types.d.ts":                  "",
+		"src/menu/Menu.jsx":               "",
+		"src/menu/node_modules/x/x.ts":    "",
+		"src/menu/__tests__/Menu.test.js": "",
+	})
+
+	assert.Equal(t, []string{"src/Button.tsx", "src/menu/Menu.jsx"}, scanTestFolder(t, folder))
+
+	// configured excludes replace the defaults
+	assert.Equal(t, []string{
+		"src/Button.spec.tsx",
+		"src/Button.stories.tsx",
+		"src/Button.tsx",
+		"src/menu/Menu.jsx",
+		"src/menu/__tests__/Menu.test.js",
+		"src/menu/node_modules/x/x.ts",
+		"src/types.d.ts",
+	}, scanTestFolder(t, folder, "src.excludes=*.test.tsx"))
+}
+
+func TestScanGitIgnore(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"package.json":               `{ "name": "acme" }`,
+		".gitignore":                 "# generated files\ngenerated/\n*.gen.tsx\n",
+		"src/Button.tsx":             "",
+		"src/Button.gen.tsx":         "",
+		"src/generated/Icon.tsx":     "",
+		"src/menu/.gitignore":        "Secret.tsx\n/local/\n",
+		"src/menu/Menu.tsx":          "",
+		"src/menu/Secret.tsx":        "",
+		"src/menu/items/Secret.tsx":  "",
+		"src/menu/local/Item.tsx":    "",
+		"src/menu/items/local/X.tsx": "",
+		"src/form/Secret.tsx":        "",
+	})
+
+	// rules of nested files only apply within their own folder,
+	// and patterns with a slash are relative to that folder
+	assert.Equal(t, []string{
+		"src/Button.tsx",
+		"src/form/Secret.tsx",
+		"src/menu/Menu.tsx",
+		"src/menu/items/local/X.tsx",
+	}, scanTestFolder(t, folder))
+
+	// all files are scanned when `.gitignore` files are not used
+	assert.Equal(t, 9, len(scanTestFolder(t, folder, "src.gitignore=false")))
+}
+
+func TestScanGitIgnoreNegation(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"package.json":           `{ "name": "acme" }`,
+		".gitignore":             "*.tsx\n!Keep*.tsx\n",
+		"src/Button.tsx":         "",
+		"src/KeepButton.tsx":     "",
+		"src/menu/.gitignore":    "!Menu.tsx\nKeepOut.tsx\n",
+		"src/menu/Menu.tsx":      "",
+		"src/menu/Item.tsx":      "",
+		"src/menu/KeepOut.tsx":   "",
+		"src/menu/KeepIn.tsx":    "",
+		"src/utils/index.ts":     "",
+		"src/utils/KeepThis.tsx": "",
+	})
+
+	// the last rule that matches decides, including the rules
+	// of nested files over those of the folders above
+	assert.Equal(t, []string{
+		"src/KeepButton.tsx",
+		"src/menu/KeepIn.tsx",
+		"src/menu/Menu.tsx",
+		"src/utils/KeepThis.tsx",
+		"src/utils/index.ts",
+	}, scanTestFolder(t, folder))
+}
+
+func TestScanSymlinkCycles(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"package.json":          `{ "name": "acme" }`,
+		"src/Button.tsx":        "",
+		"src/menu/Menu.tsx":     "",
+		"shared/icons/Icon.tsx": "",
+	})
+
+	// links back to the folders above, and to a folder outside
+	// of the source root
+	assert.Nil(t, os.Symlink(filepath.Join(folder, "src"), filepath.Join(folder, "src", "menu", "root")))
+	assert.Nil(t, os.Symlink("..", filepath.Join(folder, "src", "menu", "parent")))
+	assert.Nil(t, os.Symlink(filepath.Join(folder, "shared"), filepath.Join(folder, "src", "shared")))
+	assert.Nil(t, os.Symlink(filepath.Join(folder, "src", "shared"), filepath.Join(folder, "shared", "icons", "back")))
+
+	// linked folders are not scanned by default
+	assert.Equal(t, []string{"src/Button.tsx", "src/menu/Menu.tsx"}, scanTestFolder(t, folder))
+
+	// each folder is scanned once when links are followed,
+	// even when linked from within itself
+	assert.Equal(t, []string{
+		"src/Button.tsx",
+		"src/menu/Menu.tsx",
+		"src/shared/icons/Icon.tsx",
+	}, scanTestFolder(t, folder, "src.followSymlinks=true"))
+}
@@ -0,0 +1,205 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"sangupta.com/redefine/model"
+)
+
+// The version of the format of `components.json`, which is increased
+// whenever a change to the format would break existing consumers
+const COMPONENTS_SCHEMA_VERSION = 2
+
+// The file names the schemas are written to by `build`, and served at
+// by `serve`, next to `components.json`
+const (
+	COMPONENTS_SCHEMA_FILE = "components.schema.json"
+	CONFIG_SCHEMA_FILE     = "redefine.config.schema.json"
+)
+
+// The JSON Schema draft the schemas are written in
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// A JSON Schema, limited to the keywords needed to describe the
+// Go types that are read from or written to JSON.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 any                    `json:"type,omitempty"` // a single type, or a list of types
+	Enum                 []any                  `json:"enum,omitempty"`
+	Const                any                    `json:"const,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"` // `false`, or the schema of the values of a map
+	Items                *jsonSchema            `json:"items,omitempty"`
+	OneOf                []*jsonSchema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// Schemas of types that are not described by their Go type alone
+var knownSchemas = map[reflect.Type]*jsonSchema{
+	reflect.TypeOf(model.ComponentType(0)): {
+		Type: "string",
+		Enum: []any{model.REACT_CLASS_COMPONENT, model.REACT_FUNCTION_COMPONENT},
+	},
+}
+
+// Builds the schema of a Go type, along with the definitions of
+// all the struct types it refers to.
+type schemaBuilder struct {
+	defs   map[string]*jsonSchema
+	strict bool // whether objects may not have keys other than the known ones
+}
+
+// Return the JSON Schema of the configuration, as read from the
+// `redefine.config.json` file or the `redefine` key of `package.json`.
+func GetConfigSchema() ([]byte, error) {
+	builder := &schemaBuilder{defs: map[string]*jsonSchema{}, strict: true}
+	schema := builder.buildStruct(reflect.TypeOf(RedefineConfig{}))
+
+	schema.Schema = jsonSchemaDraft
+	schema.Title = "Redefine configuration"
+	schema.Description = "Configuration read from `redefine.config.json`, or the `redefine` key of `package.json`"
+	schema.Defs = builder.defs
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// Return the JSON Schema of the `components.json` file, which allows
+// keys unknown to this version so that consumers keep working when
+// keys are added.
+func GetComponentsSchema() ([]byte, error) {
+	builder := &schemaBuilder{defs: map[string]*jsonSchema{}}
+	schema := builder.buildStruct(reflect.TypeOf(jsonPayload{}))
+
+	schema.Schema = jsonSchemaDraft
+	schema.Title = "Redefine components"
+	schema.Description = "The components extracted by redefine, along with the details of the library"
+	schema.Properties["schemaVersion"].Const = COMPONENTS_SCHEMA_VERSION
+	schema.Defs = builder.defs
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// Return the schema of the type. Struct types are added to the
+// definitions, and referred to by their name, which also allows
+// types that contain themselves.
+func (builder *schemaBuilder) build(valueType reflect.Type) *jsonSchema {
+	// `null` is accepted, and written, for pointers
+	nullable := false
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+		nullable = true
+	}
+
+	if schema, exists := knownSchemas[valueType]; exists {
+		copied := *schema
+		return &copied
+	}
+
+	var schema *jsonSchema
+	switch valueType.Kind() {
+	case reflect.Struct:
+		return builder.ref(valueType, nullable)
+
+	case reflect.Map:
+		return &jsonSchema{
+			Type:                 []string{"object", "null"},
+			AdditionalProperties: builder.build(valueType.Elem()),
+		}
+
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{
+			Type:  []string{"array", "null"},
+			Items: builder.build(valueType.Elem()),
+		}
+
+	case reflect.String:
+		schema = &jsonSchema{Type: "string"}
+
+	case reflect.Bool:
+		schema = &jsonSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema = &jsonSchema{Type: "integer"}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := 0
+		schema = &jsonSchema{Type: "integer", Minimum: &minimum}
+
+	case reflect.Float32, reflect.Float64:
+		schema = &jsonSchema{Type: "number"}
+
+	default:
+		// interfaces hold values of any shape
+		return &jsonSchema{}
+	}
+
+	if nullable {
+		schema.Type = []any{schema.Type, "null"}
+	}
+
+	return schema
+}
+
+// Return a reference to the definition of the struct type, adding
+// the definition if it is not yet known.
+func (builder *schemaBuilder) ref(structType reflect.Type, nullable bool) *jsonSchema {
+	name := structType.Name()
+	if _, exists := builder.defs[name]; !exists {
+		// reserve the name before building, for types that contain themselves
+		builder.defs[name] = nil
+		builder.defs[name] = builder.buildStruct(structType)
+	}
+
+	ref := &jsonSchema{Ref: "#/$defs/" + name}
+	if !nullable {
+		return ref
+	}
+
+	return &jsonSchema{OneOf: []*jsonSchema{ref, {Type: "null"}}}
+}
+
+// Return the schema of the struct type, with a property for every
+// field that is read from or written to JSON.
+func (builder *schemaBuilder) buildStruct(structType reflect.Type) *jsonSchema {
+	// types that decode themselves accept values of any shape
+	if reflect.PointerTo(structType).Implements(jsonUnmarshalerType) {
+		return &jsonSchema{}
+	}
+
+	schema := &jsonSchema{
+		Type:       "object",
+		Properties: make(map[string]*jsonSchema, structType.NumField()),
+	}
+
+	if builder.strict {
+		schema.AdditionalProperties = false
+	}
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		schema.Properties[getJsonName(field)] = builder.build(field.Type)
+	}
+
+	return schema
+}
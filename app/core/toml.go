@@ -0,0 +1,94 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// This file contains the reader for configuration files written in
+// TOML. The document is decoded by the TOML decoder, which reports
+// where syntax errors are found, but not where values are written.
+// Keys are read in sorted order, and dates and times are reported as
+// errors.
+
+// Read the TOML contents into a tree of values.
+func parseTomlConfig(contents []byte) (*configNode, error) {
+	var values map[string]any
+	if err := toml.Unmarshal(contents, &values); err != nil {
+		return nil, newTomlError(err)
+	}
+
+	return convertTomlValue(values, "")
+}
+
+// Convert an error of the TOML decoder, keeping the position where
+// it was found when it is known.
+func newTomlError(err error) error {
+	var position filePosition
+
+	var decodeError *toml.DecodeError
+	if errors.As(err, &decodeError) {
+		position.line, position.column = decodeError.Position()
+	}
+
+	return &configSyntaxError{position, "invalid TOML: " + strings.TrimPrefix(err.Error(), "toml: ")}
+}
+
+// Convert a value decoded from TOML to a tree of values. The path
+// is the dotted path of keys leading to the value, for errors.
+func convertTomlValue(value any, path string) (*configNode, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		node := &configNode{kind: configObject}
+		for _, key := range keys {
+			converted, err := convertTomlValue(value[key], joinConfigPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+
+			node.fields = append(node.fields, &configField{key: key, value: converted})
+		}
+
+		return node, nil
+
+	case []any:
+		node := &configNode{kind: configArray}
+		for _, item := range value {
+			converted, err := convertTomlValue(item, path)
+			if err != nil {
+				return nil, err
+			}
+
+			node.items = append(node.items, converted)
+		}
+
+		return node, nil
+
+	case toml.LocalDate, toml.LocalTime, toml.LocalDateTime, time.Time:
+		return nil, &configSyntaxError{message: "invalid TOML: `" + path + "` is a date or time, which cannot be used in the configuration"}
+	}
+
+	return &configNode{kind: configScalar, scalar: value}, nil
+}
@@ -0,0 +1,101 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTomlConfig(t *testing.T) {
+	root, err := parseTomlConfig([]byte(`# the title
+title = "Acme é"
+limits.memory = 0x100
+ratio = 1_000.5
+
+[src]
+root = 'lib'
+includes = [
+	"**/*.tsx", # components
+	"**/*.jsx",
+]
+description = """
+Components of Acme"""
+
+[[links]]
+name = "Home"
+options = { external = true, order = 1 }
+
+[[links]]
+name = "Docs"
+`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Acme é", root.getField("title").scalar)
+	assert.Equal(t, int64(256), root.getField("limits").getField("memory").scalar)
+	assert.Equal(t, 1000.5, root.getField("ratio").scalar)
+
+	// fields are sorted by key, as the order they are written in is not known
+	keys := []string{}
+	for _, field := range root.fields {
+		keys = append(keys, field.key)
+	}
+	assert.Equal(t, []string{"limits", "links", "ratio", "src", "title"}, keys)
+
+	src := root.getField("src")
+	assert.Equal(t, "lib", src.getField("root").scalar)
+	assert.Equal(t, "Components of Acme", src.getField("description").scalar)
+	assert.Equal(t, configArray, src.getField("includes").kind)
+	assert.Equal(t, 2, len(src.getField("includes").items))
+	assert.Equal(t, "**/*.jsx", src.getField("includes").items[1].scalar)
+
+	links := root.getField("links")
+	assert.Equal(t, 2, len(links.items))
+	assert.Equal(t, "Docs", links.items[1].getField("name").scalar)
+	assert.Equal(t, true, links.items[0].getField("options").getField("external").scalar)
+	assert.Equal(t, int64(1), links.items[0].getField("options").getField("order").scalar)
+}
+
+func TestTomlConfigErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		line     int
+		column   int
+		message  string
+	}{
+		{"date", "[build]\nreleased = 2022-01-01\n", 0, 0, "invalid TOML: `build.released` is a date or time, which cannot be used in the configuration"},
+		{"date in an array", "dates = [1, 07:30:00]\n", 0, 0, "invalid TOML: `dates` is a date or time, which cannot be used in the configuration"},
+		// the decoder does not tell where some errors are found
+		{"key defined twice", "title = 'a'\ntitle = 'b'\n", 0, 0, "invalid TOML: key title is already defined"},
+		{"table defined twice", "[src]\n[src]\n", 0, 0, "invalid TOML: table src already exists"},
+		{"key of an inline table", "src = { root = 'lib' }\nsrc.includes = []\n", 0, 0, "invalid TOML: expected src to be a table, not a value"},
+		{"number out of range", "limits.memory = 99999999999999999999\n", 1, 17, "invalid TOML: "},
+		{"missing value", "title = \n", 1, 9, "invalid TOML: "},
+		{"unclosed string", "title = \"Acme\n", 1, 14, "invalid TOML: "},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := parseTomlConfig([]byte(test.contents))
+			assert.Nil(t, root)
+
+			var syntaxError *configSyntaxError
+			assert.True(t, errors.As(err, &syntaxError))
+			assert.Equal(t, filePosition{test.line, test.column}, syntaxError.position)
+			assert.Contains(t, syntaxError.message, test.message)
+		})
+	}
+}
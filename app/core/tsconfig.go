@@ -0,0 +1,213 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Compiler options from `tsconfig.json` that are of
+// interest when parsing the source files
+type TsCompilerOptions struct {
+	Target   string              `json:"target"`   // the ECMAScript target version
+	BaseUrl  string              `json:"baseUrl"`  // the folder to resolve non-relative imports from
+	Paths    map[string][]string `json:"paths"`    // import aliases like `@/*`, mapped to folders
+	RootDirs []string            `json:"rootDirs"` // folders whose contents are merged when resolving imports
+}
+
+// A reference to another Typescript project, as used in monorepos
+type TsProjectReference struct {
+	Path string `json:"path"` // the folder of the project, or its `tsconfig.json` file
+}
+
+// Structure of the `tsconfig.json` file as defined by Typescript
+// Refer https://www.typescriptlang.org/tsconfig for more details
+type TsConfig struct {
+	Extends         json.RawMessage      `json:"extends"` // the config(s) this config extends, a string or an array
+	CompilerOptions *TsCompilerOptions   `json:"compilerOptions"`
+	References      []TsProjectReference `json:"references"`
+
+	folder    string // the absolute folder containing the config
+	pathsBase string // the absolute folder `paths` are relative to
+}
+
+// Read the `tsconfig.json` file from the given folder, if present,
+// along with any configs it extends. Returns `nil` if the file does
+// not exist or cannot be read.
+func readTsConfig(folder string) *TsConfig {
+	return readTsConfigFile(filepath.Join(folder, "tsconfig.json"), make(map[string]bool))
+}
+
+// Read the given `tsconfig.json` file, merging in the compiler options
+// of the configs it extends. Paths in the compiler options are made
+// absolute relative to the config that declares them.
+func readTsConfigFile(tsConfigFilePath string, seen map[string]bool) *TsConfig {
+	tsConfigFilePath, err := filepath.Abs(tsConfigFilePath)
+	if err != nil || seen[tsConfigFilePath] || !FileExists(tsConfigFilePath) {
+		return nil
+	}
+	seen[tsConfigFilePath] = true
+
+	contents, err := os.ReadFile(tsConfigFilePath)
+	if err != nil {
+		return nil
+	}
+
+	// `tsconfig.json` allows comments and trailing commas
+	tsConfig := TsConfig{}
+	err = json.Unmarshal(stripJsonComments(contents), &tsConfig)
+	if err != nil {
+		return nil
+	}
+
+	tsConfig.folder = filepath.Dir(tsConfigFilePath)
+
+	// make paths absolute relative to this config
+	options := tsConfig.CompilerOptions
+	if options != nil {
+		if options.BaseUrl != "" {
+			options.BaseUrl = getAbsolutePath(tsConfig.folder, options.BaseUrl)
+		}
+
+		for index, rootDir := range options.RootDirs {
+			options.RootDirs[index] = getAbsolutePath(tsConfig.folder, rootDir)
+		}
+
+		if options.Paths != nil {
+			tsConfig.pathsBase = tsConfig.folder
+		}
+	}
+
+	// merge the configs being extended, in order, with this config
+	// taking precedence over all of them
+	merged := &TsConfig{}
+	for _, extends := range tsConfig.getExtends() {
+		base := readTsConfigFile(resolveTsConfigPath(tsConfig.folder, extends), seen)
+		if base != nil {
+			merged.merge(base)
+		}
+	}
+	merged.merge(&tsConfig)
+
+	// project references are not inherited
+	merged.References = tsConfig.References
+	merged.folder = tsConfig.folder
+
+	// `paths` are relative to `baseUrl`, when present
+	if merged.CompilerOptions != nil && merged.CompilerOptions.BaseUrl != "" {
+		merged.pathsBase = merged.CompilerOptions.BaseUrl
+	}
+
+	return merged
+}
+
+// Return the configs extended by this config, as `extends` may
+// either be a single string or an array of strings.
+func (tsConfig *TsConfig) getExtends() []string {
+	if len(tsConfig.Extends) == 0 {
+		return nil
+	}
+
+	var extends string
+	if json.Unmarshal(tsConfig.Extends, &extends) == nil {
+		return []string{extends}
+	}
+
+	var extendsList []string
+	json.Unmarshal(tsConfig.Extends, &extendsList)
+	return extendsList
+}
+
+// Merge the compiler options of the other config into this config,
+// the options specified in the other config taking precedence.
+func (tsConfig *TsConfig) merge(other *TsConfig) {
+	if other.CompilerOptions == nil {
+		return
+	}
+
+	if tsConfig.CompilerOptions == nil {
+		tsConfig.CompilerOptions = &TsCompilerOptions{}
+	}
+
+	options := tsConfig.CompilerOptions
+	if other.CompilerOptions.Target != "" {
+		options.Target = other.CompilerOptions.Target
+	}
+
+	if other.CompilerOptions.BaseUrl != "" {
+		options.BaseUrl = other.CompilerOptions.BaseUrl
+	}
+
+	if other.CompilerOptions.Paths != nil {
+		options.Paths = other.CompilerOptions.Paths
+		tsConfig.pathsBase = other.pathsBase
+	}
+
+	if other.CompilerOptions.RootDirs != nil {
+		options.RootDirs = other.CompilerOptions.RootDirs
+	}
+}
+
+// Return the path of the config file referred to in `extends` or
+// `references`, which is either a relative or absolute path to a file
+// or folder, or the name of a package in `node_modules`, like
+// `@tsconfig/node16`.
+func resolveTsConfigPath(folder string, name string) string {
+	var tsConfigPath string
+	if strings.HasPrefix(name, ".") || filepath.IsAbs(name) {
+		tsConfigPath = getAbsolutePath(folder, name)
+	} else {
+		tsConfigPath = findInNodeModules(folder, name)
+	}
+
+	if info, err := os.Stat(tsConfigPath); err == nil && info.IsDir() {
+		return filepath.Join(tsConfigPath, "tsconfig.json")
+	}
+
+	if !FileExists(tsConfigPath) && !strings.HasSuffix(tsConfigPath, ".json") {
+		return tsConfigPath + ".json"
+	}
+
+	return tsConfigPath
+}
+
+// Return the path as is when it is absolute, or else relative
+// to the given folder.
+func getAbsolutePath(folder string, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(folder, path)
+}
+
+// Find the given path in the `node_modules` folder of the folder
+// or any of its parents. Returns the path within the nearest
+// `node_modules` folder if not found.
+func findInNodeModules(folder string, name string) string {
+	for current := folder; ; current = filepath.Dir(current) {
+		candidate := filepath.Join(current, "node_modules", name)
+		if FileExists(candidate) || FileExists(candidate+".json") {
+			return candidate
+		}
+
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return filepath.Join(folder, "node_modules", name)
+}
@@ -17,54 +17,6 @@ import (
 	"os"
 )
 
-// Check if a file should be included in the list
-// of files to be parsed, against the inclusion rules
-// defined in user supplied configuation
-func isFileIncluded(path string, includes []string) bool {
-	for _, pattern := range includes {
-		if isWildCardMatch(path, pattern) {
-			return true
-		}
-	}
-
-	return false
-}
-
-// Check if the given string is a wildcard match against
-// the given wildcard pattern. Returns a `bool`.
-func isWildCardMatch(str string, pattern string) bool {
-	i := 0
-	j := 0
-	starIndex := -1
-	iIndex := -1
-
-	strLen := len(str)
-	patternLen := len(pattern)
-
-	for i < strLen {
-		if j < patternLen && (string(pattern[j]) == "?" || string(pattern[j]) == string(str[i])) {
-			i++
-			j++
-		} else if j < patternLen && string(pattern[j]) == "*" {
-			starIndex = j
-			iIndex = i
-			j++
-		} else if starIndex != -1 {
-			j = starIndex + 1
-			i = iIndex + 1
-			iIndex++
-		} else {
-			return false
-		}
-	}
-
-	for j < patternLen && string(pattern[j]) == "*" {
-		j++
-	}
-
-	return j == patternLen
-}
-
 func FileExists(filePath string) bool {
 	_, err := os.Stat(filePath)
 
@@ -78,3 +30,69 @@ func FileExists(filePath string) bool {
 
 	return false
 }
+
+// Strip comments and trailing commas from the given JSON contents,
+// as allowed in files like `tsconfig.json`, so that the contents can
+// be parsed using the standard JSON decoder. String values are left
+// untouched.
+func stripJsonComments(contents []byte) []byte {
+	result := make([]byte, 0, len(contents))
+	inString := false
+
+	for i := 0; i < len(contents); i++ {
+		c := contents[i]
+
+		if inString {
+			result = append(result, c)
+			if c == '\\' && i+1 < len(contents) {
+				i++
+				result = append(result, contents[i])
+			} else if c == '"' {
+				inString = false
+			}
+
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			result = append(result, c)
+
+		case c == '/' && i+1 < len(contents) && contents[i+1] == '/':
+			// line comment, skip till end of line
+			for i < len(contents) && contents[i] != '\n' {
+				i++
+			}
+			i--
+
+		case c == '/' && i+1 < len(contents) && contents[i+1] == '*':
+			// block comment, skip till closing marker
+			i += 2
+			for i+1 < len(contents) && !(contents[i] == '*' && contents[i+1] == '/') {
+				i++
+			}
+			i++
+
+		case c == ']' || c == '}':
+			// remove any trailing comma before the closing bracket
+			end := len(result) - 1
+			for end >= 0 && isJsonWhitespace(result[end]) {
+				end--
+			}
+			if end >= 0 && result[end] == ',' {
+				result = append(result[:end], result[end+1:]...)
+			}
+			result = append(result, c)
+
+		default:
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+func isJsonWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
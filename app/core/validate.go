@@ -0,0 +1,615 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	ast "sangupta.com/redefine/ast"
+)
+
+// This file contains the validation of the redefine configuration,
+// reporting syntax errors, values of the wrong type and unknown keys
+// along with where they are in the file, and paths that do not exist.
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Walks the tokens of a JSON document along with the Go type it is
+// decoded into, collecting the problems found.
+type configValidator struct {
+	file        string
+	contents    []byte
+	decoder     *json.Decoder
+	diagnostics []ast.Diagnostic
+}
+
+// Decode the configuration in the contents into the target, which
+// must be a pointer. Returns the problems found, and whether the
+// target could be decoded. When `key` is not empty, only the value
+// of that key within the top-level object is checked and decoded,
+// such as the `redefine` key of `package.json`, and the target is
+// left unchanged if the key is not present.
+func decodeConfig(file string, contents []byte, key string, target any) ([]ast.Diagnostic, bool) {
+	validator := &configValidator{
+		file:     file,
+		contents: contents,
+		decoder:  json.NewDecoder(bytes.NewReader(contents)),
+	}
+	validator.decoder.UseNumber()
+
+	targetType := reflect.TypeOf(target).Elem()
+	var err error
+	if key == "" {
+		err = validator.walk(targetType, "")
+	} else {
+		err = validator.walkKey(key, targetType)
+	}
+
+	// report the syntax error, after which nothing can be decoded
+	if err != nil {
+		var syntaxError *json.SyntaxError
+		offset := validator.decoder.InputOffset()
+		if errors.As(err, &syntaxError) {
+			offset = syntaxError.Offset
+		}
+
+		validator.addError(offset, "invalid JSON: "+err.Error())
+		return validator.diagnostics, false
+	}
+
+	if ast.HasErrors(validator.diagnostics) {
+		return validator.diagnostics, false
+	}
+
+	value := json.RawMessage(contents)
+	if key != "" {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(contents, &values); err != nil {
+			validator.addError(0, err.Error())
+			return validator.diagnostics, false
+		}
+
+		value = values[key]
+		if value == nil {
+			return validator.diagnostics, true
+		}
+	}
+
+	if err := json.Unmarshal(value, target); err != nil {
+		validator.addError(0, err.Error())
+		return validator.diagnostics, false
+	}
+
+	return validator.diagnostics, true
+}
+
+// Warn of the keys of the top-level object that are ignored, as
+// their values could not be read, such as the fields of package.json
+// written in a form that is not understood. Each warning is reported
+// where the value starts.
+func checkIgnoredKeys(file string, contents []byte, keys []string) []ast.Diagnostic {
+	validator := &configValidator{
+		file:     file,
+		contents: contents,
+		decoder:  json.NewDecoder(bytes.NewReader(contents)),
+	}
+	validator.decoder.UseNumber()
+
+	token, err := validator.decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	for validator.decoder.More() {
+		token, err := validator.decoder.Token()
+		if err != nil {
+			break
+		}
+
+		key, _ := token.(string)
+		if !slices.Contains(keys, key) {
+			if validator.skip() != nil {
+				break
+			}
+			continue
+		}
+
+		offset := validator.valueOffset()
+		value, err := validator.decoder.Token()
+		if err != nil {
+			break
+		}
+
+		validator.add(offset, ast.SEVERITY_WARNING, "`"+key+"` is ignored, as it cannot be read from "+describeJsonToken(value))
+		if validator.skipRest(value) != nil {
+			break
+		}
+	}
+
+	return validator.diagnostics
+}
+
+// Check only the value of the given key in the top-level object,
+// skipping all other keys.
+func (validator *configValidator) walkKey(key string, valueType reflect.Type) error {
+	token, err := validator.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token != json.Delim('{') {
+		return validator.skipRest(token)
+	}
+
+	for validator.decoder.More() {
+		token, err := validator.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if token == key {
+			err = validator.walk(valueType, key)
+		} else {
+			err = validator.skip()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = validator.decoder.Token()
+	return err
+}
+
+// Check the next value against the Go type it is decoded into. The
+// path is the dotted path of keys leading to the value, such as
+// `src.root`. Returns an error only for invalid JSON.
+func (validator *configValidator) walk(valueType reflect.Type, path string) error {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	// types that decode themselves accept values of any shape
+	if reflect.PointerTo(valueType).Implements(jsonUnmarshalerType) || valueType.Kind() == reflect.Interface {
+		return validator.skip()
+	}
+
+	offset := validator.valueOffset()
+	token, err := validator.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	// `null` leaves the value unchanged
+	if token == nil {
+		return nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.Struct:
+		if token != json.Delim('{') {
+			validator.addTypeError(offset, path, "an object", token)
+			return validator.skipRest(token)
+		}
+
+		for validator.decoder.More() {
+			keyOffset := validator.valueOffset()
+			token, err := validator.decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			key := token.(string)
+			field, found := findJsonField(valueType, key)
+			if !found {
+				validator.addUnknownKey(keyOffset, joinConfigPath(path, key), key, valueType)
+				if err := validator.skip(); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := validator.walk(field.Type, joinConfigPath(path, getJsonName(field))); err != nil {
+				return err
+			}
+		}
+
+		_, err = validator.decoder.Token()
+		return err
+
+	case reflect.Map:
+		if token != json.Delim('{') {
+			validator.addTypeError(offset, path, "an object", token)
+			return validator.skipRest(token)
+		}
+
+		for validator.decoder.More() {
+			token, err := validator.decoder.Token()
+			if err != nil {
+				return err
+			}
+
+			if err := validator.walk(valueType.Elem(), joinConfigPath(path, token.(string))); err != nil {
+				return err
+			}
+		}
+
+		_, err = validator.decoder.Token()
+		return err
+
+	case reflect.Slice, reflect.Array:
+		if token != json.Delim('[') {
+			validator.addTypeError(offset, path, "an array", token)
+			return validator.skipRest(token)
+		}
+
+		for index := 0; validator.decoder.More(); index++ {
+			if err := validator.walk(valueType.Elem(), fmt.Sprintf("%s[%d]", path, index)); err != nil {
+				return err
+			}
+		}
+
+		_, err = validator.decoder.Token()
+		return err
+
+	case reflect.String:
+		if _, ok := token.(string); !ok {
+			validator.addTypeError(offset, path, "a string", token)
+		}
+
+	case reflect.Bool:
+		if _, ok := token.(bool); !ok {
+			validator.addTypeError(offset, path, "true or false", token)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := token.(json.Number)
+		if !ok {
+			validator.addTypeError(offset, path, "a whole number", token)
+		} else if _, err := number.Int64(); err != nil || (valueType.Kind() >= reflect.Uint && strings.HasPrefix(string(number), "-")) {
+			validator.addError(offset, "`"+path+"` should be a whole number, found "+string(number))
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if _, ok := token.(json.Number); !ok {
+			validator.addTypeError(offset, path, "a number", token)
+		}
+	}
+
+	return validator.skipRest(token)
+}
+
+// Skip the next value, whatever its shape.
+func (validator *configValidator) skip() error {
+	token, err := validator.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	return validator.skipRest(token)
+}
+
+// Skip the rest of the value that starts with the given token,
+// which is the whole value unless it opens an object or array.
+func (validator *configValidator) skipRest(token json.Token) error {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return nil
+	}
+
+	for depth := 1; depth > 0; {
+		token, err := validator.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// Return the offset where the next value starts, skipping the
+// whitespace and separators after the previous token.
+func (validator *configValidator) valueOffset() int64 {
+	offset := validator.decoder.InputOffset()
+	for offset < int64(len(validator.contents)) && strings.IndexByte(" \t\r\n:,", validator.contents[offset]) >= 0 {
+		offset++
+	}
+
+	return offset
+}
+
+func (validator *configValidator) addError(offset int64, message string) {
+	validator.add(offset, ast.SEVERITY_ERROR, message)
+}
+
+func (validator *configValidator) addTypeError(offset int64, path string, expected string, token json.Token) {
+	validator.addError(offset, "`"+path+"` should be "+expected+", found "+describeJsonToken(token))
+}
+
+// Warn of a key that is not part of the configuration, suggesting
+// the closest known key, if any.
+func (validator *configValidator) addUnknownKey(offset int64, path string, key string, structType reflect.Type) {
+	message := "unknown key `" + path + "`"
+	if suggestion := suggestJsonName(structType, key); suggestion != "" {
+		message += ", did you mean `" + suggestion + "`?"
+	}
+
+	validator.add(offset, ast.SEVERITY_WARNING, message)
+}
+
+func (validator *configValidator) add(offset int64, severity ast.DiagnosticSeverity, message string) {
+	line, column := getLineAndColumn(validator.contents, offset)
+	validator.diagnostics = append(validator.diagnostics, ast.Diagnostic{
+		File:     validator.file,
+		Line:     line,
+		Column:   column,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+// Return the 1-based line and column of the byte offset.
+func getLineAndColumn(contents []byte, offset int64) (int, int) {
+	if offset > int64(len(contents)) {
+		offset = int64(len(contents))
+	}
+
+	before := contents[:offset]
+	line := bytes.Count(before, []byte{'\n'}) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
+
+// Describe the JSON value starting with the token, for messages.
+func describeJsonToken(token json.Token) string {
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			return "an object"
+		}
+		return "an array"
+
+	case string:
+		return "the string \"" + value + "\""
+
+	case bool, json.Number:
+		return fmt.Sprint(value)
+	}
+
+	return "null"
+}
+
+// Find the field of the struct that the JSON key decodes into,
+// matching names without regard to case as `encoding/json` does.
+func findJsonField(structType reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := getJsonName(field)
+		if name == key {
+			return field, true
+		}
+
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &field
+		}
+	}
+
+	if folded != nil {
+		return *folded, true
+	}
+
+	return reflect.StructField{}, false
+}
+
+// Return the name of the field in JSON.
+func getJsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// Return the JSON name of the struct field closest to the unknown
+// key, or an empty string if none is close enough.
+func suggestJsonName(structType reflect.Type, key string) string {
+	names := make([]string, 0, structType.NumField())
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if field.IsExported() && field.Tag.Get("json") != "-" {
+			names = append(names, getJsonName(field))
+		}
+	}
+
+	return SuggestName(names, key)
+}
+
+// Return the name closest to the unknown one, ignoring case, or an
+// empty string if none is close enough.
+func SuggestName(names []string, unknown string) string {
+	best := ""
+	bestDistance := len(unknown)/2 + 1
+
+	for _, name := range names {
+		distance := getEditDistance(strings.ToLower(unknown), strings.ToLower(name))
+		if distance < bestDistance {
+			best = name
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// Return the Levenshtein distance between the two strings.
+func getEditDistance(first string, second string) int {
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+
+	for index := range previous {
+		previous[index] = index
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(second)]
+}
+
+func joinConfigPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// Check that the paths in the configuration, as given by the user
+// and before defaults are applied, exist. Paths that are not given
+// are not checked, as their defaults are optional. Problems are
+// reported against where the path was set.
+func validateConfigPaths(config *RedefineConfig) []ast.Diagnostic {
+	diagnostics := make([]ast.Diagnostic, 0)
+
+	check := func(key string, value string, severity ast.DiagnosticSeverity) {
+		if value == "" || isUrl(value) {
+			return
+		}
+
+		if !FileExists(config.NormalizeFolderPath(value)) {
+			path, _, _ := strings.Cut(key, "[")
+			diagnostics = append(diagnostics, ast.Diagnostic{
+				File:     config.getSource(path),
+				Severity: severity,
+				Message:  "`" + key + "` refers to " + value + ", which does not exist",
+			})
+		}
+	}
+
+	if config.SrcFolder != nil {
+		check("src.root", config.SrcFolder.Root, ast.SEVERITY_ERROR)
+		for index, root := range config.SrcFolder.Roots {
+			check(fmt.Sprintf("src.roots[%d]", index), root, ast.SEVERITY_ERROR)
+		}
+	}
+
+	if config.DocsFolder != nil {
+		check("docs.root", config.DocsFolder.Root, ast.SEVERITY_WARNING)
+	}
+
+	if config.Build != nil {
+		for index, css := range config.Build.CssFiles {
+			check(fmt.Sprintf("build.css[%d]", index), css, ast.SEVERITY_WARNING)
+		}
+		check("build.lib", config.Build.Lib, ast.SEVERITY_WARNING)
+	}
+
+	return diagnostics
+}
+
+// Check that the target, which may have been read from `tsconfig.json`,
+// is one the parsers know. Unknown targets are reported as warnings,
+// and files are parsed with the latest target.
+func validateConfigTarget(config *RedefineConfig) []ast.Diagnostic {
+	if config.Target == "" {
+		return nil
+	}
+
+	if _, known := ast.NormalizeScriptTarget(config.Target); known {
+		return nil
+	}
+
+	message := "unknown `target` " + config.Target + ", files are parsed with the latest target"
+	if suggestion := SuggestName(ast.SCRIPT_TARGETS, config.Target); suggestion != "" {
+		message += ", did you mean `" + suggestion + "`?"
+	}
+
+	return []ast.Diagnostic{{
+		File:     config.getSource("target"),
+		Severity: ast.SEVERITY_WARNING,
+		Message:  message,
+	}}
+}
+
+// Check that the parser is one of those known, and that the options
+// given are those it supports. The type checker runs only with the
+// `typescript` parser, and is ignored with any other.
+func validateConfigParser(config *RedefineConfig) []ast.Diagnostic {
+	if config.Parser == "" {
+		return nil
+	}
+
+	if !slices.Contains(ast.PARSERS, config.Parser) {
+		message := "unknown `parser` " + config.Parser + ", expected `" + strings.Join(ast.PARSERS, "` or `") + "`"
+		if suggestion := SuggestName(ast.PARSERS, config.Parser); suggestion != "" {
+			message = "unknown `parser` " + config.Parser + ", did you mean `" + suggestion + "`?"
+		}
+
+		return []ast.Diagnostic{{
+			File:     config.getSource("parser"),
+			Severity: ast.SEVERITY_ERROR,
+			Message:  message,
+		}}
+	}
+
+	if config.TypeChecker && config.Parser != ast.PARSER_TYPESCRIPT {
+		return []ast.Diagnostic{{
+			File:     config.getSource("typeChecker"),
+			Severity: ast.SEVERITY_WARNING,
+			Message:  "`typeChecker` is ignored, as the `" + config.Parser + "` parser cannot check types",
+		}}
+	}
+
+	return nil
+}
+
+// Check if the value is a URL rather than a local path.
+func isUrl(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "//")
+}
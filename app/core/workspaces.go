@@ -0,0 +1,321 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+	ast "sangupta.com/redefine/ast"
+	"sangupta.com/redefine/model"
+)
+
+// This file contains the code to document the packages of a monorepo,
+// listed by the `workspaces` of package.json as npm and yarn do, or by
+// `pnpm-workspace.yaml`. Each package is read with its own configuration,
+// and the components of all are documented together.
+
+// The file listing the packages of a pnpm workspace
+const PNPM_WORKSPACE_FILE = "pnpm-workspace.yaml"
+
+// A package of the workspace
+type workspacePackage struct {
+	name   string          // the name from its package.json, which components are imported from
+	folder string          // the folder of the package, relative to the workspace, with forward slashes
+	config *RedefineConfig // the configuration of the package
+}
+
+// Details of a package of the workspace, whose components are
+// documented along with those of the other packages
+type packagePayload struct {
+	Name        string            `json:"name"`             // the package name, which components are imported from
+	Folder      string            `json:"folder"`           // the folder of the package, relative to the workspace
+	Version     string            `json:"version"`          // version read from package.json file of the package
+	Description string            `json:"description"`      // description read from package.json file of the package
+	Index       string            `json:"libDocs"`          // index file as defined in the docs folder of the package
+	Lib         string            `json:"library"`          // the component library JS of the package
+	Peers       map[string]string `json:"peerDependencies"` // version ranges of the peer dependencies of the package
+}
+
+// Read the configuration of every package of the workspace, or only
+// of the one named in the options. Returns nothing if the base folder
+// is not a workspace.
+func (config *RedefineConfig) readWorkspaces(options ConfigOptions) ([]*workspacePackage, []ast.Diagnostic) {
+	patterns, file, err := config.getWorkspacePatterns()
+	if err != nil {
+		return nil, []ast.Diagnostic{newConfigSyntaxError(file, err)}
+	}
+
+	if len(patterns) == 0 {
+		if options.Package != "" {
+			return nil, []ast.Diagnostic{newConfigError("", errors.New("package "+options.Package+" was asked for, but "+config.baseFolder+" is not a workspace"))}
+		}
+
+		return nil, nil
+	}
+
+	// packages are read with the same environment and overrides,
+	// but not with the configuration file given for the workspace
+	environment := []string{}
+	for _, variable := range options.Environment {
+		if !strings.HasPrefix(variable, ENV_CONFIG_FILE+"=") {
+			environment = append(environment, variable)
+		}
+	}
+
+	var packages []*workspacePackage
+	// the package may be asked for by its folder, written the way
+	// the patterns are
+	wanted := strings.TrimSuffix(strings.TrimPrefix(options.Package, "./"), "/")
+
+	var diagnostics []ast.Diagnostic
+	var names []string
+
+	folders, err := findWorkspaceFolders(config.NormalizeFolderPath(""), patterns)
+	if err != nil {
+		return nil, []ast.Diagnostic{newConfigError(file, err)}
+	}
+
+	for _, folder := range folders {
+		// packages whose package.json cannot be read are reported,
+		// and not documented
+		name, problems := readPackageName(config.NormalizeFolderPath(folder))
+		if ast.HasErrors(problems) {
+			diagnostics = append(diagnostics, problems...)
+			continue
+		}
+		if name == "" {
+			name = folder
+		}
+
+		names = append(names, name)
+		if wanted != "" && wanted != name && wanted != folder {
+			continue
+		}
+
+		getLogger(options.Logger).Debug("Reading workspace package", "name", name, "path", folder)
+		packageConfig, problems := GetRedefineConfig(ConfigOptions{
+			BaseFolder:  path.Join(config.baseFolder, folder),
+			Environment: environment,
+			Overrides:   options.Overrides,
+			Logger:      options.Logger,
+		})
+
+		diagnostics = append(diagnostics, problems...)
+		if packageConfig == nil {
+			continue
+		}
+
+		// link to the source of packages that do not name their
+		// repository within the repository of the workspace
+		if packageConfig.Repository.Url == "" && config.Repository.Url != "" {
+			repository := *config.Repository
+			repository.Directory = path.Join(config.Repository.Directory, folder)
+			packageConfig.Repository = &repository
+		}
+
+		packages = append(packages, &workspacePackage{name: name, folder: folder, config: packageConfig})
+	}
+
+	if options.Package != "" && len(packages) == 0 && !ast.HasErrors(diagnostics) {
+		message := "no package named " + options.Package + " in the workspace"
+		if suggestion := SuggestName(names, wanted); suggestion != "" {
+			message += ", did you mean `" + suggestion + "`?"
+		}
+
+		diagnostics = append(diagnostics, newConfigError(file, errors.New(message)))
+	}
+
+	return packages, diagnostics
+}
+
+// Return the patterns of the package folders of the workspace, along
+// with the file they are read from. Returns no patterns if the base
+// folder is not a workspace.
+func (config *RedefineConfig) getWorkspacePatterns() ([]string, string, error) {
+	if len(config.packageJson.Workspaces) > 0 {
+		return config.packageJson.Workspaces, config.NormalizeFolderPath("package.json"), nil
+	}
+
+	file := config.NormalizeFolderPath(PNPM_WORKSPACE_FILE)
+	contents, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, file, err
+	}
+
+	var workspace struct {
+		Packages []string `yaml:"packages"`
+	}
+	err = yaml.Unmarshal(contents, &workspace)
+	return workspace.Packages, file, err
+}
+
+// Return the folders with a `package.json` that match the workspace
+// patterns, relative to the base folder, sorted. Patterns starting
+// with `!` exclude the folders they match, and later patterns take
+// precedence over earlier ones. Folders within `node_modules` and
+// hidden folders are skipped. Returns an error for invalid patterns.
+func findWorkspaceFolders(baseFolder string, patterns []string) ([]string, error) {
+	fileSystem := os.DirFS(baseFolder)
+	matched := map[string]bool{}
+
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./")
+		pattern = strings.TrimSuffix(pattern, "/")
+
+		// folders that are skipped are not walked into by patterns
+		// ending with `**`, while for other patterns, skipping would
+		// also skip the folders next to them
+		var skip error
+		if strings.HasSuffix(pattern, "**") {
+			skip = doublestar.SkipDir
+		}
+
+		err := doublestar.GlobWalk(fileSystem, pattern, func(folder string, entry fs.DirEntry) error {
+			if !entry.IsDir() {
+				return nil
+			}
+
+			if isSkippedWorkspaceFolder(folder) {
+				return skip
+			}
+
+			if FileExists(filepath.Join(baseFolder, folder, "package.json")) {
+				matched[folder] = !exclude
+			}
+
+			return nil
+		}, doublestar.WithNoFollow())
+
+		if err != nil {
+			return nil, errors.New("invalid workspace pattern " + pattern + ": " + err.Error())
+		}
+	}
+
+	var folders []string
+	for folder, included := range matched {
+		if included && folder != "." {
+			folders = append(folders, folder)
+		}
+	}
+
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// Check if the folder, relative to the workspace, is within a hidden
+// folder or `node_modules`, where there are no packages to document.
+func isSkippedWorkspaceFolder(folder string) bool {
+	for _, segment := range strings.Split(folder, "/") {
+		if segment == "node_modules" || (strings.HasPrefix(segment, ".") && segment != ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Return the name from the `package.json` in the folder, if any,
+// along with the problems found reading it.
+func readPackageName(folder string) (string, []ast.Diagnostic) {
+	file := filepath.Join(folder, "package.json")
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil
+	}
+
+	var name string
+	problems, _ := decodeConfig(file, contents, "name", &name)
+	return name, problems
+}
+
+// Extract the components of every package of the workspace, each
+// with its own configuration, along with their docs. Components ids
+// are prefixed with the package name, such as `@acme/menu/Item`.
+func (app *RedefineApp) extractPackages(ctx context.Context) ([]model.Component, []ast.Diagnostic, error) {
+	var all []model.Component
+	var diagnostics []ast.Diagnostic
+
+	for _, workspacePackage := range app.Config.packages {
+		packageApp := *app
+		packageApp.Config = workspacePackage.config
+
+		components, problems, err := packageApp.extractComponents(ctx)
+		diagnostics = append(diagnostics, problems...)
+		if err != nil {
+			return nil, diagnostics, err
+		}
+
+		workspacePackage.config.readDocs(components)
+		for index := range components {
+			component := &components[index]
+			component.Package = workspacePackage.name
+			component.Id = workspacePackage.name + "/" + component.Id
+			if component.ParentId != "" {
+				component.ParentId = workspacePackage.name + "/" + component.ParentId
+			}
+
+			for subIndex := range component.SubComponents {
+				if component.SubComponents[subIndex].ComponentId != "" {
+					component.SubComponents[subIndex].ComponentId = workspacePackage.name + "/" + component.SubComponents[subIndex].ComponentId
+				}
+			}
+		}
+
+		all = append(all, components...)
+	}
+
+	return all, diagnostics, nil
+}
+
+// Return the details of the packages of the workspace, in the
+// order they were found.
+func (config *RedefineConfig) getPackagesPayload() []packagePayload {
+	if len(config.packages) == 0 {
+		return nil
+	}
+
+	payloads := make([]packagePayload, 0, len(config.packages))
+	for _, workspacePackage := range config.packages {
+		packageConfig := workspacePackage.config
+
+		var index []byte
+		if FileExists(packageConfig.DocsFolder.Index) {
+			index, _ = os.ReadFile(packageConfig.DocsFolder.Index)
+		}
+
+		payloads = append(payloads, packagePayload{
+			Name:        workspacePackage.name,
+			Folder:      workspacePackage.folder,
+			Version:     packageConfig.packageJson.Version,
+			Description: packageConfig.packageJson.Description,
+			Index:       string(index),
+			Lib:         packageConfig.Build.Lib,
+			Peers:       packageConfig.packageJson.PeerDependencies,
+		})
+	}
+
+	return payloads
+}
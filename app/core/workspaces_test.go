@@ -0,0 +1,90 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ast "sangupta.com/redefine/ast"
+)
+
+func TestFindWorkspaceFolders(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"package.json":                               `{ "name": "acme" }`,
+		"apps/docs/package.json":                     `{ "name": "docs" }`,
+		"packages/button/package.json":               `{ "name": "@acme/button" }`,
+		"packages/nested/menu/package.json":          `{ "name": "@acme/menu" }`,
+		"packages/nested/menu/src/index.ts":          "",
+		"packages/internal/package.json":             `{ "name": "@acme/internal" }`,
+		"packages/internal/keep/package.json":        `{ "name": "@acme/keep" }`,
+		"packages/icons/node_modules/x/package.json": `{ "name": "x" }`,
+		"packages/.cache/y/package.json":             `{ "name": "y" }`,
+		"packages/empty/src/index.ts":                "",
+	})
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{"single level", []string{"packages/*"}, []string{"packages/button", "packages/internal"}},
+		{"any level", []string{"./packages/**/"}, []string{"packages/button", "packages/internal", "packages/internal/keep", "packages/nested/menu"}},
+		{"exclusion", []string{"packages/**", "!packages/internal"}, []string{"packages/button", "packages/internal/keep", "packages/nested/menu"}},
+		{"later patterns take precedence", []string{"!packages/button", "packages/*", "apps/docs"}, []string{"apps/docs", "packages/button", "packages/internal"}},
+		{"exclusion of many", []string{"packages/**", "!packages/internal/**"}, []string{"packages/button", "packages/nested/menu"}},
+		{"no match", []string{"libs/*"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			folders, err := findWorkspaceFolders(folder, test.patterns)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, folders)
+		})
+	}
+
+	_, err := findWorkspaceFolders(folder, []string{"packages/[a-"})
+	assert.EqualError(t, err, "invalid workspace pattern packages/[a-: syntax error in pattern")
+}
+
+func TestReadPackageName(t *testing.T) {
+	folder := t.TempDir()
+	writeTestFiles(t, folder, map[string]string{
+		"button/package.json":  `{ "name": "@acme/button" }`,
+		"menu/package.json":    "{\n  \"name\": 5\n}",
+		"invalid/package.json": `{ "name": "@acme/invalid", }`,
+	})
+
+	name, problems := readPackageName(filepath.Join(folder, "button"))
+	assert.Equal(t, "@acme/button", name)
+	assert.Equal(t, 0, len(problems))
+
+	// a folder without a package.json has no name, and no problems
+	name, problems = readPackageName(folder)
+	assert.Equal(t, "", name)
+	assert.Equal(t, 0, len(problems))
+
+	_, problems = readPackageName(filepath.Join(folder, "menu"))
+	assert.Equal(t, 1, len(problems))
+	assert.Equal(t, ast.SEVERITY_ERROR, problems[0].Severity)
+	assert.Equal(t, filepath.Join(folder, "menu", "package.json"), problems[0].File)
+	assert.Equal(t, 2, problems[0].Line)
+	assert.Equal(t, "`name` should be a string, found 5", problems[0].Message)
+
+	_, problems = readPackageName(filepath.Join(folder, "invalid"))
+	assert.Equal(t, 1, len(problems))
+	assert.Contains(t, problems[0].Message, "invalid JSON")
+}
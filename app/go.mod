@@ -1,16 +1,18 @@
 module sangupta.com/redefine
 
-go 1.18
+go 1.21.0
 
 require (
+	github.com/bmatcuk/doublestar/v4 v4.6.1
 	github.com/deckarep/golang-set/v2 v2.1.0
 	github.com/google/uuid v1.3.0
+	github.com/pelletier/go-toml/v2 v2.2.4
 	github.com/quickjs-go/quickjs-go v0.0.0-20220113024216-b0ec36d46b2b
 	github.com/stretchr/testify v1.7.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
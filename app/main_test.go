@@ -13,17 +13,26 @@ that can be found in LICENSE file in the code repository.
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	ast "sangupta.com/redefine/ast"
+	"sangupta.com/redefine/core"
 	"sangupta.com/redefine/model"
 )
 
 func TestEmptySourceFile(t *testing.T) {
 	code := ""
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 0, "Test when source file is empty")
 }
 
@@ -39,7 +48,7 @@ func TestClassComponentWithNoPropsAndNoJsDoc(t *testing.T) {
 	}
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1, "Test when source file is empty")
 
 	component := components[0]
@@ -62,7 +71,7 @@ func TestClassComponentWithExportStatement(t *testing.T) {
 	export default HelloWorld;
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1, "Test when source file is empty")
 
 	component := components[0]
@@ -85,7 +94,7 @@ func TestClassComponentWithExportStatementUsingCallExpression(t *testing.T) {
 	export default withIntl(HelloWorld);
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1, "Test when source file is empty")
 
 	component := components[0]
@@ -160,7 +169,7 @@ func TestClassComponentWithPropsAndWithJsDoc(t *testing.T) {
 	}
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1, "Test when source file is empty")
 
 	component := components[0]
@@ -273,7 +282,7 @@ func TestSimpleFunctionComponent(t *testing.T) {
 	}
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1)
 
 	component := components[0]
@@ -294,7 +303,7 @@ func TestSimpleFunctionComponentThatUsesFragments(t *testing.T) {
 	}
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1)
 
 	component := components[0]
@@ -317,7 +326,7 @@ func TestFunctionComponentWithExportStatement(t *testing.T) {
 	export default HelloWorld;
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1)
 
 	component := components[0]
@@ -340,7 +349,7 @@ func TestFunctionComponentWithExportStatementUsingCallExpression(t *testing.T) {
 	export default withIntl(HelloWorld);
 	`
 
-	components := getComponents(code)
+	components := getComponents(t, code)
 	assert.True(t, len(components) == 1)
 
 	component := components[0]
@@ -350,8 +359,1291 @@ func TestFunctionComponentWithExportStatementUsingCallExpression(t *testing.T) {
 	assert.Equal(t, 0, len(component.Props))
 }
 
-func getComponents(code string) []model.Component {
-	sourceFile, syntaxKind := ast.GetAstForFileContents(code)
+func TestFunctionComponentWithSubComponentAssignment(t *testing.T) {
+	code := `
+	export function TabPanel() {
+		return <div>Panel</div>
+	}
+
+	export function Tabs() {
+		return <div>Tabs</div>
+	}
+
+	Tabs.Panel = TabPanel;
+	Tabs.displayName = 'Tabs';
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 2)
+
+	panel := components[0]
+	assert.Equal(t, "TabPanel", panel.Name)
+	assert.Equal(t, "Tabs", panel.Parent)
+	assert.Nil(t, panel.SubComponents)
+
+	tabs := components[1]
+	assert.Equal(t, "Tabs", tabs.Name)
+	assert.Equal(t, "", tabs.Parent)
+	assert.Equal(t, 1, len(tabs.SubComponents))
+	assert.Equal(t, "Panel", tabs.SubComponents[0].Name)
+	assert.Equal(t, "TabPanel", tabs.SubComponents[0].ComponentName)
+}
+
+func TestClassComponentWithStaticSubComponent(t *testing.T) {
+	code := `import React from 'react';
+
+	export function MenuItem() {
+		return <li>Item</li>
+	}
+
+	export default class Menu extends React.Component {
+
+		static Item = MenuItem;
+
+		static defaultProps = {
+			open: false
+		}
+
+		render() {
+			return <ul></ul>
+		}
+
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 2)
+
+	item := components[0]
+	assert.Equal(t, "MenuItem", item.Name)
+	assert.Equal(t, "Menu", item.Parent)
+
+	menu := components[1]
+	assert.Equal(t, "Menu", menu.Name)
+	assert.Equal(t, 1, len(menu.SubComponents))
+	assert.Equal(t, "Item", menu.SubComponents[0].Name)
+	assert.Equal(t, "MenuItem", menu.SubComponents[0].ComponentName)
+}
+
+func TestClassComponentWithEventProps(t *testing.T) {
+	code := `import React from 'react';
+
+	interface InputProps {
+		/**
+		 * Fired when value changes.
+		 */
+		onChange(value: string, index?: number, ...rest: any[]): void;
+
+		onSelect?: (item: Array<string>) => boolean;
+
+		onKeyDown: React.KeyboardEventHandler<HTMLInputElement>;
+
+		online: boolean;
+	}
+
+	export default class Input extends React.Component<InputProps> {
+
+		render() {
+			return <input />
+		}
+
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 4, len(component.Props))
+	assert.Equal(t, 3, len(component.Events))
+
+	param := component.Props[0]
+	assert.Equal(t, "onChange", param.Name)
+	assert.Equal(t, "$function", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, true, param.IsEvent)
+	assert.Equal(t, "Fired when value changes.", param.Description)
+	assert.Equal(t, "void", param.ReturnType)
+	assert.Equal(t, 3, len(param.Params))
+	assert.Equal(t, "value", param.Params[0].Name)
+	assert.Equal(t, "string", param.Params[0].ParamType)
+	assert.Equal(t, false, param.Params[0].Optional)
+	assert.Equal(t, "index", param.Params[1].Name)
+	assert.Equal(t, true, param.Params[1].Optional)
+	assert.Equal(t, "rest", param.Params[2].Name)
+	assert.Equal(t, "any[]", param.Params[2].ParamType)
+	assert.Equal(t, true, param.Params[2].Rest)
+
+	param = component.Props[1]
+	assert.Equal(t, "onSelect", param.Name)
+	assert.Equal(t, "$function", param.PropType)
+	assert.Equal(t, false, param.Required)
+	assert.Equal(t, true, param.IsEvent)
+	assert.Equal(t, "boolean", param.ReturnType)
+	assert.Equal(t, "Array<string>", param.Params[0].ParamType)
+
+	param = component.Props[2]
+	assert.Equal(t, "onKeyDown", param.Name)
+	assert.Equal(t, "React.KeyboardEventHandler<HTMLInputElement>", param.PropType)
+	assert.Equal(t, true, param.IsEvent)
+
+	param = component.Props[3]
+	assert.Equal(t, "online", param.Name)
+	assert.Equal(t, false, param.IsEvent)
+}
+
+func TestGenericClassComponent(t *testing.T) {
+	code := `import React from 'react';
+
+	interface SelectProps<V> {
+		value: V;
+
+		options: Array<V>;
+
+		onSelect(item: V): void;
+
+		label: string;
+	}
+
+	export class Select<T extends object = {}> extends React.Component<SelectProps<T>> {
+
+		render() {
+			return <select></select>
+		}
+
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 1, len(component.TypeParameters))
+	assert.Equal(t, "T", component.TypeParameters[0].Name)
+	assert.Equal(t, "object", component.TypeParameters[0].Constraint)
+	assert.Equal(t, "{}", component.TypeParameters[0].Default)
+	assert.Equal(t, 4, len(component.Props))
+
+	param := component.Props[0]
+	assert.Equal(t, "value", param.Name)
+	assert.Equal(t, "T", param.PropType)
+	assert.Equal(t, true, param.IsGeneric)
+
+	param = component.Props[1]
+	assert.Equal(t, "options", param.Name)
+	assert.Equal(t, "Array<T>", param.PropType)
+	assert.Equal(t, true, param.IsGeneric)
+
+	param = component.Props[2]
+	assert.Equal(t, "onSelect", param.Name)
+	assert.Equal(t, "T", param.Params[0].ParamType)
+	assert.Equal(t, true, param.IsGeneric)
+
+	param = component.Props[3]
+	assert.Equal(t, "label", param.Name)
+	assert.Equal(t, false, param.IsGeneric)
+}
+
+func TestGenericFunctionComponent(t *testing.T) {
+	code := `
+	interface ListProps<T> {
+		items: T[];
+	}
+
+	export function List<T>(props: ListProps<T>) {
+		return <ul></ul>
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, "List", component.Name)
+	assert.Equal(t, 1, len(component.TypeParameters))
+	assert.Equal(t, "T", component.TypeParameters[0].Name)
+	assert.Equal(t, "", component.TypeParameters[0].Constraint)
+	assert.Equal(t, 1, len(component.Props))
+	assert.Equal(t, "T[]", component.Props[0].PropType)
+	assert.Equal(t, true, component.Props[0].IsGeneric)
+}
+
+func TestClassComponentWithStaticPropTypes(t *testing.T) {
+	code := `import React from 'react';
+	import PropTypes from 'prop-types';
+
+	export default class Button extends React.Component {
+
+		static propTypes = {
+			/**
+			 * The label of the button.
+			 */
+			label: PropTypes.string.isRequired,
+			size: PropTypes.oneOf(['sm', 'md']),
+			value: PropTypes.oneOfType([PropTypes.string, PropTypes.number]),
+			items: PropTypes.arrayOf(PropTypes.node),
+			onClick: PropTypes.func,
+			style: PropTypes.shape({
+				color: PropTypes.string.isRequired,
+			}),
+		}
+
+		static defaultProps = {
+			size: 'md',
+		}
+
+		render() {
+			return <button>{this.props.label}</button>
+		}
+
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 6, len(component.Props))
+	assert.Equal(t, 1, len(component.Events))
+
+	param := component.Props[0]
+	assert.Equal(t, "label", param.Name)
+	assert.Equal(t, "string", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, "The label of the button.", param.Description)
+
+	param = component.Props[1]
+	assert.Equal(t, "size", param.Name)
+	assert.Equal(t, "$enum", param.PropType)
+	assert.Equal(t, false, param.Required)
+	assert.Equal(t, "md", param.DefaultValue)
+	assert.Equal(t, 2, len(param.EnumTypes))
+	assert.Equal(t, "sm", param.EnumTypes[0].Name)
+	assert.Equal(t, "string", param.EnumTypes[0].ParamType)
+
+	param = component.Props[2]
+	assert.Equal(t, "value", param.Name)
+	assert.Equal(t, "$enum", param.PropType)
+	assert.Equal(t, "string", param.EnumTypes[0].Name)
+	assert.Equal(t, "number", param.EnumTypes[1].Name)
+
+	param = component.Props[3]
+	assert.Equal(t, "items", param.Name)
+	assert.Equal(t, "ReactNode[]", param.PropType)
+
+	param = component.Props[4]
+	assert.Equal(t, "onClick", param.Name)
+	assert.Equal(t, "$function", param.PropType)
+	assert.Equal(t, true, param.IsEvent)
+
+	param = component.Props[5]
+	assert.Equal(t, "style", param.Name)
+	assert.Equal(t, "$shape", param.PropType)
+	assert.Equal(t, 1, len(param.Shape))
+	assert.Equal(t, "color", param.Shape[0].Name)
+	assert.Equal(t, true, param.Shape[0].Required)
+}
+
+func TestPropTypesArrayOf(t *testing.T) {
+	code := `import React from 'react';
+	import PropTypes from 'prop-types';
+
+	export default class List extends React.Component {
+
+		static propTypes = {
+			items: PropTypes.arrayOf(PropTypes.shape({
+				label: PropTypes.string.isRequired,
+				href: PropTypes.string,
+			})).isRequired,
+			sizes: PropTypes.arrayOf(PropTypes.oneOf(['sm', 'md'])),
+			rows: PropTypes.arrayOf(PropTypes.arrayOf(PropTypes.exact({ id: PropTypes.number }))),
+		}
+
+		render() {
+			return <ul>{this.props.items}</ul>
+		}
+
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 3, len(component.Props))
+
+	// the shape of the items is that of the array
+	param := component.Props[0]
+	assert.Equal(t, "items", param.Name)
+	assert.Equal(t, "$shape[]", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, 2, len(param.Shape))
+	assert.Equal(t, "label", param.Shape[0].Name)
+	assert.Equal(t, true, param.Shape[0].Required)
+	assert.Equal(t, "href", param.Shape[1].Name)
+
+	// as are the values they may take
+	param = component.Props[1]
+	assert.Equal(t, "$enum[]", param.PropType)
+	assert.Equal(t, 2, len(param.EnumTypes))
+	assert.Equal(t, "sm", param.EnumTypes[0].Name)
+
+	param = component.Props[2]
+	assert.Equal(t, "$shape[][]", param.PropType)
+	assert.Equal(t, 1, len(param.Shape))
+	assert.Equal(t, "id", param.Shape[0].Name)
+	assert.Equal(t, "number", param.Shape[0].PropType)
+}
+
+func TestFunctionComponentWithPropTypesMergedWithInterface(t *testing.T) {
+	code := `import PropTypes from 'prop-types';
+
+	interface BadgeProps {
+		/**
+		 * The badge text.
+		 */
+		text: string;
+
+		/**
+		 * The size of the badge.
+		 */
+		size?: string;
+	}
+
+	export function Badge(props: BadgeProps) {
+		return <span>{props.text}</span>
+	}
+
+	Badge.propTypes = {
+		text: PropTypes.string,
+		size: PropTypes.string.isRequired,
+		tone: PropTypes.oneOf(['info', 'warn']).isRequired,
+	};
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 3, len(component.Props))
+
+	param := component.Props[0]
+	assert.Equal(t, "text", param.Name)
+	assert.Equal(t, "string", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, "The badge text.", param.Description)
+
+	// `isRequired` of `propTypes` makes the optional prop required
+	param = component.Props[1]
+	assert.Equal(t, "size", param.Name)
+	assert.Equal(t, "string", param.PropType)
+	assert.Equal(t, true, param.Required)
+
+	param = component.Props[2]
+	assert.Equal(t, "tone", param.Name)
+	assert.Equal(t, "$enum", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, 2, len(param.EnumTypes))
+}
+
+func TestFunctionComponentWithJsDocParams(t *testing.T) {
+	code := `
+	/**
+	 * A simple button.
+	 *
+	 * @param {Object} props
+	 * @param {string} props.label - the label to display
+	 * @param {'sm'|'md'} [props.size] the size of the button
+	 * @param {function(string): void} props.onClick
+	 */
+	export function Button(props) {
+		return <button>{props.label}</button>
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 3, len(component.Props))
+	assert.Equal(t, 1, len(component.Events))
+
+	param := component.Props[0]
+	assert.Equal(t, "label", param.Name)
+	assert.Equal(t, "string", param.PropType)
+	assert.Equal(t, true, param.Required)
+	assert.Equal(t, "the label to display", param.Description)
+
+	param = component.Props[1]
+	assert.Equal(t, "size", param.Name)
+	assert.Equal(t, "$enum", param.PropType)
+	assert.Equal(t, false, param.Required)
+	assert.Equal(t, 2, len(param.EnumTypes))
+	assert.Equal(t, "sm", param.EnumTypes[0].Name)
+
+	param = component.Props[2]
+	assert.Equal(t, "onClick", param.Name)
+	assert.Equal(t, "$function", param.PropType)
+	assert.Equal(t, true, param.IsEvent)
+	assert.Equal(t, "void", param.ReturnType)
+}
+
+func TestFunctionComponentWithJsDocTypedef(t *testing.T) {
+	code := `
+	/**
+	 * @typedef {Object} CardProps
+	 * @property {string} title the card title
+	 * @property {number=} elevation
+	 */
+
+	/**
+	 * @param {CardProps} props
+	 */
+	export function Card({ title, elevation }) {
+		return <div>{title}</div>
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 2, len(component.Props))
+
+	param := component.Props[0]
+	assert.Equal(t, "title", param.Name)
+	assert.Equal(t, "string", param.PropType)
+	assert.Equal(t, true, param.Required)
+
+	param = component.Props[1]
+	assert.Equal(t, "elevation", param.Name)
+	assert.Equal(t, "number", param.PropType)
+	assert.Equal(t, false, param.Required)
+}
+
+func TestTruncatedSourceFiles(t *testing.T) {
+	code := `import React from 'react';
+	import PropTypes from 'prop-types';
+
+	interface ButtonProps<T> extends React.HTMLAttributes<HTMLButtonElement> {
+		/**
+		 * The label of the button.
+		 */
+		label: string;
+		size?: 'sm' | 'md';
+		onClick?: (event: MouseEvent, value: T) => void;
+	}
+
+	/**
+	 * A button.
+	 */
+	export class Button<T> extends React.Component<ButtonProps<T>> {
+		static Icon = Icon;
+
+		static defaultProps = {
+			size: 'md',
+		}
+
+		render() {
+			return <button>{this.props.label}</button>
+		}
+	}
+
+	export function Icon(props: { name: string }) {
+		return <i>{props.name}</i>
+	}
+
+	Icon.propTypes = {
+		name: PropTypes.string.isRequired,
+	};
+
+	export const Menu = React.forwardRef((props: ButtonProps<string>, ref) => <ul ref={ref}></ul>);
+	Menu.Item = Icon;
+
+	class extends React.Component {
+		render() {
+			return <div />
+		}
+	}
+
+	export default function () {
+		return <div />
+	}
+	`
+
+	// files cut off anywhere, as when saved midway, are reported
+	// without stopping the extraction of other files
+	for length := 0; length <= len(code); length++ {
+		assert.NotPanics(t, func() {
+			getComponents(t, code[:length])
+		}, "file truncated to %d characters", length)
+	}
+
+	// components before the point the file is cut at are still found
+	components := getComponents(t, code[:strings.Index(code, "export const Menu")] + "export class")
+	assert.Equal(t, 2, len(components))
+	assert.Equal(t, "Button", components[0].Name)
+	assert.Equal(t, "Icon", components[1].Name)
+}
+
+func TestTypescriptFilesWithoutJsx(t *testing.T) {
+	// generic arrow functions and `<Type>` casts are only valid in
+	// `.ts` files, where they are not mistaken for JSX elements
+	code := `
+	export const identity = <T>(value: T): T => value;
+	export const size = <number>(window as any).size;
+	`
+
+	sourceFile, _, diagnostics := ast.ParseFileContents("utils.ts", code, &ast.ParseOptions{Parser: currentParser})
+	if sourceFile == nil {
+		t.Fatalf("cannot parse with the %s parser: %v", currentParser, diagnostics)
+	}
+	assert.False(t, ast.HasErrors(diagnostics))
+	assert.Equal(t, 2, len(sourceFile.Statements))
+
+	_, _, diagnostics = ast.ParseFileContents("utils.tsx", code, &ast.ParseOptions{Parser: currentParser})
+	assert.True(t, ast.HasErrors(diagnostics))
+}
+
+func TestSourceLocations(t *testing.T) {
+	code := `import React from 'react';
+	interface AlertProps {
+		/**
+		 * the message to show
+		 */
+		message: string;
+		kind?: string;
+	}
+
+	/**
+	 * Shows an alert
+	 */
+	export class Alert extends React.Component<AlertProps> {
+		render() {
+			return null;
+		}
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 13, component.Line)
+	assert.Equal(t, 17, component.EndLine)
+	assert.Equal(t, "index.tsx", component.SourceFile)
+
+	assert.Equal(t, 2, len(component.Props))
+	assert.Equal(t, 6, component.Props[0].Line)
+	assert.Equal(t, 6, component.Props[0].EndLine)
+	assert.Equal(t, 7, component.Props[1].Line)
+}
+
+func TestInheritedProps(t *testing.T) {
+	code := `import React from 'react';
+	interface BaseProps<T> {
+		/**
+		 * the value of the field
+		 */
+		value?: T;
+		disabled?: boolean;
+	}
+
+	interface ButtonProps extends BaseProps<string>, React.ButtonHTMLAttributes<HTMLButtonElement> {
+		label: string;
+		disabled: boolean;
+	}
+
+	export class Button extends React.Component<ButtonProps> {
+		render() {
+			return null;
+		}
+	}
+	`
+
+	components := getComponents(t, code)
+	assert.True(t, len(components) == 1)
+
+	component := components[0]
+	assert.Equal(t, 4, len(component.Props))
+
+	prop := component.Props[0]
+	assert.Equal(t, "label", prop.Name)
+	assert.Equal(t, "", prop.InheritedFrom)
+
+	// own declaration wins over the inherited one
+	prop = component.Props[1]
+	assert.Equal(t, "disabled", prop.Name)
+	assert.Equal(t, true, prop.Required)
+
+	prop = component.Props[2]
+	assert.Equal(t, "value", prop.Name)
+	assert.Equal(t, "string", prop.PropType)
+	assert.Equal(t, "BaseProps<string>", prop.InheritedFrom)
+	assert.Equal(t, "the value of the field", prop.Description)
+
+	prop = component.Props[3]
+	assert.Equal(t, true, prop.Collapsed)
+	assert.Equal(t, "React.ButtonHTMLAttributes<HTMLButtonElement>", prop.PropType)
+	assert.Equal(t, "Inherits all `<button>` attributes", prop.Description)
+}
+
+// The parser to run the tests with, as passed with `-args -parser=go`.
+// All tests run once for each parser when no parser is given, so that
+// every parser is checked to read components the same way.
+var parserFlag = flag.String("parser", "", "the parser to run the tests with, `typescript` or `go`, all parsers when empty")
+
+// The parser the tests are currently running with
+var currentParser string
+
+func TestConfigValidation(t *testing.T) {
+	folder := t.TempDir()
+	contents := `{
+	"src": {
+		"root": "src",
+		"includes": ["**/*.tsx"],
+		"exclud": []
+	},
+	"build": {
+		"css": "theme.css"
+	}
+}`
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(contents), 0644)
+
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 3, len(diagnostics))
+
+	// unknown keys are warnings, with the likely key
+	assert.Equal(t, ast.SEVERITY_WARNING, diagnostics[0].Severity)
+	assert.Equal(t, 5, diagnostics[0].Line)
+	assert.Contains(t, diagnostics[0].Message, "did you mean `excludes`")
+
+	// values of the wrong type are errors
+	assert.Equal(t, ast.SEVERITY_ERROR, diagnostics[1].Severity)
+	assert.Equal(t, 8, diagnostics[1].Line)
+	assert.Contains(t, diagnostics[1].Message, "build.css")
+
+	// and the paths are checked all the same
+	assert.Equal(t, ast.SEVERITY_ERROR, diagnostics[2].Severity)
+	assert.Contains(t, diagnostics[2].Message, "`src.root` refers to src")
+
+	// missing paths are reported on their own
+	contents = `{ "src": { "root": "src" } }`
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(contents), 0644)
+
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Contains(t, diagnostics[0].Message, "src.root")
+
+	os.Mkdir(filepath.Join(folder, "src"), 0755)
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.NotNil(t, config)
+	assert.Equal(t, 0, len(diagnostics))
+}
+
+func TestConfigTarget(t *testing.T) {
+	folder := t.TempDir()
+	os.Mkdir(filepath.Join(folder, "src"), 0755)
+
+	// aliases of targets are known
+	os.WriteFile(filepath.Join(folder, "tsconfig.json"), []byte(`{ "compilerOptions": { "target": "ES6" } }`), 0644)
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.NotNil(t, config)
+	assert.Equal(t, 0, len(diagnostics))
+
+	// while unknown targets are reported where they were read from
+	os.WriteFile(filepath.Join(folder, "tsconfig.json"), []byte(`{ "compilerOptions": { "target": "es202" } }`), 0644)
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.NotNil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, ast.SEVERITY_WARNING, diagnostics[0].Severity)
+	assert.Equal(t, "tsconfig.json", diagnostics[0].File)
+	assert.Contains(t, diagnostics[0].Message, "unknown `target` es202")
+}
+
+func TestConfigParser(t *testing.T) {
+	folder := t.TempDir()
+	os.Mkdir(filepath.Join(folder, "src"), 0755)
+
+	// unknown parsers are errors, with the likely parser
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(`{ "parser": "gopher" }`), 0644)
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, ast.SEVERITY_ERROR, diagnostics[0].Severity)
+	assert.Equal(t, "unknown `parser` gopher, expected `typescript` or `go`", diagnostics[0].Message)
+
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(`{ "parser": "typscript" }`), 0644)
+	_, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Equal(t, "unknown `parser` typscript, did you mean `typescript`?", diagnostics[0].Message)
+
+	// the type checker is ignored by the go parser
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(`{ "parser": "go", "typeChecker": true }`), 0644)
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.NotNil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, ast.SEVERITY_WARNING, diagnostics[0].Severity)
+	assert.Equal(t, "`typeChecker` is ignored, as the `go` parser cannot check types", diagnostics[0].Message)
+
+	// while a target that is not known is reported along with errors
+	os.WriteFile(filepath.Join(folder, "redefine.config.json"), []byte(`{ "parser": 5, "target": "es202" }`), 0644)
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 2, len(diagnostics))
+	assert.Contains(t, diagnostics[0].Message, "`parser`")
+	assert.Contains(t, diagnostics[1].Message, "unknown `target` es202")
+}
+
+func TestConfigSources(t *testing.T) {
+	folder := t.TempDir()
+	os.Mkdir(filepath.Join(folder, "src"), 0755)
+	os.Mkdir(filepath.Join(folder, "lib"), 0755)
+
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{ "redefine": { "parser": "go", "target": "es2017" } }`), 0644)
+	os.WriteFile(filepath.Join(folder, "team.toml"), []byte(`
+# shared across projects
+typeChecker = true
+target = "es2019"
+
+[limits]
+parseTimeout = 10
+memory = 512
+`), 0644)
+	os.WriteFile(filepath.Join(folder, "redefine.config.yaml"), []byte(`
+extends: ./team.toml
+src:
+  root: src
+limits:
+  memory: 256
+`), 0644)
+
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{
+		BaseFolder:  folder,
+		Environment: []string{"REDEFINE_LIMITS_PARSE_TIMEOUT=20", "REDEFINE_SRC_ROOT=lib", "HOME=/"},
+		Overrides:   []string{"src.root=src", "typeChecker=false"},
+	})
+	assert.Equal(t, 0, len(diagnostics))
+
+	// package.json < extended file < configuration file < environment < overrides
+	assert.Equal(t, "go", config.Parser)
+	assert.Equal(t, "es2019", config.Target)
+	assert.Equal(t, uint32(256), config.Limits.Memory)
+	assert.Equal(t, 20, config.Limits.ParseTimeout)
+	assert.Equal(t, filepath.Join(folder, "src"), config.SrcFolder.Root)
+	assert.False(t, config.TypeChecker)
+
+	// problems in YAML files are reported where they are written
+	os.WriteFile(filepath.Join(folder, "redefine.config.yaml"), []byte(`src:
+  root: src
+  includes: yes
+`), 0644)
+
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, 3, diagnostics[0].Line)
+	assert.Equal(t, 13, diagnostics[0].Column)
+
+	// while problems in TOML files are reported against the file, as
+	// the decoder does not tell where values are written
+	os.WriteFile(filepath.Join(folder, "redefine.config.toml"), []byte(`[src]
+root = "src"
+unknown = 'value'
+`), 0644)
+
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder, ConfigFile: filepath.Join(folder, "redefine.config.toml")})
+	assert.NotNil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, filepath.Join(folder, "redefine.config.toml"), diagnostics[0].File)
+	assert.Equal(t, 0, diagnostics[0].Line)
+	assert.Contains(t, diagnostics[0].Message, "src.unknown")
+
+	// other than syntax errors
+	os.WriteFile(filepath.Join(folder, "redefine.config.toml"), []byte("[src]\nroot = \"src\n"), 0644)
+
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder, ConfigFile: filepath.Join(folder, "redefine.config.toml")})
+	assert.Nil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, 2, diagnostics[0].Line)
+	assert.Equal(t, 12, diagnostics[0].Column)
+	assert.Contains(t, diagnostics[0].Message, "invalid TOML")
+}
+
+func TestInitProject(t *testing.T) {
+	folder := t.TempDir()
+	os.MkdirAll(filepath.Join(folder, "lib", "menu"), 0755)
+	os.MkdirAll(filepath.Join(folder, "docs"), 0755)
+
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{ "name": "demo", "description": "Demo components" }`), 0644)
+	os.WriteFile(filepath.Join(folder, "lib", "Button.tsx"), []byte(`
+	export function Button() {
+		return <button>Click</button>
+	}
+	`), 0644)
+	os.WriteFile(filepath.Join(folder, "lib", "menu", "Item.tsx"), []byte(`
+	export function Item() {
+		return <li>Item</li>
+	}
+	`), 0644)
+	os.WriteFile(filepath.Join(folder, "docs", "Button.md"), []byte("# Button\n"), 0644)
+
+	app := &core.RedefineApp{BaseFolder: folder, RunMode: "init"}
+	diagnostics, err := app.InitProject(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(diagnostics))
+
+	// the configuration is what was found in the project
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Equal(t, 0, len(diagnostics))
+	assert.Equal(t, filepath.Join(folder, "lib"), config.SrcFolder.Root)
+	assert.Equal(t, []string{"*.ts", "*.tsx"}, config.SrcFolder.Includes)
+
+	index, _ := os.ReadFile(filepath.Join(folder, "docs", "index.md"))
+	assert.Equal(t, "# demo\n\nDemo components\n", string(index))
+
+	// only components without documentation get a page
+	stub, _ := os.ReadFile(filepath.Join(folder, "docs", "menu", "Item.md"))
+	assert.Contains(t, string(stub), "<Item />")
+
+	button, _ := os.ReadFile(filepath.Join(folder, "docs", "Button.md"))
+	assert.Equal(t, "# Button\n", string(button))
+}
+
+func TestInitThenBuild(t *testing.T) {
+	folder := t.TempDir()
+	os.MkdirAll(filepath.Join(folder, "lib"), 0755)
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{ "name": "demo", "description": "Demo components" }`), 0644)
+	os.WriteFile(filepath.Join(folder, "lib", "Button.tsx"), []byte(`
+	export function Button() {
+		return <button>Click</button>
+	}
+	`), 0644)
+
+	app := &core.RedefineApp{BaseFolder: folder, RunMode: "init"}
+	_, err := app.InitProject(context.Background())
+	assert.Nil(t, err)
+
+	// the index written by init is what the build documents the library with
+	options := core.ConfigOptions{BaseFolder: folder, Overrides: []string{"parser=" + currentParser}}
+	config, diagnostics := core.GetRedefineConfig(options)
+	assert.Equal(t, 0, len(diagnostics))
+	assert.Equal(t, filepath.Join(folder, "docs", "index.md"), config.DocsFolder.Index)
+
+	app = &core.RedefineApp{BaseFolder: folder, Config: config, RunMode: "build"}
+	jsonBytes, _, err := extractComponents(t, app)
+	assert.Nil(t, err)
+
+	var payload struct {
+		Index string `json:"libDocs"`
+	}
+	json.Unmarshal(jsonBytes, &payload)
+	assert.Equal(t, "# demo\n\nDemo components\n", payload.Index)
+
+	written, _ := os.ReadFile(filepath.Join(folder, "components.json"))
+	assert.Equal(t, string(jsonBytes), string(written))
+}
+
+func TestBuildWriteError(t *testing.T) {
+	folder := t.TempDir()
+	os.MkdirAll(filepath.Join(folder, "src"), 0755)
+
+	// components.json cannot be written over a folder
+	os.MkdirAll(filepath.Join(folder, "components.json"), 0755)
+
+	options := core.ConfigOptions{BaseFolder: folder, Overrides: []string{"parser=" + currentParser}}
+	config, _ := core.GetRedefineConfig(options)
+	assert.NotNil(t, config)
+
+	app := &core.RedefineApp{BaseFolder: folder, Config: config, RunMode: "build"}
+	jsonBytes, _, err := extractComponents(t, app)
+	assert.Nil(t, jsonBytes)
+
+	var pathError *fs.PathError
+	if assert.ErrorAs(t, err, &pathError) {
+		assert.Equal(t, filepath.Join(folder, "components.json"), pathError.Path)
+	}
+}
+
+func TestPackageJson(t *testing.T) {
+	var packageJson core.PackageJson
+	err := json.Unmarshal([]byte(`{
+		"name": "@acme/ui",
+		"homepage": "https://acme.dev",
+		"author": "Jane Doe <jane@acme.dev> (https://jane.dev)",
+		"contributors": ["John", { "name": "Jim", "email": "jim@acme.dev" }],
+		"license": { "type": "MIT" },
+		"bugs": "https://github.com/acme/ui/issues",
+		"keywords": ["react", "ui"],
+		"peerDependencies": { "react": ">=17" },
+		"repository": "github:acme/ui"
+	}`), &packageJson)
+	assert.Nil(t, err)
+
+	// the shorthand forms are read as objects
+	assert.Equal(t, core.PackageAuthor{Name: "Jane Doe", Email: "jane@acme.dev", Url: "https://jane.dev"}, packageJson.Author)
+	assert.Equal(t, []core.PackageAuthor{{Name: "John"}, {Name: "Jim", Email: "jim@acme.dev"}}, packageJson.Contributors)
+	assert.Equal(t, "https://github.com/acme/ui/issues", packageJson.Bugs.Url)
+	assert.Equal(t, "github:acme/ui", packageJson.Repository.Url)
+
+	// and fields that are not understood do not lose the others
+	assert.Equal(t, "", packageJson.License)
+	assert.Equal(t, "@acme/ui", packageJson.Name)
+	assert.Equal(t, "https://acme.dev", packageJson.HomePage)
+	assert.Equal(t, []string{"react", "ui"}, packageJson.Keywords)
+	assert.Equal(t, map[string]string{"react": ">=17"}, packageJson.PeerDependencies)
+
+	// the fields that are ignored are warned of where they are written
+	folder := t.TempDir()
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{
+	"name": "@acme/ui",
+	"license": { "type": "MIT" },
+	"keywords": "react",
+	"redefine": { "parser": "go" }
+}`), 0644)
+
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.NotNil(t, config)
+	assert.Equal(t, 2, len(diagnostics))
+	assert.Equal(t, ast.SEVERITY_WARNING, diagnostics[0].Severity)
+	assert.Equal(t, filepath.Join(folder, "package.json"), diagnostics[0].File)
+	assert.Equal(t, 3, diagnostics[0].Line)
+	assert.Equal(t, 13, diagnostics[0].Column)
+	assert.Equal(t, "`license` is ignored, as it cannot be read from an object", diagnostics[0].Message)
+	assert.Equal(t, "`keywords` is ignored, as it cannot be read from the string \"react\"", diagnostics[1].Message)
+
+	// while problems of the redefine configuration are errors, reported once
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{ "name": "@acme/ui", "redefine": { "parser": 5 } }`), 0644)
+
+	config, diagnostics = core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder})
+	assert.Nil(t, config)
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, ast.SEVERITY_ERROR, diagnostics[0].Severity)
+	assert.Contains(t, diagnostics[0].Message, "`redefine.parser`")
+}
+
+func TestWorkspaces(t *testing.T) {
+	folder := t.TempDir()
+	for _, name := range []string{"button", "nested/menu", "internal"} {
+		os.MkdirAll(filepath.Join(folder, "packages", name, "src"), 0755)
+		os.WriteFile(filepath.Join(folder, "packages", name, "package.json"), []byte(`{ "name": "@acme/`+filepath.Base(name)+`", "version": "1.0.0" }`), 0644)
+	}
+
+	os.WriteFile(filepath.Join(folder, "package.json"), []byte(`{ "name": "acme" }`), 0644)
+	os.WriteFile(filepath.Join(folder, "pnpm-workspace.yaml"), []byte("packages:\n  - packages/**\n  - '!packages/internal'\n"), 0644)
+	os.WriteFile(filepath.Join(folder, "packages", "button", "src", "Button.tsx"), []byte(`
+	export function Button() {
+		return <button>Click</button>
+	}
+	`), 0644)
+	os.WriteFile(filepath.Join(folder, "packages", "nested", "menu", "src", "Item.tsx"), []byte(`
+	export function Item() {
+		return <li>Item</li>
+	}
+	`), 0644)
+	os.MkdirAll(filepath.Join(folder, "packages", "button", "docs"), 0755)
+	os.WriteFile(filepath.Join(folder, "packages", "button", "docs", "index.md"), []byte("# Buttons\n"), 0644)
+
+	// all packages are documented together, with their own configuration
+	options := core.ConfigOptions{BaseFolder: folder, Overrides: []string{"parser=" + currentParser}}
+	config, diagnostics := core.GetRedefineConfig(options)
+	assert.Equal(t, 0, len(diagnostics))
+
+	app := &core.RedefineApp{BaseFolder: folder, Config: config}
+	jsonBytes, diagnostics, err := extractComponents(t, app)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(diagnostics))
+
+	var payload struct {
+		Components []model.Component `json:"components"`
+		Packages   []struct {
+			Name   string `json:"name"`
+			Folder string `json:"folder"`
+			Index  string `json:"libDocs"`
+		} `json:"packages"`
+	}
+	json.Unmarshal(jsonBytes, &payload)
+
+	assert.Equal(t, 2, len(payload.Components))
+	assert.Equal(t, "@acme/button/Button", payload.Components[0].Id)
+	assert.Equal(t, "@acme/button", payload.Components[0].Package)
+	assert.Equal(t, "@acme/menu/Item", payload.Components[1].Id)
+	assert.Equal(t, 2, len(payload.Packages))
+	assert.Equal(t, "packages/nested/menu", payload.Packages[1].Folder)
+
+	// with the index of the docs folder of each package
+	assert.Equal(t, "# Buttons\n", payload.Packages[0].Index)
+	assert.Equal(t, "", payload.Packages[1].Index)
+
+	// a single package is read as if it was not part of the workspace
+	options.Package = "@acme/menu"
+	config, diagnostics = core.GetRedefineConfig(options)
+	assert.Equal(t, 0, len(diagnostics))
+	assert.Equal(t, filepath.Join(folder, "packages", "nested", "menu", "src"), config.SrcFolder.Root)
+
+	// or by its folder, however it is written
+	for _, folderName := range []string{"packages/nested/menu", "./packages/nested/menu", "packages/nested/menu/"} {
+		options.Package = folderName
+		config, diagnostics = core.GetRedefineConfig(options)
+		assert.Equal(t, 0, len(diagnostics), folderName)
+		assert.Equal(t, filepath.Join(folder, "packages", "nested", "menu", "src"), config.SrcFolder.Root)
+	}
+
+	options.Package = "@acme/manu"
+	config, diagnostics = core.GetRedefineConfig(options)
+	assert.Nil(t, config)
+	assert.Contains(t, diagnostics[0].Message, "did you mean `@acme/menu`?")
+}
+
+func TestComponentIds(t *testing.T) {
+	folder := t.TempDir()
+	files := map[string]string{
+		"src/Button.tsx":     "export function Button() {\n\treturn <button>Click</button>\n}\n",
+		"src/list/Item.tsx":  "export function Item() {\n\treturn <li>Item</li>\n}\n",
+		"src/list/Items.tsx": "export function Item() {\n\treturn <li>Other</li>\n}\n",
+		"src/menu/Item.tsx":  "export function Item() {\n\treturn <li>Menu item</li>\n}\n",
+		"src/menu/Menu.tsx":  "import { Item } from './Item';\n\nexport function Menu() {\n\treturn <ul></ul>\n}\n\nMenu.Item = Item;\n",
+		"docs/menu/Item.md":  "menu item docs",
+		"docs/Menu.md":       "menu docs",
+		"docs/Item.md":       "item docs",
+		"package.json":       `{ "name": "acme" }`,
+	}
+	for name, contents := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(folder, name)), 0755)
+		os.WriteFile(filepath.Join(folder, name), []byte(contents), 0644)
+	}
+
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder, Overrides: []string{"parser=" + currentParser}})
+	assert.Equal(t, 0, len(diagnostics))
+
+	app := &core.RedefineApp{BaseFolder: folder, Config: config}
+	jsonBytes, diagnostics, err := extractComponents(t, app)
+	assert.Nil(t, err)
+
+	var payload struct {
+		Components []model.Component `json:"components"`
+	}
+	json.Unmarshal(jsonBytes, &payload)
+
+	ids := []string{}
+	for _, component := range payload.Components {
+		ids = append(ids, component.Id)
+	}
+
+	// components declared twice in the same folder get a suffix
+	assert.Equal(t, []string{"Button", "list/Item", "list/Item~2", "menu/Item", "menu/Menu"}, ids)
+	assert.Equal(t, 2, len(diagnostics))
+	assert.Equal(t, "component list/Item is declared more than once in the same folder", diagnostics[0].Message)
+	assert.Equal(t, "component name Item is used by multiple components: list/Item, list/Item~2, menu/Item", diagnostics[1].Message)
+
+	// docs are read by id, and by name for components with unique names
+	components := payload.Components
+	assert.Equal(t, "", components[1].Docs)
+	assert.Equal(t, "menu item docs", components[3].Docs)
+	assert.Equal(t, "menu docs", components[4].Docs)
+
+	// sub-components are linked to the component imported, by id
+	assert.Equal(t, "", components[1].ParentId)
+	assert.Equal(t, "Menu", components[3].Parent)
+	assert.Equal(t, "menu/Menu", components[3].ParentId)
+	assert.Equal(t, 1, len(components[4].SubComponents))
+	assert.Equal(t, "menu/Item", components[4].SubComponents[0].ComponentId)
+}
+
+func TestSourceUrls(t *testing.T) {
+	root := t.TempDir()
+	folder := filepath.Join(root, "app")
+	files := map[string]string{
+		// installed in the project, and hoisted above it
+		"app/node_modules/@acme/theme/package.json": `{ "name": "@acme/theme", "types": "index.d.ts" }`,
+		"app/node_modules/@acme/theme/index.d.ts":   "export interface ThemeProps {\n\ttheme?: string;\n}\n",
+		"node_modules/@acme/base/package.json":      `{ "name": "@acme/base", "types": "index.d.ts" }`,
+		"node_modules/@acme/base/index.d.ts":        "export interface BaseProps {\n\tid?: string;\n}\n",
+		"app/package.json":                          `{ "name": "acme", "repository": "https://github.com/acme/ui", "redefine": { "dependencies": { "resolveTypes": true } } }`,
+		"app/src/Button.tsx": `import { BaseProps } from '@acme/base';
+import { ThemeProps } from '@acme/theme';
+
+interface ButtonProps extends BaseProps, ThemeProps {
+	label: string;
+}
+
+export function Button(props: ButtonProps) {
+	return <button>{props.label}</button>
+}
+`,
+	}
+	for name, contents := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0755)
+		os.WriteFile(filepath.Join(root, name), []byte(contents), 0644)
+	}
+
+	config, diagnostics := core.GetRedefineConfig(core.ConfigOptions{BaseFolder: folder, Overrides: []string{"parser=" + currentParser}})
+	assert.Equal(t, 0, len(diagnostics))
+
+	app := &core.RedefineApp{BaseFolder: folder, Config: config}
+	jsonBytes, diagnostics, err := extractComponents(t, app)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(diagnostics))
+
+	var payload struct {
+		Components []model.Component `json:"components"`
+	}
+	json.Unmarshal(jsonBytes, &payload)
+	assert.Equal(t, 1, len(payload.Components))
+
+	button := payload.Components[0]
+	assert.Equal(t, "https://github.com/acme/ui/blob/main/src/Button.tsx#L8-L10", button.SourceUrl)
+
+	props := map[string]model.PropDef{}
+	for _, prop := range button.Props {
+		props[prop.Name] = prop
+	}
+	assert.Equal(t, 3, len(props))
+	assert.Equal(t, "https://github.com/acme/ui/blob/main/src/Button.tsx#L5-L5", props["label"].SourceUrl)
+
+	// props declared by installed packages have no link
+	assert.Equal(t, "node_modules/@acme/theme/index.d.ts", props["theme"].SourceFile)
+	assert.Equal(t, "", props["theme"].SourceUrl)
+	assert.Equal(t, filepath.Join(root, "node_modules", "@acme", "base", "index.d.ts"), props["id"].SourceFile)
+	assert.Equal(t, "", props["id"].SourceUrl)
+}
+
+func TestSchemas(t *testing.T) {
+	var schema map[string]any
+
+	bytes, err := core.GetComponentsSchema()
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(bytes, &schema))
+
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, float64(core.COMPONENTS_SCHEMA_VERSION), properties["schemaVersion"].(map[string]any)["const"])
+	assert.Equal(t, "#/$defs/Component", properties["components"].(map[string]any)["items"].(map[string]any)["$ref"])
+
+	// props refer to their own definition for their shape
+	defs := schema["$defs"].(map[string]any)
+	propDef := defs["PropDef"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, "#/$defs/PropDef", propDef["shape"].(map[string]any)["items"].(map[string]any)["$ref"])
+
+	bytes, err = core.GetConfigSchema()
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(bytes, &schema))
+
+	properties = schema["properties"].(map[string]any)
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Contains(t, properties, "src")
+	assert.Contains(t, properties, "$schema")
+	assert.Equal(t, map[string]any{"type": "boolean"}, properties["typeChecker"])
+}
+
+func TestComponentTypeJson(t *testing.T) {
+	bytes, err := json.Marshal([]model.ComponentType{model.REACT_CLASS_COMPONENT, model.REACT_FUNCTION_COMPONENT})
+	assert.Nil(t, err)
+	assert.Equal(t, `["class","function"]`, string(bytes))
+
+	// numbers written by earlier versions are still read
+	var componentTypes []model.ComponentType
+	assert.Nil(t, json.Unmarshal([]byte(`["function", 0]`), &componentTypes))
+	assert.Equal(t, []model.ComponentType{model.REACT_FUNCTION_COMPONENT, model.REACT_CLASS_COMPONENT}, componentTypes)
+
+	declarations := core.GetTypeDeclarations()
+	assert.Contains(t, declarations, "interface ComponentDef {\n")
+	assert.Contains(t, declarations, `    componentType: "class" | "function";`)
+	assert.Contains(t, declarations, "    docFileName: string;")
+	assert.Contains(t, declarations, "    props: Array<PropDef> | null;")
+	assert.Contains(t, declarations, "    $schema: string;")
+}
+
+func TestParseArguments(t *testing.T) {
+	cwd, _ := os.Getwd()
+
+	app, err := parseArguments([]string{"redefine", "build", "docs", "--strict", "--set", "src.root=lib", "--set=title=Acme"})
+	assert.Nil(t, err)
+	assert.Equal(t, "build", app.RunMode)
+	assert.Equal(t, "docs", app.BaseFolder)
+	assert.True(t, app.Strict)
+	assert.Equal(t, []string{"src.root=lib", "title=Acme"}, app.Overrides)
+
+	// a single argument is the folder, unless it is an action
+	app, err = parseArguments([]string{"redefine", "docs"})
+	assert.Nil(t, err)
+	assert.Equal(t, "serve", app.RunMode)
+	assert.Equal(t, "docs", app.BaseFolder)
+
+	app, err = parseArguments([]string{"redefine", "init", "--quiet"})
+	assert.Nil(t, err)
+	assert.Equal(t, "init", app.RunMode)
+	assert.Equal(t, cwd, app.BaseFolder)
+
+	app, err = parseArguments([]string{"redefine"})
+	assert.Nil(t, err)
+	assert.Equal(t, cwd, app.BaseFolder)
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--stirct"}, "unknown flag --stirct, did you mean --strict?"},
+		{[]string{"--log-fromat", "json"}, "unknown flag --log-fromat, did you mean --log-format?"},
+		{[]string{"--unknown"}, "unknown flag --unknown"},
+		{[]string{"--strict=false"}, "--strict does not take a value"},
+		{[]string{"--config"}, "missing value for --config"},
+		{[]string{"biuld", "docs"}, "unknown action biuld, did you mean build?"},
+		{[]string{"types"}, "missing the file to write the Typescript declarations to"},
+		{[]string{"publish", "docs"}, "the publish action is not implemented yet"},
+		{[]string{"publish"}, "the publish action is not implemented yet"},
+		{[]string{"build", "docs", "more"}, "too many arguments"},
+	}
+
+	for _, test := range tests {
+		app, err := parseArguments(append([]string{"redefine"}, test.args...))
+		assert.Nil(t, app)
+		assert.EqualError(t, err, test.expected)
+	}
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	parsers := []string{ast.PARSER_TYPESCRIPT, ast.PARSER_GO}
+	if *parserFlag != "" {
+		parsers = []string{*parserFlag}
+	}
+
+	exitCode := 0
+	for _, parser := range parsers {
+		currentParser = parser
+		fmt.Println("Running tests with the " + parser + " parser")
+
+		if code := m.Run(); code != 0 {
+			exitCode = code
+		}
+	}
+
+	os.Exit(exitCode)
+}
+
+// Extract and write the components of the app, failing the test if
+// the parser cannot be created, which is reported without a file.
+func extractComponents(t *testing.T, app *core.RedefineApp) ([]byte, []ast.Diagnostic, error) {
+	jsonBytes, diagnostics, err := app.ExtractAndWriteComponents(context.Background())
+	for _, diagnostic := range diagnostics {
+		if diagnostic.File == "" && diagnostic.Severity == ast.SEVERITY_ERROR {
+			t.Fatalf("cannot extract with the %s parser: %s", currentParser, diagnostic.Message)
+		}
+	}
+
+	return jsonBytes, diagnostics, err
+}
+
+// Return the components of the code, failing the test if it cannot
+// be parsed, such as when the parser cannot be created.
+func getComponents(t *testing.T, code string) []model.Component {
+	sourceFile, syntaxKind, diagnostics := ast.ParseFileContents("index.tsx", code, &ast.ParseOptions{Parser: currentParser})
+	if sourceFile == nil {
+		t.Fatalf("cannot parse with the %s parser: %v", currentParser, diagnostics)
+	}
+
 	components := model.GetComponentsFromSourceFile(sourceFile, syntaxKind, "testComponent.go", "in-memory/testing")
 
 	// jsonStr, _ := json.MarshalIndent(components, "", "  ")
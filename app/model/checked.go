@@ -0,0 +1,76 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package model
+
+import (
+	"strings"
+
+	"sangupta.com/redefine/ast"
+)
+
+/**
+ * Refine the props of the component with the props resolved by the
+ * Typescript type checker, when it was run. Types resolved by the
+ * checker replace the types as written in code, except for enums,
+ * shapes and functions whose details have already been read. Props
+ * that could only be found by the checker are added, leaving out
+ * DOM attributes unless they are to be expanded.
+ */
+func (ex *extraction) applyCheckedProps(component *Component, checkedProps []ast.CheckedProp) {
+	if len(checkedProps) == 0 {
+		return
+	}
+
+	propIndex := make(map[string]int, len(component.Props))
+	collapsed := false
+	for index, prop := range component.Props {
+		propIndex[prop.Name] = index
+		collapsed = collapsed || prop.Collapsed
+	}
+
+	for _, checkedProp := range checkedProps {
+		index, exists := propIndex[checkedProp.Name]
+		if exists {
+			prop := &component.Props[index]
+			if !strings.HasPrefix(prop.PropType, "$") {
+				prop.PropType = checkedProp.Type
+			}
+
+			prop.Required = !checkedProp.Optional
+			if prop.Description == "" {
+				prop.Description = checkedProp.Description
+			}
+
+			continue
+		}
+
+		// leave out DOM attributes, which are already collapsed or are
+		// to be collapsed, as no declaration was found for them in code
+		isDomAttribute := domAttributesPattern.MatchString(checkedProp.DeclaredIn)
+		if isDomAttribute && (collapsed || !ex.options.ExpandDomAttributes) {
+			continue
+		}
+
+		component.Props = append(component.Props, PropDef{
+			Name:          checkedProp.Name,
+			PropType:      checkedProp.Type,
+			Required:      !checkedProp.Optional,
+			Description:   checkedProp.Description,
+			IsEvent:       isEventPropName(checkedProp.Name),
+			InheritedFrom: checkedProp.DeclaredIn,
+			SourceFile:    checkedProp.FileName,
+		})
+	}
+
+	component.Events = getEventProps(component.Props)
+}
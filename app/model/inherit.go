@@ -0,0 +1,222 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package model
+
+import (
+	"regexp"
+	"strings"
+
+	"sangupta.com/redefine/ast"
+)
+
+// This file contains functions to read the props a props interface
+// inherits from the interfaces it extends, such as `BaseProps` or
+// `React.ButtonHTMLAttributes<HTMLButtonElement>`.
+
+// The maximum depth of interfaces extending other interfaces
+const maxInheritanceDepth = 16
+
+// Interfaces that declare the attributes of DOM elements, which run
+// into hundreds of props and are collapsed into a single entry
+var domAttributesPattern = regexp.MustCompile(`^(\w*HTMLAttributes|\w*SVGAttributes|AriaAttributes|DOMAttributes|ComponentProps|ComponentPropsWithRef|ComponentPropsWithoutRef)$`)
+
+// Element names that do not follow from the name of the interface
+// or the DOM element type, such as `HTMLAnchorElement`
+var domElementNames = map[string]string{
+	"anchor":    "a",
+	"image":     "img",
+	"paragraph": "p",
+	"olist":     "ol",
+	"ulist":     "ul",
+	"tablecell": "td",
+	"tablerow":  "tr",
+	"quote":     "blockquote",
+	"heading":   "h1",
+	"mod":       "del",
+	"tablecol":  "col",
+	"dlist":     "dl",
+}
+
+/**
+ * Read the props inherited by the props interface from the interfaces
+ * it extends. Props already present, either declared by the interface
+ * itself or inherited earlier, are not repeated.
+ *
+ * @param source the source file declaring the interface
+ *
+ * @param declaration the props interface declaration
+ *
+ * @param propDefaultValueMap a `map` of default values for props
+ *
+ * @param scope the type parameters in scope for the interface
+ *
+ * @param names the names of props already read
+ */
+func (ex *extraction) getInheritedProps(source *ast.SourceFile, declaration *ast.Statement, propDefaultValueMap map[string]string, scope *typeScope, names map[string]bool, depth int) []PropDef {
+	if depth >= maxInheritanceDepth {
+		return nil
+	}
+
+	props := make([]PropDef, 0)
+	for _, clause := range declaration.HeritageClauses {
+		for index := range clause.Types {
+			heritage := &clause.Types[index]
+			typeText := ex.getHeritageTypeText(heritage, scope)
+
+			// collapse the attributes of DOM elements into a single entry
+			if !ex.options.ExpandDomAttributes && ex.isDomAttributesType(heritage) {
+				if !names[typeText] {
+					names[typeText] = true
+					props = append(props, ex.getCollapsedDomAttributesProp(heritage, typeText))
+				}
+				continue
+			}
+
+			parentSource, parent := source.FindInterfaceOfExpression(heritage.Expression)
+			if parent == nil {
+				continue
+			}
+
+			parentScope := ex.newInheritedTypeScope(scope, parent.TypeParameters, heritage.TypeArguments)
+			for _, member := range parent.Members {
+				if member.Name == nil || names[member.Name.EscapedText] {
+					continue
+				}
+				names[member.Name.EscapedText] = true
+
+				prop := ex.getComponentProp(member, propDefaultValueMap, parentScope)
+				prop.InheritedFrom = typeText
+				setPropLocation(prop, parentSource, member.Pos, member.End)
+				props = append(props, *prop)
+			}
+
+			props = append(props, ex.getInheritedProps(parentSource, parent, propDefaultValueMap, parentScope, names, depth+1)...)
+		}
+	}
+
+	return props
+}
+
+/**
+ * Check if the extended type declares the attributes of DOM
+ * elements, like `React.ButtonHTMLAttributes<HTMLButtonElement>`.
+ */
+func (ex *extraction) isDomAttributesType(heritage *ast.TypeValue) bool {
+	return domAttributesPattern.MatchString(ex.getHeritageTypeName(heritage))
+}
+
+/**
+ * Create the single prop that stands for all inherited attributes
+ * of a DOM element.
+ */
+func (ex *extraction) getCollapsedDomAttributesProp(heritage *ast.TypeValue, typeText string) PropDef {
+	description := "Inherits all DOM attributes"
+	if element := ex.getDomElementName(heritage); element != "" {
+		description = "Inherits all `<" + element + ">` attributes"
+	}
+
+	return PropDef{
+		Name:          "..." + typeText,
+		PropType:      typeText,
+		Description:   description,
+		InheritedFrom: typeText,
+		Collapsed:     true,
+	}
+}
+
+/**
+ * Return the name of the DOM element whose attributes are inherited,
+ * read from the name of the interface like `ButtonHTMLAttributes`, or
+ * from its type argument like `HTMLButtonElement` or `'button'`.
+ */
+func (ex *extraction) getDomElementName(heritage *ast.TypeValue) string {
+	name := ex.getHeritageTypeName(heritage)
+	element := ""
+
+	switch {
+	case strings.HasSuffix(name, "HTMLAttributes") && name != "HTMLAttributes" && name != "AllHTMLAttributes":
+		element = strings.TrimSuffix(name, "HTMLAttributes")
+
+	case strings.HasPrefix(name, "ComponentProps") && len(heritage.TypeArguments) > 0:
+		// `ComponentProps<'button'>`
+		literal := heritage.TypeArguments[0].Literal
+		if literal != nil {
+			return literal.Text
+		}
+
+	case len(heritage.TypeArguments) > 0:
+		// `HTMLAttributes<HTMLDivElement>`
+		argument := ex.syntax.GetTypeText(&heritage.TypeArguments[0])
+		if strings.HasPrefix(argument, "HTML") && strings.HasSuffix(argument, "Element") {
+			element = strings.TrimSuffix(strings.TrimPrefix(argument, "HTML"), "Element")
+		}
+	}
+
+	element = strings.ToLower(element)
+	if mapped, exists := domElementNames[element]; exists {
+		return mapped
+	}
+
+	return element
+}
+
+/**
+ * Return the name of the extended type without any namespace,
+ * such as `ButtonHTMLAttributes` for `React.ButtonHTMLAttributes`.
+ */
+func (ex *extraction) getHeritageTypeName(heritage *ast.TypeValue) string {
+	expr := heritage.Expression
+	if expr == nil {
+		return ""
+	}
+
+	if ex.syntax.IsPropertyAccessExpression(expr) && expr.Name != nil {
+		return expr.Name.EscapedText
+	}
+
+	return expr.EscapedText
+}
+
+/**
+ * Render the extended type as text, such as
+ * `React.ButtonHTMLAttributes<HTMLButtonElement>`.
+ */
+func (ex *extraction) getHeritageTypeText(heritage *ast.TypeValue, scope *typeScope) string {
+	text := ex.getExpressionName(heritage.Expression)
+	if len(heritage.TypeArguments) == 0 {
+		return text
+	}
+
+	arguments := make([]string, 0, len(heritage.TypeArguments))
+	for index := range heritage.TypeArguments {
+		arguments = append(arguments, ex.getTypeText(scope, &heritage.TypeArguments[index]))
+	}
+
+	return text + "<" + strings.Join(arguments, ", ") + ">"
+}
+
+/**
+ * Return the dotted name of an identifier or property access
+ * expression, such as `React.HTMLAttributes`.
+ */
+func (ex *extraction) getExpressionName(expr *ast.Expression) string {
+	if expr == nil {
+		return ""
+	}
+
+	if ex.syntax.IsPropertyAccessExpression(expr) && expr.Name != nil {
+		return ex.getExpressionName(expr.Expression) + "." + expr.Name.EscapedText
+	}
+
+	return expr.EscapedText
+}
@@ -0,0 +1,159 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package model
+
+import (
+	"strings"
+
+	"sangupta.com/redefine/ast"
+)
+
+// This file contains functions to read props from the JSDoc
+// type annotations, as used by plain JSX function components
+// that document their props with `@param` or `@typedef` tags.
+
+/**
+ * Read the props of a function component from its JSDoc comment.
+ * Props are read either from `@param` tags qualified with the
+ * props parameter name, like `@param {'sm'|'md'} props.size`, or
+ * from the `@typedef` that the props parameter refers to, like
+ * `@param {ButtonProps} props`.
+ *
+ * @param source the source file defining the component
+ *
+ * @param functionStatement the function declaration of the component
+ */
+func (ex *extraction) getJsDocProps(source ast.SourceFile, functionStatement *ast.Statement) []PropDef {
+	paramTags := ex.getJsDocParamTags(functionStatement.JsDoc)
+	if len(paramTags) == 0 {
+		return nil
+	}
+
+	// the props parameter is the first parameter of the function,
+	// which may be destructured, in which case we rely on the name
+	// used in the first `@param` tag
+	propsName := ast.GetEntityName(paramTags[0].Name)
+	if len(functionStatement.Parameters) > 0 {
+		param := functionStatement.Parameters[0]
+		if param.Name != nil && param.Name.EscapedText != "" {
+			propsName = param.Name.EscapedText
+		}
+	}
+
+	// find all tags of the form `props.size`
+	prefix := propsName + "."
+	var propTags []ast.JsDocTag
+	var propsTag *ast.JsDocTag
+	for index, tag := range paramTags {
+		name := ast.GetEntityName(tag.Name)
+		if name == propsName {
+			propsTag = &paramTags[index]
+			continue
+		}
+
+		// skip nested names like `props.style.color`
+		if strings.HasPrefix(name, prefix) && !strings.Contains(name[len(prefix):], ".") {
+			propTags = append(propTags, tag)
+		}
+	}
+
+	// check if the props parameter points to a `@typedef`, or has the
+	// `props.size` tags nested within its type, as Typescript does when
+	// the props parameter is declared as an `{Object}`
+	if len(propTags) == 0 && propsTag != nil && propsTag.TypeExpression != nil {
+		typeValue := propsTag.TypeExpression.TypeValue
+		if typeValue != nil && len(typeValue.JsDocPropertyTags) > 0 {
+			propTags = typeValue.JsDocPropertyTags
+		} else if typeValue != nil && ex.syntax.IsTypeReference(typeValue) {
+			typedef := source.GetJsDocTypedef(ast.GetEntityName(typeValue.TypeName))
+			if typedef != nil && typedef.TypeExpression != nil {
+				propTags = typedef.TypeExpression.JsDocPropertyTags
+			}
+		}
+	}
+
+	if len(propTags) == 0 {
+		return nil
+	}
+
+	props := make([]PropDef, 0, len(propTags))
+	for _, tag := range propTags {
+		propDef := ex.getJsDocProp(tag)
+		setPropLocation(&propDef, &source, tag.Pos, tag.End)
+
+		props = append(props, propDef)
+	}
+
+	return props
+}
+
+/**
+ * Return all `@param` tags across the given JSDoc comments.
+ */
+func (ex *extraction) getJsDocParamTags(jsDocs []ast.JsDoc) []ast.JsDocTag {
+	var tags []ast.JsDocTag
+	for _, jsDoc := range jsDocs {
+		for _, tag := range jsDoc.Tags {
+			if ex.syntax.IsJsDocParameterTag(&tag) && tag.Name != nil {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
+/**
+ * Create a prop definition from a `@param` or `@property` tag. The
+ * tag is converted into an interface member so that the type is
+ * read the same way as for Typescript props.
+ */
+func (ex *extraction) getJsDocProp(tag ast.JsDocTag) PropDef {
+	name := ast.GetEntityName(tag.Name)
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		name = name[index+1:]
+	}
+
+	member := ast.Member{
+		Name: &ast.AstObject{EscapedText: name},
+		Kind: ex.syntax.PropertySignature,
+	}
+
+	// `[props.size]` and `{string=}` both denote optional props
+	optional := tag.IsBracketed
+	if tag.TypeExpression != nil {
+		typeValue := tag.TypeExpression.TypeValue
+		if typeValue != nil && ex.syntax.IsJsDocOptionalType(typeValue) {
+			optional = true
+			typeValue = typeValue.TypeValue
+		}
+
+		// read JSDoc function types like Typescript function types
+		if typeValue != nil && ex.syntax.IsJsDocFunctionType(typeValue) {
+			functionType := *typeValue
+			functionType.Kind = ex.syntax.FunctionType
+			typeValue = &functionType
+		}
+
+		member.TypeReference = typeValue
+	}
+
+	if optional {
+		member.QuestionToken = &ast.AstObject{Kind: ex.syntax.QuestionToken}
+	}
+
+	propDef := ex.getComponentProp(member, map[string]string{}, nil)
+	propDef.Description = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag.Comment), "-"))
+
+	return *propDef
+}
@@ -13,15 +13,19 @@ that can be found in LICENSE file in the code repository.
 package model
 
 import (
-	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"sangupta.com/redefine/ast"
 )
 
-var Syntax *ast.SyntaxKind
+// Holds what is needed while extracting components, so that
+// extractions with different parsers or options can run side by
+// side without sharing any state.
+type extraction struct {
+	syntax  *ast.SyntaxKind // the syntax kinds of the parser that created the AST
+	options ExtractOptions  // the options to extract components with
+}
 
 /**
  * Get a map of all components against the file that they
@@ -34,10 +38,17 @@ var Syntax *ast.SyntaxKind
  *
  * @param syntaxKind the `SyntaxKind` object as extracted from
  *		the typescript compiler.
+ *
+ * @param modules the modules that the source files may import
+ *		types from, may be `nil`
+ *
+ * @param options the options to extract components with, may be `nil`
  */
-func GetComponents(fileAstMap map[string]ast.SourceFile, syntaxKind *ast.SyntaxKind) []Component {
-	// setup syntax so anyone can use it within the package
-	Syntax = syntaxKind
+func GetComponents(fileAstMap map[string]ast.SourceFile, syntaxKind *ast.SyntaxKind, modules *ast.Modules, options *ExtractOptions) []Component {
+	ex := &extraction{syntax: syntaxKind}
+	if options != nil {
+		ex.options = *options
+	}
 
 	// start timing
 	start := time.Now()
@@ -53,15 +64,16 @@ func GetComponents(fileAstMap map[string]ast.SourceFile, syntaxKind *ast.SyntaxK
 	// process for each file path and AST
 	for file, sourceFile := range fileAstMap {
 		name, path := getNameAndPath(file)
+		sourceFile.SetModules(modules)
 
-		components := extractComponentsFromSourceFile(name, path, sourceFile)
+		components := ex.extractComponentsFromSourceFile(name, path, sourceFile)
 		list = append(list, components...)
 	}
 
-	// get time spent
-	duration := time.Since(start)
-	fmt.Println("Total number of components extracted: " + strconv.Itoa(len(list)))
-	fmt.Println("Total time in extracting components: " + duration.String())
+	// connect compound components across files
+	linkSubComponents(list)
+
+	ex.options.getLogger().Debug("Extracted components", "components", len(list), "duration", time.Since(start))
 
 	return list
 }
@@ -69,11 +81,13 @@ func GetComponents(fileAstMap map[string]ast.SourceFile, syntaxKind *ast.SyntaxK
 // Get components as defined in a single source file.
 // This is useful for testing
 func GetComponentsFromSourceFile(sourceFile *ast.SourceFile, syntaxKind *ast.SyntaxKind, name string, path string) []Component {
-	// setup syntax so anyone can use it within the package
-	Syntax = syntaxKind
+	ex := &extraction{syntax: syntaxKind}
 
 	// convert and return
-	return extractComponentsFromSourceFile(name, path, *sourceFile)
+	list := ex.extractComponentsFromSourceFile(name, path, *sourceFile)
+	linkSubComponents(list)
+
+	return list
 }
 
 /**
@@ -85,7 +99,7 @@ func GetComponentsFromSourceFile(sourceFile *ast.SourceFile, syntaxKind *ast.Syn
  *
  * @param sourceFile the `SourceFile` instance describing the AST
  */
-func extractComponentsFromSourceFile(name string, path string, sourceFile ast.SourceFile) []Component {
+func (ex *extraction) extractComponentsFromSourceFile(name string, path string, sourceFile ast.SourceFile) []Component {
 	// fmt.Println("Extracting components from: " + path + "/" + name)
 
 	cl := make([]Component, 0)
@@ -94,29 +108,210 @@ func extractComponentsFromSourceFile(name string, path string, sourceFile ast.So
 		return cl
 	}
 
+	// compute line offsets once, before the source file is copied around
+	sourceFile.ComputeLineStarts()
+
 	for _, statement := range sourceFile.Statements {
 		// detect class based components
-		if Syntax.IsClassDeclaration(&statement) {
-			component := extractClassBasedComponents(path, sourceFile, statement)
+		if ex.syntax.IsClassDeclaration(&statement) {
+			component := ex.extractClassBasedComponents(path, sourceFile, statement)
 			if component != nil {
+				setComponentLocation(component, &sourceFile, &statement)
+				ex.applyCheckedProps(component, sourceFile.GetCheckedProps(component.Name))
 				cl = append(cl, *component)
 			}
 			continue
 		}
 
 		// detect function based components
-		if Syntax.IsFunctionDeclaration(&statement) {
-			component := extractFunctionBasedComponent(path, sourceFile, statement)
+		if ex.syntax.IsFunctionDeclaration(&statement) {
+			component := ex.extractFunctionBasedComponent(path, sourceFile, statement)
 			if component != nil {
+				setComponentLocation(component, &sourceFile, &statement)
+				ex.applyCheckedProps(component, sourceFile.GetCheckedProps(component.Name))
 				cl = append(cl, *component)
 			}
 			continue
 		}
 	}
 
+	// detect sub-components assigned as `Parent.Child = Component`
+	ex.detectSubComponentAssignments(sourceFile, cl)
+
 	return cl
 }
 
+/**
+ * Record the file and the lines where the component is declared.
+ */
+func setComponentLocation(component *Component, sourceFile *ast.SourceFile, statement *ast.Statement) {
+	component.key = getComponentKey(sourceFile.FileName, component.Name)
+	component.SourceFile = sourceFile.FileName
+	component.Line, _ = sourceFile.GetStartLineAndColumn(statement.Pos)
+	component.EndLine = sourceFile.GetEndLine(statement.End)
+}
+
+/**
+ * Record the file and the lines where the prop is declared, given
+ * the `pos` and `end` of the declaring node.
+ */
+func setPropLocation(prop *PropDef, sourceFile *ast.SourceFile, pos int, end int) {
+	prop.SourceFile = sourceFile.FileName
+	prop.Line, _ = sourceFile.GetStartLineAndColumn(pos)
+	prop.EndLine = sourceFile.GetEndLine(end)
+}
+
+/**
+ * Find all expression statements of the form `Parent.Child = Component`
+ * where `Parent` is one of the components defined in this file, and
+ * record the `Child` as a candidate sub-component of the parent.
+ * Candidates are verified later in `linkSubComponents`, once all
+ * components are known.
+ */
+func (ex *extraction) detectSubComponentAssignments(sourceFile ast.SourceFile, components []Component) {
+	if len(components) == 0 {
+		return
+	}
+
+	for _, statement := range sourceFile.Statements {
+		if !ex.syntax.IsExpressionStatement(&statement) || !ex.syntax.IsPropertyAssignmentExpression(statement.Expression) {
+			continue
+		}
+
+		// the right side must point to another component by name
+		expr := statement.Expression
+		if expr.Right == nil || !ex.syntax.IsIdentifier(expr.Right) {
+			continue
+		}
+
+		parentName := expr.Left.Expression.EscapedText
+		for index := range components {
+			if components[index].Name != parentName {
+				continue
+			}
+
+			components[index].SubComponents = append(components[index].SubComponents, newSubComponentDef(&sourceFile, expr.Left.Name.EscapedText, expr.Right.EscapedText))
+		}
+	}
+}
+
+/**
+ * Find all `static` members of a class component that are assigned
+ * a plain identifier, such as `static Item = MenuItem`. These are
+ * candidate sub-components of the class component.
+ */
+func (ex *extraction) getStaticSubComponents(source *ast.SourceFile, classDeclStatement *ast.Statement) []SubComponentDef {
+	var subComponents []SubComponentDef
+
+	for _, member := range classDeclStatement.Members {
+		if member.Name == nil || !ex.syntax.IsPropertyDeclaration(&member) || !ex.syntax.HasStaticModifier(&member) {
+			continue
+		}
+
+		if member.Initializer == nil || !ex.syntax.IsIdentifier(member.Initializer) {
+			continue
+		}
+
+		subComponents = append(subComponents, newSubComponentDef(source, member.Name.EscapedText, member.Initializer.EscapedText))
+	}
+
+	return subComponents
+}
+
+/**
+ * Create a candidate sub-component attached as the given property,
+ * pointing to the component of the given name in the source file.
+ * The component is looked up in the file it is imported from, if
+ * any, under the name it is exported as. Default exports are looked
+ * up by the name they are imported as.
+ */
+func newSubComponentDef(source *ast.SourceFile, name string, componentName string) SubComponentDef {
+	subComponent := SubComponentDef{
+		Name:          name,
+		ComponentName: componentName,
+	}
+
+	file, exportedName := source.GetImportedFile(componentName)
+	if exportedName == "" {
+		// declared in the same file
+		subComponent.key = getComponentKey(source.FileName, componentName)
+		return subComponent
+	}
+
+	if file == "" {
+		// imported from a module that was not read
+		return subComponent
+	}
+
+	if exportedName == "default" {
+		exportedName = componentName
+	}
+
+	subComponent.key = getComponentKey(file, exportedName)
+	return subComponent
+}
+
+// Return the key of the component declared in the file with the
+// given name, which tells apart components that share a name.
+func getComponentKey(file string, name string) string {
+	return file + "#" + name
+}
+
+/**
+ * Link all candidate sub-components to the components they point
+ * to, which are declared in the same file as the parent, or in the
+ * file the parent imports them from. A candidate that does not
+ * resolve to a known component is dropped, and each resolved child
+ * gets its `Parent` set.
+ */
+func linkSubComponents(list []Component) {
+	componentIndex := make(map[string]int, len(list))
+	for index, component := range list {
+		componentIndex[component.key] = index
+	}
+
+	for index := range list {
+		if len(list[index].SubComponents) == 0 {
+			continue
+		}
+
+		var linked []SubComponentDef
+		for _, subComponent := range list[index].SubComponents {
+			childIndex, exists := componentIndex[subComponent.key]
+			if !exists || childIndex == index {
+				continue
+			}
+
+			linked = append(linked, subComponent)
+			list[childIndex].Parent = list[index].Name
+			list[childIndex].parentKey = list[index].key
+		}
+
+		list[index].SubComponents = linked
+	}
+}
+
+// Set the ids of the components that sub-components are attached
+// to, and of the sub-components attached to each component, once
+// all components have been assigned their ids.
+func SetSubComponentIds(list []Component) {
+	ids := make(map[string]string, len(list))
+	for _, component := range list {
+		ids[component.key] = component.Id
+	}
+
+	for index := range list {
+		component := &list[index]
+		if component.parentKey != "" {
+			component.ParentId = ids[component.parentKey]
+		}
+
+		for subIndex := range component.SubComponents {
+			component.SubComponents[subIndex].ComponentId = ids[component.SubComponents[subIndex].key]
+		}
+	}
+}
+
 // Extract name and path from a complete full absolute path.
 // Returns the name as the first part and path as the second
 // part in the return values.
@@ -136,10 +331,16 @@ func getNameAndPath(str string) (string, string) {
 /**
  * Extract a class based component (if applicable) from the given statement
  */
-func extractClassBasedComponents(path string, source ast.SourceFile, classDeclStatement ast.Statement) *Component {
+func (ex *extraction) extractClassBasedComponents(path string, source ast.SourceFile, classDeclStatement ast.Statement) *Component {
+	// skip anonymous classes, and those cut short in broken files,
+	// as a component is documented by its name
+	if classDeclStatement.Name == nil {
+		return nil
+	}
+
 	// skip if there is no export modifier - we only document
 	// public components
-	if !(classDeclStatement.HasExportModifier() || source.IsNameExported(classDeclStatement.Name.EscapedText)) {
+	if !(ex.syntax.HasExportModifier(&classDeclStatement) || source.IsNameExported(classDeclStatement.Name.EscapedText)) {
 		return nil
 	}
 
@@ -152,31 +353,33 @@ func extractClassBasedComponents(path string, source ast.SourceFile, classDeclSt
 
 	// case 1: has export keyword, and extend react.component or just component from both react library
 	// the class must have a method called "render" to be a component
-	if !Syntax.HasMethodOfName(classDeclStatement, "render") {
+	if !ex.syntax.HasMethodOfName(classDeclStatement, "render") {
 		return nil
 	}
 
 	// all checks pass - this is a class based component
 	// verify if it extends from React or not
-	componentTypeWrapper := detectComponentType(source, classDeclStatement)
+	componentTypeWrapper := ex.detectComponentType(source, classDeclStatement)
 	if componentTypeWrapper == nil || !componentTypeWrapper.Detected {
 		return nil
 	}
 
 	// class extends and is definitely a react component
 	componentDef := Component{
-		Name:          classDeclStatement.GetClassName(),
-		SourcePath:    path,
-		ComponentType: componentTypeWrapper.ComponentType,
-		Description:   ast.GetJsDoc(classDeclStatement.JsDoc),
-		Props:         make([]PropDef, 0),
+		Name:           ex.syntax.GetClassName(&classDeclStatement),
+		SourcePath:     path,
+		ComponentType:  componentTypeWrapper.ComponentType,
+		Description:    ast.GetJsDoc(classDeclStatement.JsDoc),
+		Props:          make([]PropDef, 0),
+		SubComponents:  ex.getStaticSubComponents(&source, &classDeclStatement),
+		TypeParameters: ex.getTypeParamDefs(classDeclStatement.TypeParameters),
 	}
 
 	// read and build a map (if available) of default values
 	// of the component props. We build it before reading the
 	// props themselves, so that we can assign the default
 	// values within the same loop
-	propDefaultValueMap := getPropsDefaultValuesIfAvailable(&classDeclStatement)
+	propDefaultValueMap := ex.getPropsDefaultValuesIfAvailable(&classDeclStatement)
 
 	// find component props and their types
 	if len(componentTypeWrapper.ClauseType.TypeArguments) > 0 {
@@ -184,27 +387,92 @@ func extractClassBasedComponents(path string, source ast.SourceFile, classDeclSt
 		// this is the interface as specified as the first
 		// argument in the heritage clause
 		typeReference := componentTypeWrapper.ClauseType.TypeArguments[0]
+		componentDef.Props = ex.getPropsOfType(source, &typeReference, propDefaultValueMap, componentDef.TypeParameters)
+	}
 
-		// find all members of the interface from the source file
-		// we just read all members of the interface
-		// TODO: we need to find and read all members of any super type
-		// as well here, so that we can create a single list of all
-		// properties
-		var members []ast.Member
-		if typeReference.TypeName != nil {
-			members = source.GetMembersOfType(typeReference.TypeName.EscapedText)
-		}
+	// plain JS components declare their props via `propTypes`
+	componentDef.Props = mergePropTypesProps(componentDef.Props, ex.getPropTypesProps(source, &classDeclStatement, propDefaultValueMap))
 
-		// document all the members as thi components props of this
-		// component. We create a value object for each member we found
-		if len(members) > 0 {
-			for _, member := range members {
-				componentDef.Props = append(componentDef.Props, *getComponentProp(member, propDefaultValueMap))
-			}
+	// list all event handler props separately
+	componentDef.Events = getEventProps(componentDef.Props)
+
+	return &componentDef
+}
+
+/**
+ * Read the props of a component from the type that defines them,
+ * such as `ButtonProps` in `React.Component<ButtonProps>` or in
+ * `function Button(props: ButtonProps)`.
+ *
+ * @param typeReference the props type as referenced by the component
+ *
+ * @param propDefaultValueMap a `map` of default values for props
+ *
+ * @param typeParams the type parameters declared by the component,
+ * 		if the component is generic
+ */
+func (ex *extraction) getPropsOfType(source ast.SourceFile, typeReference *ast.TypeReference, propDefaultValueMap map[string]string, typeParams []TypeParamDef) []PropDef {
+	props := make([]PropDef, 0)
+	if typeReference == nil || typeReference.TypeName == nil {
+		return props
+	}
+
+	// find all members of the interface from the source file
+	// we just read all members of the interface
+	// TODO: we need to find and read all members of any super type
+	// as well here, so that we can create a single list of all
+	// properties
+	typeName := typeReference.TypeName.EscapedText
+	declaringSource, declaration := source.FindInterface(typeName)
+	if declaration == nil || len(declaration.Members) == 0 {
+		return props
+	}
+
+	// map the type parameters of the props interface to the
+	// type arguments used by the component
+	scope := ex.newTypeScope(typeParams, declaration.TypeParameters, typeReference.TypeArguments)
+
+	// document all the members as thi components props of this
+	// component. We create a value object for each member we found
+	names := make(map[string]bool, len(declaration.Members))
+	for _, member := range declaration.Members {
+		prop := ex.getComponentProp(member, propDefaultValueMap, scope)
+		setPropLocation(prop, declaringSource, member.Pos, member.End)
+
+		names[prop.Name] = true
+		props = append(props, *prop)
+	}
+
+	// add the props inherited from the interfaces being extended
+	props = append(props, ex.getInheritedProps(declaringSource, declaration, propDefaultValueMap, scope, names, 0)...)
+
+	return props
+}
+
+/**
+ * Convert the type parameters declared on a component into
+ * their definitions. Returns `nil` if the component is not
+ * generic.
+ */
+func (ex *extraction) getTypeParamDefs(typeParameters []ast.TypeParameter) []TypeParamDef {
+	if len(typeParameters) == 0 {
+		return nil
+	}
+
+	typeParams := make([]TypeParamDef, 0, len(typeParameters))
+	for _, typeParameter := range typeParameters {
+		if typeParameter.Name == nil {
+			continue
 		}
+
+		typeParams = append(typeParams, TypeParamDef{
+			Name:       typeParameter.Name.EscapedText,
+			Constraint: ex.syntax.GetTypeText(typeParameter.Constraint),
+			Default:    ex.syntax.GetTypeText(typeParameter.Default),
+		})
 	}
 
-	return &componentDef
+	return typeParams
 }
 
 /**
@@ -214,11 +482,11 @@ func extractClassBasedComponents(path string, source ast.SourceFile, classDeclSt
  * for a prop, the key for that prop is not present in the
  * map.
  */
-func getPropsDefaultValuesIfAvailable(classDeclStatement *ast.Statement) map[string]string {
+func (ex *extraction) getPropsDefaultValuesIfAvailable(classDeclStatement *ast.Statement) map[string]string {
 	defaultValueMap := make(map[string]string, 0)
 
 	// for all these prop members, see if there is a default value specified or not
-	defaultProps := findDefaultPropsMember(classDeclStatement)
+	defaultProps := ex.findDefaultPropsMember(classDeclStatement)
 	if defaultProps == nil {
 		return defaultValueMap
 	}
@@ -230,8 +498,13 @@ func getPropsDefaultValuesIfAvailable(classDeclStatement *ast.Statement) map[str
 
 	// iterate over all properties
 	for _, property := range defaultProps.Initializer.Properties {
+		// spread properties have no name
+		if property.Name == nil {
+			continue
+		}
+
 		propName := property.Name.EscapedText
-		propValue := extractPropValue(property)
+		propValue := ex.extractPropValue(property)
 
 		defaultValueMap[propName] = propValue
 	}
@@ -245,24 +518,24 @@ func getPropsDefaultValuesIfAvailable(classDeclStatement *ast.Statement) map[str
  * when reading properties from `static defaultProps`
  * member of the class based component.
  */
-func extractPropValue(property ast.Property) string {
+func (ex *extraction) extractPropValue(property ast.Property) string {
 	switch property.Initializer.Kind {
-	case Syntax.TrueKeyword:
+	case ex.syntax.TrueKeyword:
 		return "true"
 
-	case Syntax.FalseKeyword:
+	case ex.syntax.FalseKeyword:
 		return "false"
 
-	case Syntax.StringLiteral:
+	case ex.syntax.StringLiteral:
 		return property.Initializer.Text
 
-	case Syntax.NumericLiteral:
+	case ex.syntax.NumericLiteral:
 		return property.Initializer.Text
 
-	case Syntax.Identifier:
+	case ex.syntax.Identifier:
 		return property.Initializer.EscapedText
 
-	case Syntax.NullKeyword:
+	case ex.syntax.NullKeyword:
 		return "null"
 	}
 
@@ -273,13 +546,13 @@ func extractPropValue(property ast.Property) string {
  * Given a class definition, find the member that
  * is named `defaultProps` and is `static` defined.
  */
-func findDefaultPropsMember(classDeclStatement *ast.Statement) *ast.Member {
+func (ex *extraction) findDefaultPropsMember(classDeclStatement *ast.Statement) *ast.Member {
 	if len(classDeclStatement.Members) == 0 {
 		return nil
 	}
 
 	for _, member := range classDeclStatement.Members {
-		if member.Name != nil && member.Name.EscapedText == "defaultProps" && member.HasStaticModifier() {
+		if member.Name != nil && member.Name.EscapedText == "defaultProps" && ex.syntax.HasStaticModifier(&member) {
 			return &member
 		}
 	}
@@ -290,10 +563,15 @@ func findDefaultPropsMember(classDeclStatement *ast.Statement) *ast.Member {
 /**
  * Extract a function based component (if applicable) from the given statement
  */
-func extractFunctionBasedComponent(path string, source ast.SourceFile, functionStatement ast.Statement) *Component {
+func (ex *extraction) extractFunctionBasedComponent(path string, source ast.SourceFile, functionStatement ast.Statement) *Component {
+	// skip anonymous functions, as a component is documented by its name
+	if functionStatement.Name == nil {
+		return nil
+	}
+
 	// skip if there is no export modifier - we only document
 	// public components
-	if !(functionStatement.HasExportModifier() || source.IsNameExported(functionStatement.Name.EscapedText)) {
+	if !(ex.syntax.HasExportModifier(&functionStatement) || source.IsNameExported(functionStatement.Name.EscapedText)) {
 		return nil
 	}
 
@@ -324,29 +602,47 @@ func extractFunctionBasedComponent(path string, source ast.SourceFile, functionS
 	for _, statement := range functionStatement.Body.Statements {
 		// this is a return statement
 		// this takes care of something `return <MyComponent />`
-		if Syntax.IsReturnStatement(&statement) && Syntax.IsJsxElement(statement.Expression) {
-			// (Syntax.IsParenthesizedExpression(statement.Expression) && Syntax.IsJsxElement(statement.Expression.Expression))) {
-			return createFunctionComponentDef(path, functionStatement)
+		if ex.syntax.IsReturnStatement(&statement) && ex.syntax.IsJsxElement(statement.Expression) {
+			// (ex.syntax.IsParenthesizedExpression(statement.Expression) && ex.syntax.IsJsxElement(statement.Expression.Expression))) {
+			return ex.createFunctionComponentDef(path, source, functionStatement)
 		}
 
 		// body is ParenthesizedExpression with JSX
 		// const NewComponent = () => <MyComponent />
-		if Syntax.IsParenthesizedExpression(&statement) && Syntax.IsJsxElement(statement.Expression) {
-			return createFunctionComponentDef(path, functionStatement)
+		if ex.syntax.IsParenthesizedExpression(&statement) && ex.syntax.IsJsxElement(statement.Expression) {
+			return ex.createFunctionComponentDef(path, source, functionStatement)
 		}
 	}
 
 	return nil
 }
 
-func createFunctionComponentDef(path string, functionStatement ast.Statement) *Component {
+func (ex *extraction) createFunctionComponentDef(path string, source ast.SourceFile, functionStatement ast.Statement) *Component {
 	componentDef := Component{
-		Name:          functionStatement.Name.EscapedText,
-		SourcePath:    path,
-		ComponentType: REACT_FUNCTION_COMPONENT,
-		Description:   ast.GetJsDoc(functionStatement.JsDoc),
+		Name:           functionStatement.Name.EscapedText,
+		SourcePath:     path,
+		ComponentType:  REACT_FUNCTION_COMPONENT,
+		Description:    ast.GetJsDoc(functionStatement.JsDoc),
+		TypeParameters: ex.getTypeParamDefs(functionStatement.TypeParameters),
+	}
+
+	// the first parameter of the function, if typed, carries the props
+	if len(functionStatement.Parameters) > 0 {
+		typeReference := functionStatement.Parameters[0].TypeReference
+		if typeReference != nil && ex.syntax.IsTypeReference(typeReference) {
+			componentDef.Props = ex.getPropsOfType(source, typeReference, map[string]string{}, componentDef.TypeParameters)
+		}
+	}
+
+	// untyped JSX components may document props via JSDoc
+	if len(componentDef.Props) == 0 {
+		componentDef.Props = ex.getJsDocProps(source, &functionStatement)
 	}
 
+	// plain JS components declare their props via `propTypes`
+	componentDef.Props = mergePropTypesProps(componentDef.Props, ex.getPropTypesProps(source, &functionStatement, map[string]string{}))
+	componentDef.Events = getEventProps(componentDef.Props)
+
 	return &componentDef
 }
 
@@ -360,8 +656,11 @@ func createFunctionComponentDef(path string, functionStatement ast.Statement) *C
  * @param propDefaultValueMap a `map` of default values
  * 		for props as read from the `static defaultProps`
  * 		read from the component.
+ *
+ * @param scope the type parameters in scope for generic
+ * 		components, or `nil`.
  */
-func getComponentProp(member ast.Member, propDefaultValueMap map[string]string) *PropDef {
+func (ex *extraction) getComponentProp(member ast.Member, propDefaultValueMap map[string]string, scope *typeScope) *PropDef {
 	// create a prop definition for the member
 	propDefintion := PropDef{
 		Name:        member.Name.EscapedText,
@@ -379,15 +678,23 @@ func getComponentProp(member ast.Member, propDefaultValueMap map[string]string)
 	// get the prop type if available
 	// this is a tricky place. The prop type may not have
 	// been explicitly defined.
-	if member.TypeReference != nil && member.TypeReference.TypeName != nil {
-		propDefintion.PropType = member.TypeReference.TypeName.EscapedText
-	} else {
-		memberType := Syntax.GetType(member.TypeReference)
-		if !Syntax.IsUnknownType(memberType) && !Syntax.IsFunctionType(member.TypeReference) {
+	if ex.syntax.IsMethodSignature(&member) {
+		// a method signature such as `onChange(value: string): void`
+		// carries its parameters and return type on the member itself
+		propDefintion.PropType = "$function"
+		propDefintion.Params = ex.getParamDefs(member.Parameters, scope)
+		propDefintion.ReturnType = ex.getTypeText(scope, member.TypeReference)
+	} else if member.TypeReference != nil && (member.TypeReference.TypeName != nil || member.TypeReference.ElementType != nil) {
+		// render type arguments and array types as well,
+		// for `ChangeHandler<string>` or `T[]`
+		propDefintion.PropType = ex.getTypeText(scope, member.TypeReference)
+	} else if member.TypeReference != nil {
+		memberType := ex.syntax.GetType(member.TypeReference)
+		if !ex.syntax.IsUnknownType(memberType) && !ex.syntax.IsFunctionType(member.TypeReference) {
 			propDefintion.PropType = memberType
 		} else {
 			// Is this a union type? for example `myProp: string | bool`
-			if Syntax.IsUnionType(member.TypeReference) {
+			if ex.syntax.IsUnionType(member.TypeReference) {
 				propDefintion.PropType = "$enum"
 
 				// check under type.types - it carries a list
@@ -396,36 +703,29 @@ func getComponentProp(member ast.Member, propDefaultValueMap map[string]string)
 
 				// iterate and add
 				for index, individualType := range member.TypeReference.Types {
-					if individualType.Kind == Syntax.TypeReference {
+					if individualType.Kind == ex.syntax.TypeReference {
 						// we read the value from typeName.escapedText
 						propDefintion.EnumTypes[index] = ParamDef{
-							Name:      individualType.TypeName.EscapedText,
+							Name:      ex.getTypeText(scope, &individualType),
 							ParamType: "",
 						}
-					} else if individualType.Kind == Syntax.LiteralType {
+					} else if individualType.Kind == ex.syntax.LiteralType {
 						// we read the value from literal.text
 						propDefintion.EnumTypes[index] = ParamDef{
 							Name:      individualType.Literal.Text,
-							ParamType: Syntax.GetType(individualType.Literal),
+							ParamType: ex.syntax.GetType(individualType.Literal),
 						}
 					}
 				}
-			} else if Syntax.IsFunctionType(member.TypeReference) {
+			} else if ex.syntax.IsFunctionType(member.TypeReference) {
 				propDefintion.PropType = "$function"
 
 				if member.TypeReference.Parameters != nil {
-					propDefintion.Params = make([]ParamDef, 0)
-
 					// build the type using definitions
-					for _, param := range member.TypeReference.Parameters {
-						propDefintion.Params = append(propDefintion.Params, ParamDef{
-							Name:      param.Name.EscapedText,
-							ParamType: Syntax.GetType(param.TypeReference),
-						})
-					}
+					propDefintion.Params = ex.getParamDefs(member.TypeReference.Parameters, scope)
 
 					// set return type of function
-					propDefintion.ReturnType = Syntax.GetType(member.TypeReference.TypeValue)
+					propDefintion.ReturnType = ex.getTypeText(scope, member.TypeReference.TypeValue)
 				}
 			}
 		}
@@ -434,5 +734,54 @@ func getComponentProp(member ast.Member, propDefaultValueMap map[string]string)
 	// set default value if applicable
 	propDefintion.DefaultValue = propDefaultValueMap[propDefintion.Name]
 
+	// props like `onClick` or `onChange` are event handlers
+	propDefintion.IsEvent = isEventPropName(propDefintion.Name)
+
+	// props whose type refers to a type parameter of the component
+	propDefintion.IsGeneric = ex.isGenericMember(scope, member)
+
 	return &propDefintion
 }
+
+/**
+ * Build parameter definitions for the given function parameters,
+ * recording whether each parameter is optional or a rest parameter.
+ */
+func (ex *extraction) getParamDefs(params []ast.Parameter, scope *typeScope) []ParamDef {
+	paramDefs := make([]ParamDef, 0, len(params))
+
+	for _, param := range params {
+		paramDef := ParamDef{
+			Optional: param.QuestionToken != nil || param.Initializer != nil,
+			Rest:     param.DotDotDotToken != nil,
+		}
+
+		if param.Name != nil {
+			paramDef.Name = param.Name.EscapedText
+		}
+
+		if param.TypeReference != nil {
+			paramDef.ParamType = ex.getTypeText(scope, param.TypeReference)
+		}
+
+		paramDefs = append(paramDefs, paramDef)
+	}
+
+	return paramDefs
+}
+
+/**
+ * Collect all props that are event handlers so that they
+ * can be documented separately as the component events.
+ */
+func getEventProps(props []PropDef) []PropDef {
+	var events []PropDef
+
+	for _, prop := range props {
+		if prop.IsEvent {
+			events = append(events, prop)
+		}
+	}
+
+	return events
+}
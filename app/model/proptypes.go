@@ -0,0 +1,294 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package model
+
+import (
+	"sangupta.com/redefine/ast"
+)
+
+// This file contains functions to read props from the runtime
+// `propTypes` declarations, as used by plain JS components that
+// do not have a Typescript interface defining their props.
+
+// The types that the simple `PropTypes` validators map to.
+var propTypesValidatorTypes = map[string]string{
+	"any":         "any",
+	"array":       "array",
+	"bool":        "boolean",
+	"func":        "$function",
+	"number":      "number",
+	"object":      "object",
+	"string":      "string",
+	"symbol":      "symbol",
+	"node":        "ReactNode",
+	"element":     "ReactElement",
+	"elementType": "ElementType",
+}
+
+/**
+ * Read the props of a component as declared in its `propTypes`,
+ * either via `static propTypes = {...}` on a class component or
+ * via `Component.propTypes = {...}` anywhere in the source file.
+ *
+ * @param source the source file defining the component
+ *
+ * @param statement the class or function declaration of the component
+ *
+ * @param propDefaultValueMap a `map` of default values for props
+ */
+func (ex *extraction) getPropTypesProps(source ast.SourceFile, statement *ast.Statement, propDefaultValueMap map[string]string) []PropDef {
+	properties := ex.findStaticPropTypes(statement)
+	if properties == nil && statement.Name != nil {
+		properties = ex.findPropTypesAssignment(source, statement.Name.EscapedText)
+	}
+
+	if len(properties) == 0 {
+		return nil
+	}
+
+	props := make([]PropDef, 0, len(properties))
+	for _, property := range properties {
+		propDef := ex.getPropTypesProp(property)
+		propDef.DefaultValue = propDefaultValueMap[propDef.Name]
+		setPropLocation(&propDef, &source, property.Pos, property.End)
+		propDef.IsEvent = isEventPropName(propDef.Name)
+
+		props = append(props, propDef)
+	}
+
+	return props
+}
+
+/**
+ * Find the properties of the `static propTypes` member of the
+ * given class declaration, if any.
+ */
+func (ex *extraction) findStaticPropTypes(statement *ast.Statement) []ast.Property {
+	for _, member := range statement.Members {
+		if member.Name == nil || member.Name.EscapedText != "propTypes" || !ex.syntax.HasStaticModifier(&member) {
+			continue
+		}
+
+		if member.Initializer != nil && ex.syntax.IsObjectLiteralExpression(member.Initializer) {
+			return member.Initializer.Properties
+		}
+	}
+
+	return nil
+}
+
+/**
+ * Find the properties of the `<name>.propTypes = {...}` assignment
+ * in the source file, if any.
+ */
+func (ex *extraction) findPropTypesAssignment(source ast.SourceFile, name string) []ast.Property {
+	for _, statement := range source.Statements {
+		if !ex.syntax.IsExpressionStatement(&statement) || !ex.syntax.IsPropertyAssignmentExpression(statement.Expression) {
+			continue
+		}
+
+		expr := statement.Expression
+		if expr.Left.Expression.EscapedText != name || expr.Left.Name.EscapedText != "propTypes" {
+			continue
+		}
+
+		if expr.Right != nil && ex.syntax.IsObjectLiteralExpression(expr.Right) {
+			return expr.Right.Properties
+		}
+	}
+
+	return nil
+}
+
+/**
+ * Create a prop definition from a single property of the
+ * `propTypes` object, such as `size: PropTypes.string.isRequired`.
+ */
+func (ex *extraction) getPropTypesProp(property ast.Property) PropDef {
+	propDef := PropDef{
+		Name:        property.GetName(),
+		Description: ast.GetJsDoc(property.JsDoc),
+	}
+
+	ex.readPropTypesValidator(property.Initializer, &propDef)
+	return propDef
+}
+
+/**
+ * Read the validator expression of a prop and populate the
+ * prop definition with its type, required flag, enum values
+ * and nested shape.
+ */
+func (ex *extraction) readPropTypesValidator(expr *ast.Expression, propDef *PropDef) {
+	if expr == nil {
+		return
+	}
+
+	// `PropTypes.string.isRequired`
+	if ex.syntax.IsPropertyAccessExpression(expr) && expr.Name != nil && expr.Name.EscapedText == "isRequired" {
+		propDef.Required = true
+		expr = expr.Expression
+	}
+
+	// simple validators like `PropTypes.string`
+	if !ex.syntax.IsCallExpression(expr) {
+		propDef.PropType = propTypesValidatorTypes[ex.getPropTypesValidatorName(expr)]
+		return
+	}
+
+	// validators that take arguments like `PropTypes.oneOf([...])`
+	var arg *ast.Expression
+	if len(expr.Arguments) > 0 {
+		arg = &expr.Arguments[0]
+	}
+
+	switch ex.getPropTypesValidatorName(expr.Expression) {
+	case "oneOf":
+		propDef.PropType = "$enum"
+		if arg != nil && ex.syntax.IsArrayLiteralExpression(arg) {
+			propDef.EnumTypes = make([]ParamDef, 0, len(arg.Elements))
+			for _, element := range arg.Elements {
+				propDef.EnumTypes = append(propDef.EnumTypes, ParamDef{
+					Name:      element.Text,
+					ParamType: ex.syntax.GetType(&element),
+				})
+			}
+		}
+
+	case "oneOfType":
+		propDef.PropType = "$enum"
+		if arg != nil && ex.syntax.IsArrayLiteralExpression(arg) {
+			propDef.EnumTypes = make([]ParamDef, 0, len(arg.Elements))
+			for index := range arg.Elements {
+				propDef.EnumTypes = append(propDef.EnumTypes, ParamDef{
+					Name:      ex.getPropTypesValidatorType(&arg.Elements[index]),
+					ParamType: "",
+				})
+			}
+		}
+
+	case "arrayOf":
+		// the shape or values of the items are those of the array
+		element := PropDef{}
+		ex.readPropTypesValidator(arg, &element)
+		if element.PropType == "" {
+			element.PropType = "any"
+		}
+
+		propDef.PropType = element.PropType + "[]"
+		propDef.Shape = element.Shape
+		propDef.EnumTypes = element.EnumTypes
+
+	case "objectOf":
+		propDef.PropType = "Record<string, " + ex.getPropTypesValidatorType(arg) + ">"
+
+	case "instanceOf":
+		if arg != nil {
+			propDef.PropType = arg.EscapedText
+		}
+
+	case "shape", "exact":
+		propDef.PropType = "$shape"
+		if arg != nil && ex.syntax.IsObjectLiteralExpression(arg) {
+			propDef.Shape = make([]PropDef, 0, len(arg.Properties))
+			for _, property := range arg.Properties {
+				propDef.Shape = append(propDef.Shape, ex.getPropTypesProp(property))
+			}
+		}
+	}
+}
+
+/**
+ * Return the type for a nested validator, such as the argument
+ * to `PropTypes.arrayOf(PropTypes.string)`.
+ */
+func (ex *extraction) getPropTypesValidatorType(expr *ast.Expression) string {
+	propDef := PropDef{}
+	ex.readPropTypesValidator(expr, &propDef)
+
+	if propDef.PropType == "" {
+		return "any"
+	}
+
+	return propDef.PropType
+}
+
+/**
+ * Return the name of the validator, which is the last name in
+ * a property access chain such as `PropTypes.string`, or the
+ * identifier itself when imported directly, like `string`.
+ */
+func (ex *extraction) getPropTypesValidatorName(expr *ast.Expression) string {
+	if expr == nil {
+		return ""
+	}
+
+	if ex.syntax.IsPropertyAccessExpression(expr) && expr.Name != nil {
+		return expr.Name.EscapedText
+	}
+
+	if ex.syntax.IsIdentifier(expr) {
+		return expr.EscapedText
+	}
+
+	return ""
+}
+
+/**
+ * Merge the props read from `propTypes` into the props read from
+ * the Typescript props interface. Typescript definitions win, but
+ * details missing from them are filled in from `propTypes`, and
+ * props only declared in `propTypes` are appended. A prop is
+ * required if either of them requires it.
+ */
+func mergePropTypesProps(props []PropDef, propTypesProps []PropDef) []PropDef {
+	if len(propTypesProps) == 0 {
+		return props
+	}
+
+	propIndex := make(map[string]int, len(props))
+	for index, prop := range props {
+		propIndex[prop.Name] = index
+	}
+
+	for _, propTypesProp := range propTypesProps {
+		index, exists := propIndex[propTypesProp.Name]
+		if !exists {
+			props = append(props, propTypesProp)
+			continue
+		}
+
+		prop := &props[index]
+		if prop.PropType == "" {
+			prop.PropType = propTypesProp.PropType
+		}
+
+		if propTypesProp.Required {
+			prop.Required = true
+		}
+
+		if prop.EnumTypes == nil {
+			prop.EnumTypes = propTypesProp.EnumTypes
+		}
+
+		if prop.Shape == nil {
+			prop.Shape = propTypesProp.Shape
+		}
+
+		if prop.Description == "" {
+			prop.Description = propTypesProp.Description
+		}
+	}
+
+	return props
+}
@@ -12,30 +12,95 @@ that can be found in LICENSE file in the code repository.
 
 package model
 
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+)
+
 type Component struct {
-	Name          string        `json:"name"`
-	SourcePath    string        `json:"sourcePath"`
-	ComponentType ComponentType `json:"componentType"`
-	Description   string        `json:"description"`
-	Props         []PropDef     `json:"props"`
-	Docs          string        `json:"docs"`
-	DocFileName   string        `json:"docFileName"`
+	Name           string            `json:"name"`
+	Id             string            `json:"id"`
+	SourcePath     string            `json:"sourcePath"`
+	Package        string            `json:"package"`
+	ComponentType  ComponentType     `json:"componentType"`
+	Description    string            `json:"description"`
+	Props          []PropDef         `json:"props"`
+	Events         []PropDef         `json:"events"`
+	Docs           string            `json:"docs"`
+	DocFileName    string            `json:"docFileName"`
+	SubComponents  []SubComponentDef `json:"subComponents"`
+	Parent         string            `json:"parent"`   // the name of the component this one is attached to
+	ParentId       string            `json:"parentId"` // the id of the component this one is attached to
+	TypeParameters []TypeParamDef    `json:"typeParameters"`
+	SourceFile     string            `json:"sourceFile"`
+	Line           int               `json:"line"`
+	EndLine        int               `json:"endLine"`
+	SourceUrl      string            `json:"sourceUrl"`
+	key            string            // the file and name the component is declared with, which is unique
+	parentKey      string            // the key of the component this one is attached to
 }
 
 type PropDef struct {
-	Name         string     `json:"name"`
-	PropType     string     `json:"type"`
-	EnumTypes    []ParamDef `json:"enumOf"`
-	Required     bool       `json:"required"`
-	DefaultValue string     `json:"defaultValue"`
-	Description  string     `json:"description"`
-	ReturnType   string     `json:"returnType"`
-	Params       []ParamDef `json:"params"`
+	Name          string     `json:"name"`
+	PropType      string     `json:"type"`
+	EnumTypes     []ParamDef `json:"enumOf"`
+	Required      bool       `json:"required"`
+	DefaultValue  string     `json:"defaultValue"`
+	Description   string     `json:"description"`
+	ReturnType    string     `json:"returnType"`
+	Params        []ParamDef `json:"params"`
+	IsEvent       bool       `json:"isEvent"`
+	IsGeneric     bool       `json:"isGeneric"`
+	Shape         []PropDef  `json:"shape"`
+	SourceFile    string     `json:"sourceFile"`
+	Line          int        `json:"line"`
+	EndLine       int        `json:"endLine"`
+	SourceUrl     string     `json:"sourceUrl"`
+	InheritedFrom string     `json:"inheritedFrom"`
+	Collapsed     bool       `json:"collapsed"`
+}
+
+// Options that control how components are extracted
+type ExtractOptions struct {
+	ExpandDomAttributes bool         // list inherited DOM attributes one by one, instead of a single entry
+	Logger              *slog.Logger // the logger to report progress to, nothing is logged when `nil`
+}
+
+// Return the logger of the options, or one that discards all
+// records if there is none.
+func (options *ExtractOptions) getLogger() *slog.Logger {
+	if options == nil || options.Logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	return options.Logger
 }
 
 type ParamDef struct {
 	Name      string `json:"name"`
 	ParamType string `json:"type"`
+	Optional  bool   `json:"optional"`
+	Rest      bool   `json:"rest"`
+}
+
+// Defines a type parameter of a generic component, such
+// as `T extends object = {}` in `Select<T extends object = {}>`.
+type TypeParamDef struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"`
+	Default    string `json:"default"`
+}
+
+// Defines a component that is attached to another component
+// as a property, for example `Tabs.Panel = TabPanel` or
+// `static Item = MenuItem`.
+type SubComponentDef struct {
+	Name          string `json:"name"`        // the property name on the parent, `Panel` for `Tabs.Panel`
+	ComponentName string `json:"component"`   // the name of the component being attached
+	ComponentId   string `json:"componentId"` // the id of the component being attached
+	key           string // the key of the component being attached, empty if it cannot be found
 }
 
 type ComponentType int64
@@ -44,3 +109,48 @@ const (
 	REACT_CLASS_COMPONENT ComponentType = iota
 	REACT_FUNCTION_COMPONENT
 )
+
+// The names component types are written to JSON with
+var componentTypeNames = map[ComponentType]string{
+	REACT_CLASS_COMPONENT:    "class",
+	REACT_FUNCTION_COMPONENT: "function",
+}
+
+// Return the name of the component type, as written to JSON.
+func (componentType ComponentType) String() string {
+	return componentTypeNames[componentType]
+}
+
+func (componentType ComponentType) MarshalJSON() ([]byte, error) {
+	name, exists := componentTypeNames[componentType]
+	if !exists {
+		return nil, errors.New("unknown component type")
+	}
+
+	return json.Marshal(name)
+}
+
+// Read the component type from its name, or from the number it
+// was written as in earlier versions of the components JSON.
+func (componentType *ComponentType) UnmarshalJSON(data []byte) error {
+	var number int64
+	if json.Unmarshal(data, &number) == nil {
+		*componentType = ComponentType(number)
+		return nil
+	}
+
+	var name string
+	err := json.Unmarshal(data, &name)
+	if err != nil {
+		return err
+	}
+
+	for value, valueName := range componentTypeNames {
+		if valueName == name {
+			*componentType = value
+			return nil
+		}
+	}
+
+	return errors.New("unknown component type: " + name)
+}
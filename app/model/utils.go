@@ -14,6 +14,7 @@ package model
 
 import (
 	"strings"
+	"unicode"
 
 	"sangupta.com/redefine/ast"
 )
@@ -25,10 +26,130 @@ type ComponentTypeWrapper struct {
 	Detected      bool
 }
 
+// Carries the type parameters in scope when reading the props
+// of a generic component, along with how the type parameters of
+// the props interface map onto the type arguments supplied to it.
+type typeScope struct {
+	typeParams    map[string]bool   // type parameters declared by the component
+	substitutions map[string]string // props interface type parameter to type argument
+}
+
+// Create a new scope for the given component type parameters,
+// mapping the props interface type parameters onto the type
+// arguments it was referenced with. Returns `nil` if there is
+// nothing generic about the props.
+func (ex *extraction) newTypeScope(typeParams []TypeParamDef, interfaceTypeParams []ast.TypeParameter, typeArguments []ast.TypeReference) *typeScope {
+	if len(typeParams) == 0 && len(interfaceTypeParams) == 0 {
+		return nil
+	}
+
+	scope := typeScope{
+		typeParams:    make(map[string]bool, len(typeParams)),
+		substitutions: make(map[string]string, len(interfaceTypeParams)),
+	}
+
+	for _, typeParam := range typeParams {
+		scope.typeParams[typeParam.Name] = true
+	}
+
+	for index, interfaceTypeParam := range interfaceTypeParams {
+		if interfaceTypeParam.Name == nil {
+			continue
+		}
+
+		// fall back to the default of the type parameter if the
+		// type argument was omitted
+		name := interfaceTypeParam.Name.EscapedText
+		if index < len(typeArguments) {
+			scope.substitutions[name] = ex.syntax.GetTypeText(&typeArguments[index])
+		} else if interfaceTypeParam.Default != nil {
+			scope.substitutions[name] = ex.syntax.GetTypeText(interfaceTypeParam.Default)
+		}
+	}
+
+	return &scope
+}
+
+// Create the scope for an interface extended by another interface,
+// such as `Base<T>` in `interface Props<T> extends Base<T>`. The type
+// arguments are rendered in the scope of the extending interface, so
+// that substitutions carry through the whole chain.
+func (ex *extraction) newInheritedTypeScope(parent *typeScope, interfaceTypeParams []ast.TypeParameter, typeArguments []ast.TypeReference) *typeScope {
+	scope := typeScope{
+		typeParams:    make(map[string]bool),
+		substitutions: make(map[string]string, len(interfaceTypeParams)),
+	}
+
+	if parent != nil {
+		scope.typeParams = parent.typeParams
+	}
+
+	for index, interfaceTypeParam := range interfaceTypeParams {
+		if interfaceTypeParam.Name == nil {
+			continue
+		}
+
+		name := interfaceTypeParam.Name.EscapedText
+		if index < len(typeArguments) {
+			scope.substitutions[name] = ex.getTypeText(parent, &typeArguments[index])
+		} else if interfaceTypeParam.Default != nil {
+			scope.substitutions[name] = ex.syntax.GetTypeText(interfaceTypeParam.Default)
+		}
+	}
+
+	return &scope
+}
+
+// Render the type as text, substituting type parameters of the
+// props interface with the type arguments of the component.
+func (ex *extraction) getTypeText(scope *typeScope, node *ast.TypeReference) string {
+	if scope == nil {
+		return ex.syntax.GetTypeText(node)
+	}
+
+	return ex.syntax.GetSubstitutedTypeText(node, scope.substitutions)
+}
+
+// Check if the type refers to any of the type parameters of the
+// component, after applying the substitutions.
+func (ex *extraction) isGenericType(scope *typeScope, node *ast.TypeReference) bool {
+	if scope == nil || node == nil {
+		return false
+	}
+
+	for _, name := range ex.syntax.GetReferencedTypeNames(node) {
+		if substitute, exists := scope.substitutions[name]; exists {
+			name = substitute
+		}
+
+		if scope.typeParams[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check if the member type, or for method signatures any of
+// its parameters, refers to a type parameter of the component.
+func (ex *extraction) isGenericMember(scope *typeScope, member ast.Member) bool {
+	if ex.isGenericType(scope, member.TypeReference) {
+		return true
+	}
+
+	for _, param := range member.Parameters {
+		if ex.isGenericType(scope, param.TypeReference) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // This method detects the component type, its heritage
 // clause (read the interface implementing the props)
 // and the applicable `HeritageClause.Type`
-func detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.Statement) *ComponentTypeWrapper {
+func (ex *extraction) detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.Statement) *ComponentTypeWrapper {
 	if len(classDeclStatement.HeritageClauses) == 0 {
 		return nil
 	}
@@ -40,7 +161,7 @@ func detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.State
 			var exprText string
 
 			if expr != nil {
-				if Syntax.IsPropertyAccessExpression(expr) {
+				if ex.syntax.IsPropertyAccessExpression(expr) {
 					exprText = expr.Expression.EscapedText
 					name := expr.Name.EscapedText
 
@@ -49,7 +170,7 @@ func detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.State
 					}
 				}
 
-				if Syntax.IsIdentifier(expr) {
+				if ex.syntax.IsIdentifier(expr) {
 					exprText = expr.EscapedText
 				}
 
@@ -57,7 +178,7 @@ func detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.State
 					continue
 				}
 
-				if isReactImport(sourceFile, exprText) {
+				if ex.isReactImport(sourceFile, exprText) {
 					return &ComponentTypeWrapper{
 						ComponentType: REACT_CLASS_COMPONENT,
 						Clause:        &clause,
@@ -77,9 +198,9 @@ func detectComponentType(sourceFile ast.SourceFile, classDeclStatement ast.State
 // To find it out, we run a check against all import
 // statements in the source file, and see if the import
 // object is coming in from a package called `react`.
-func isReactImport(sourceFile ast.SourceFile, name string) bool {
+func (ex *extraction) isReactImport(sourceFile ast.SourceFile, name string) bool {
 	for _, st := range sourceFile.Statements {
-		if !Syntax.IsImportDeclaration(&st) {
+		if !ex.syntax.IsImportDeclaration(&st) {
 			continue
 		}
 
@@ -91,3 +212,13 @@ func isReactImport(sourceFile ast.SourceFile, name string) bool {
 
 	return false
 }
+
+// Check if the prop name is that of an event handler, that is,
+// it is of the form `on[A-Z]*` like `onClick` or `onChange`.
+func isEventPropName(name string) bool {
+	if len(name) < 3 || !strings.HasPrefix(name, "on") {
+		return false
+	}
+
+	return unicode.IsUpper(rune(name[2]))
+}
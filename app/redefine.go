@@ -10,18 +10,25 @@ that can be found in LICENSE file in the code repository.
 
 */
 
+//go:generate go run . types ../client/src/@types/components.d.ts
+
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
+	ast "sangupta.com/redefine/ast"
 	core "sangupta.com/redefine/core"
 )
 
@@ -32,38 +39,92 @@ func main() {
 	}
 
 	// parse OS arguments to fetch action and path
-	app := parseOsArguments()
+	app, err := parseOsArguments()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr)
+		printHelp()
+		os.Exit(2)
+	}
+
+	logger := app.Logger
+
+	// the declarations do not depend on the project, and are
+	// written to the given file instead of a folder
+	if app.IsTypesMode() {
+		err := os.WriteFile(app.BaseFolder, []byte(core.GetTypeDeclarations()), 0644)
+		if err != nil {
+			logger.Error("Unable to write Typescript declarations", "error", err)
+			os.Exit(1)
+		}
+
+		logger.Info("Typescript declarations written", "path", app.BaseFolder)
+		return
+	}
+
+	// scaffold the configuration and docs of a project, which
+	// may not have any configuration yet
+	if app.IsInitMode() {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		diagnostics, err := app.InitProject(ctx)
+		stop()
+
+		core.LogDiagnostics(logger, diagnostics)
+		if err != nil {
+			logger.Error("Unable to initialize the project", "error", err)
+			os.Exit(1)
+		}
+
+		return
+	}
 
 	// read configuration, measuring overall time
 	start := time.Now()
-	config := core.GetRedefineConfig(app.BaseFolder)
+	config, configDiagnostics := core.GetRedefineConfig(core.ConfigOptions{
+		BaseFolder:  app.BaseFolder,
+		ConfigFile:  app.ConfigFile,
+		Environment: os.Environ(),
+		Overrides:   app.Overrides,
+		Logger:      logger,
+		Package:     app.Package,
+	})
+
+	// print all problems in the configuration, failing if there are any
+	if app.IsValidateMode() {
+		validateConfig(configDiagnostics)
+		return
+	}
 
 	// `nil` config comes in case when we have an error
-	// the error was written to console
+	// which is reported along with all other problems
+	core.LogDiagnostics(logger, configDiagnostics)
 	if config == nil {
-		return
+		os.Exit(1)
 	}
 
 	// setup config
 	app.Config = config
 
-	// print all configuration
-	config.PrintInfo()
+	// log all configuration
+	config.LogInfo()
 
-	// run extraction
-	jsonBytes, err := app.ExtractAndWriteComponents()
+	// run extraction, stopping early on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	jsonBytes, diagnostics, err := app.ExtractAndWriteComponents(ctx)
+	stop()
 	duration := time.Since(start)
 
+	// report all problems found in source files
+	core.LogDiagnostics(logger, diagnostics)
+
 	// error?
 	if err != nil {
-		fmt.Println("Ran into issues when extracting components")
-		log.Fatal(err)
-		return
+		logger.Error("Ran into issues when extracting components", "error", err)
+		os.Exit(1)
 	}
 
 	// emit time taken in generation
-	fmt.Println("Done in " + duration.String())
-	fmt.Println()
+	logger.Info("Done", "duration", duration)
 
 	// if there was nothing produced, exit quietly
 	if jsonBytes == nil {
@@ -72,7 +133,7 @@ func main() {
 
 	// if we are in serve mode, start HTTP server
 	if app.IsServeMode() {
-		serveBuildOverHttp(jsonBytes, config)
+		serveBuildOverHttp(jsonBytes, config, logger)
 		return
 	}
 
@@ -82,6 +143,21 @@ func main() {
 	}
 }
 
+// Print the problems found in the configuration, and exit with
+// a non-zero status if there are any.
+func validateConfig(diagnostics []ast.Diagnostic) {
+	if len(diagnostics) == 0 {
+		fmt.Println("Configuration is valid")
+		return
+	}
+
+	for _, diagnostic := range diagnostics {
+		fmt.Println(diagnostic.String())
+	}
+
+	os.Exit(1)
+}
+
 // Publish a static application that can be deployed that
 // contains everything this application will need. All static
 // files, including components.json, are emitted to disk and
@@ -93,7 +169,7 @@ func publishApplication(jsonBytes []byte, config *core.RedefineConfig) {
 // This method serves the generated components.json over
 // HTTP. Optionally, any built files that are defined
 // in package.json (including any folder) are also served
-func serveBuildOverHttp(jsonBytes []byte, config *core.RedefineConfig) {
+func serveBuildOverHttp(jsonBytes []byte, config *core.RedefineConfig, logger *slog.Logger) {
 	scanFolders := mapset.NewSet[string]()
 
 	for _, css := range config.Build.CssFiles {
@@ -104,13 +180,14 @@ func serveBuildOverHttp(jsonBytes []byte, config *core.RedefineConfig) {
 	}
 
 	http.HandleFunc("/", func(writer http.ResponseWriter, request *http.Request) {
-		doHttpRequest(writer, request, jsonBytes, config, scanFolders)
+		doHttpRequest(writer, request, jsonBytes, config, scanFolders, logger)
 	})
 
-	fmt.Println("Starting HTTP server on http://localhost:1309 ...")
+	logger.Info("Starting HTTP server", "url", "http://localhost:1309")
 	err := http.ListenAndServe(":1309", nil)
 	if err != nil {
-		log.Fatal(err)
+		logger.Error("Unable to run HTTP server", "error", err)
+		os.Exit(1)
 	}
 }
 
@@ -136,6 +213,10 @@ func sendFile(uri string, writer http.ResponseWriter, bytes []byte) {
 		writer.Header().Add("Content-Type", "text/css")
 	}
 
+	if strings.HasSuffix(uri, ".d.ts") {
+		writer.Header().Add("Content-Type", "text/plain; charset=utf-8")
+	}
+
 	if strings.HasSuffix(uri, ".js.map") || strings.HasSuffix(uri, ".css.map") || strings.HasSuffix(uri, ".json") {
 		writer.Header().Add("Content-Type", "application/json")
 	}
@@ -148,19 +229,33 @@ func sendFile(uri string, writer http.ResponseWriter, bytes []byte) {
 }
 
 // use basic http handler to serve all files
-func doHttpRequest(writer http.ResponseWriter, request *http.Request, jsonBytes []byte, config *core.RedefineConfig, scanFolders mapset.Set[string]) {
+func doHttpRequest(writer http.ResponseWriter, request *http.Request, jsonBytes []byte, config *core.RedefineConfig, scanFolders mapset.Set[string], logger *slog.Logger) {
 	uriPath := request.URL.Path
 
 	if uriPath == "/" {
 		uriPath = "/index.html"
 	}
 
-	fmt.Println("Serving request: " + uriPath)
+	logger.Debug("Serving request", "path", uriPath)
 	if uriPath == "/components.json" {
 		sendFile(uriPath, writer, jsonBytes)
 		return
 	}
 
+	// the schemas and declarations are served next to the components
+	// JSON, where its `$schema` reference points to
+	formatFile, err := core.GetFormatFile(uriPath[1:])
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		writer.Write([]byte(err.Error()))
+		return
+	}
+
+	if formatFile != nil {
+		sendFile(uriPath, writer, formatFile)
+		return
+	}
+
 	// if the request was not served, find the file as was
 	// created in the dist folder for the library
 	uriNoSlash := uriPath[1:]
@@ -194,50 +289,202 @@ func doHttpRequest(writer http.ResponseWriter, request *http.Request, jsonBytes
 	writer.Write([]byte("Not found"))
 }
 
-func parseOsArguments() *core.RedefineApp {
+// The actions that may be given before the folder.
+var actions = []string{"serve", "build", "validate", "init", "types", "publish"}
+
+// The flags that are set by being given, like `--strict`.
+var switchFlags = []string{"strict", "quiet", "verbose"}
+
+// The flags that take a value, like `--log-format json`.
+var valueFlags = []string{"log-format", "config", "set", "package"}
+
+// Parse the OS arguments into the app to run, along with the logger
+// selected by the flags. Returns an error for invalid arguments.
+func parseOsArguments() (*core.RedefineApp, error) {
+	return parseArguments(os.Args)
+}
+
+// Parse the arguments, starting with the program name, into the app
+// to run. Returns an error for unknown flags and actions, and for
+// missing values.
+func parseArguments(osArgs []string) (*core.RedefineApp, error) {
 	var baseFolder string
 
+	// separate flags like `--strict` from the positional arguments,
+	// reading the values of flags like `--log-format json`, of which
+	// `--set` may be given many times
+	args := []string{osArgs[0]}
+	flags := mapset.NewSet[string]()
+	values := map[string][]string{}
+	for index := 1; index < len(osArgs); index++ {
+		arg := osArgs[index]
+		if !strings.HasPrefix(arg, "--") {
+			args = append(args, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg[2:], "=")
+		if slices.Contains(switchFlags, name) && !hasValue {
+			flags.Add(name)
+			continue
+		}
+
+		if !slices.Contains(valueFlags, name) {
+			if slices.Contains(switchFlags, name) {
+				return nil, errors.New("--" + name + " does not take a value")
+			}
+
+			message := "unknown flag --" + name
+			if suggestion := core.SuggestName(append(switchFlags, valueFlags...), name); suggestion != "" {
+				message += ", did you mean --" + suggestion + "?"
+			}
+
+			return nil, errors.New(message)
+		}
+
+		if !hasValue {
+			if index+1 == len(osArgs) {
+				return nil, errors.New("missing value for --" + name)
+			}
+
+			index++
+			value = osArgs[index]
+		}
+
+		values[name] = append(values[name], value)
+	}
+
+	logFormat := core.LOG_FORMAT_TEXT
+	if formats := values["log-format"]; len(formats) > 0 {
+		logFormat = formats[len(formats)-1]
+	}
+
+	configFile := ""
+	if configFiles := values["config"]; len(configFiles) > 0 {
+		configFile = configFiles[len(configFiles)-1]
+	}
+
+	packageName := ""
+	if packages := values["package"]; len(packages) > 0 {
+		packageName = packages[len(packages)-1]
+	}
+
+	// create the logger, writing to standard error so that only
+	// what redefine produces is written to standard output
+	level := slog.LevelInfo
+	if flags.Contains("quiet") {
+		level = slog.LevelError
+	} else if flags.Contains("verbose") {
+		level = slog.LevelDebug
+	}
+
+	logger, err := core.NewLogger(os.Stderr, level, logFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single argument is the folder, unless it is an action, like
+	// `redefine init`, which is run on the current directory
+	if len(args) == 2 && isAction(args[1]) {
+		if strings.EqualFold(args[1], "types") {
+			return nil, errors.New("missing the file to write the Typescript declarations to")
+		}
+
+		args = append(args, "")
+	}
+
 	// check for os arguments
-	numArgs := len(os.Args)
+	numArgs := len(args)
 	runMode := "serve"
 	switch numArgs {
 	case 1:
-		cwd, err := os.Getwd()
-		if err != nil {
-			fmt.Println("No path was specified and error reading current directory")
-		}
-
-		baseFolder = cwd
+		baseFolder = ""
 
 	case 2:
-		baseFolder = os.Args[1]
+		baseFolder = args[1]
 
 	case 3:
-		runMode = os.Args[1]
-		baseFolder = os.Args[2]
+		runMode = args[1]
+		baseFolder = args[2]
+
+		if !isAction(runMode) {
+			message := "unknown action " + runMode
+			if suggestion := core.SuggestName(actions, runMode); suggestion != "" {
+				message += ", did you mean " + suggestion + "?"
+			}
+
+			return nil, errors.New(message)
+		}
+
+		// publishing a static site is planned, but not done yet
+		if strings.EqualFold(runMode, "publish") {
+			return nil, errors.New("the publish action is not implemented yet")
+		}
 
 	default:
-		return nil
+		return nil, errors.New("too many arguments")
+	}
+
+	if baseFolder == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, errors.New("no path was specified and error reading current directory")
+		}
+
+		baseFolder = cwd
 	}
 
 	app := core.RedefineApp{
 		RunMode:    runMode,
 		BaseFolder: baseFolder,
+		Strict:     flags.Contains("strict"),
+		Logger:     logger,
+		ConfigFile: configFile,
+		Overrides:  values["set"],
+		Package:    packageName,
 	}
 
-	return &app
+	return &app, nil
+}
+
+// Check if the argument is one of the actions, ignoring case.
+func isAction(arg string) bool {
+	for _, action := range actions {
+		if strings.EqualFold(action, arg) {
+			return true
+		}
+	}
+
+	return false
 }
 
 func printHelp() {
 	fmt.Println("Redefine: UI component documentation")
-	fmt.Println("usage: $ redefine <action> <folder>")
+	fmt.Println("usage: $ redefine [<action>] [<folder>] [--config <file>] [--set <key>=<value>]... [--strict]")
+	fmt.Println("                                        [--package <name>] [--quiet | --verbose] [--log-format text|json]")
 	fmt.Println()
 	fmt.Println("    <action>  (optional) specify non-default actions:")
 	fmt.Println("              `serve`: run local server to serve documentation")
 	fmt.Println("              `build`: export all doc files to an output folder")
+	fmt.Println("              `validate`: check the configuration and print all problems")
+	fmt.Println("              `init`: write a configuration and docs to start from")
+	fmt.Println("              `types`: write Typescript declarations of components.json")
+	fmt.Println("              to the file given instead of the folder")
+	fmt.Println("              `publish`: write a static site that can be deployed,")
+	fmt.Println("              not implemented yet")
 	fmt.Println()
 	fmt.Println("    <folder>  Root folder where either `package.json` or")
-	fmt.Println("              `redefine.config.json` exists.")
+	fmt.Println("              `redefine.config.json` exists. Defaults to the")
+	fmt.Println("              current directory.")
+	fmt.Println()
+	fmt.Println("    --config      read the configuration from this file")
+	fmt.Println("    --set         set a configuration value, such as `src.root=lib`,")
+	fmt.Println("                  over those in files and REDEFINE_* variables")
+	fmt.Println("    --package     only document this package of a workspace, by name or folder")
+	fmt.Println("    --strict      fail if any source file has errors")
+	fmt.Println("    --quiet       only log errors")
+	fmt.Println("    --verbose     also log debug details, such as the time of each phase")
+	fmt.Println("    --log-format  write logs as `text`, the default, or `json`")
 	fmt.Println()
 	fmt.Println("Detailed instructions at https://redefine.sangupta.com")
 	fmt.Println()
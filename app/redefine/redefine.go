@@ -0,0 +1,216 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+// Package redefine extracts the documentation of UI components from
+// source files, for use by Go tools that embed redefine.
+//
+// Each `Extractor` holds its own parser and syntax kinds, and nothing
+// is shared between extractors, so that several extractions can run
+// concurrently by using one extractor for each.
+package redefine
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"sangupta.com/redefine/ast"
+	"sangupta.com/redefine/model"
+)
+
+// The options to create an extractor with.
+type ExtractorOptions struct {
+	Parser      string // the parser to use, `typescript` or `go`, defaults to `typescript`
+	Target      string // the ECMAScript target such as `ES2020`, defaults to the latest
+	TypeChecker bool   // resolve the props types using the Typescript type checker, needs `Resolve`
+
+	// the `typescript.js` file of the Typescript compiler used by the
+	// typescript parser, defaults to `$REDEFINE_TYPESCRIPT` or else
+	// the one in `node_modules` of the working folder
+	TypescriptFile string
+
+	// limits of the typescript parser, the defaults apply when zero
+	Timeout     time.Duration // the longest time to parse a single file, or run the type checker
+	MemoryLimit uint32        // the most memory the QuickJS runtime may use, in megabytes
+	StackLimit  uint32        // the largest stack the QuickJS runtime may use, in kilobytes
+
+	Logger *slog.Logger // the logger to report progress to, nothing is logged when `nil`
+}
+
+// The options for a single extraction.
+type Options struct {
+	Files               []string           // the absolute paths of the files to extract components from
+	Resolve             ast.ModuleResolver // resolves imports to files, when `nil` imported files are not parsed
+	ExpandDomAttributes bool               // list inherited DOM attributes one by one, instead of a single entry
+}
+
+// The result of an extraction.
+type Result struct {
+	Components  []model.Component // the components found in the files
+	Diagnostics []ast.Diagnostic  // the problems found in the files
+}
+
+// Returned by `Extract` once the extractor has been closed.
+var ErrClosed = errors.New("redefine: extractor is closed")
+
+// An extraction waiting to be run by the extractor.
+type request struct {
+	ctx     context.Context
+	options Options
+	result  chan *Result
+}
+
+// Extracts components using its own parser. The parser runs on a
+// goroutine of its own that is locked to an OS thread, as needed by
+// the Typescript parser. An extractor is safe for concurrent use, but
+// runs one extraction at a time.
+type Extractor struct {
+	options   ExtractorOptions
+	syntax    *ast.SyntaxKind
+	requests  chan request
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Create a new extractor, along with its parser. Returns an error if
+// the parser cannot be created, such as when the Typescript compiler
+// cannot be loaded. The extractor must be closed once done to free
+// the parser.
+func NewExtractor(options ExtractorOptions) (*Extractor, error) {
+	extractor := &Extractor{
+		options:  options,
+		requests: make(chan request),
+		done:     make(chan struct{}),
+	}
+
+	ready := make(chan error)
+	go extractor.run(ready)
+
+	if err := <-ready; err != nil {
+		return nil, err
+	}
+
+	return extractor, nil
+}
+
+// Return the syntax kinds of the parser used by the extractor.
+func (extractor *Extractor) GetSyntaxKind() *ast.SyntaxKind {
+	return extractor.syntax
+}
+
+// Extract the components from the files in the options. Returns the
+// error of the context if it is done before the extraction completes,
+// in which case parsing stops too, or `ErrClosed` if the extractor has
+// been closed.
+func (extractor *Extractor) Extract(ctx context.Context, options Options) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// buffered, so that the worker does not block if the caller
+	// has stopped waiting
+	req := request{
+		ctx:     ctx,
+		options: options,
+		result:  make(chan *Result, 1),
+	}
+
+	select {
+	case extractor.requests <- req:
+	case <-extractor.done:
+		return nil, ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case result := <-req.result:
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Stop the extractor and free its parser. Extractions running when
+// the extractor is closed are completed first.
+func (extractor *Extractor) Close() {
+	extractor.closeOnce.Do(func() {
+		close(extractor.done)
+	})
+}
+
+// Create the parser and serve extraction requests till the extractor
+// is closed. The parser stays on the OS thread of this goroutine.
+func (extractor *Extractor) run(ready chan<- error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	parser, err := ast.NewParser(&ast.ParseOptions{
+		Parser:      extractor.options.Parser,
+		Target:      extractor.options.Target,
+		TypeChecker: extractor.options.TypeChecker,
+		Timeout:     extractor.options.Timeout,
+		MemoryLimit: extractor.options.MemoryLimit,
+		StackLimit:  extractor.options.StackLimit,
+		Logger:      extractor.options.Logger,
+
+		TypescriptFile: extractor.options.TypescriptFile,
+	})
+	if err != nil {
+		ready <- err
+		return
+	}
+	defer parser.Free()
+
+	extractor.syntax = parser.GetSyntaxKind()
+	ready <- nil
+
+	for {
+		select {
+		case req := <-extractor.requests:
+			req.result <- extractor.extract(req.ctx, parser, req.options)
+
+		case <-extractor.done:
+			return
+		}
+	}
+}
+
+// Parse the files with the parser and extract the components.
+func (extractor *Extractor) extract(ctx context.Context, parser ast.Parser, options Options) *Result {
+	var astMap map[string]ast.SourceFile
+	var modules *ast.Modules
+	var diagnostics []ast.Diagnostic
+
+	if options.Resolve == nil {
+		astMap, diagnostics = ast.ParseFiles(ctx, parser, options.Files)
+	} else {
+		parseOptions := &ast.ParseOptions{
+			TypeChecker: extractor.options.TypeChecker,
+			Logger:      extractor.options.Logger,
+		}
+		astMap, modules, diagnostics = ast.ParseFilesAndImports(ctx, parser, options.Files, parseOptions, options.Resolve)
+	}
+
+	extractOptions := &model.ExtractOptions{
+		ExpandDomAttributes: options.ExpandDomAttributes,
+		Logger:              extractor.options.Logger,
+	}
+
+	return &Result{
+		Components:  model.GetComponents(astMap, extractor.syntax, modules, extractOptions),
+		Diagnostics: diagnostics,
+	}
+}
@@ -0,0 +1,134 @@
+/*
+
+Redefine - UI component documentation
+
+MIT License.
+Copyright (c) 2022, Sandeep Gupta.
+
+Use of this source code is governed by a MIT style license
+that can be found in LICENSE file in the code repository.
+
+*/
+
+package redefine
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write the components to files in a new folder, and return
+// their paths.
+func writeComponentFiles(t *testing.T, names ...string) []string {
+	folder := t.TempDir()
+	files := make([]string, 0, len(names))
+
+	for _, name := range names {
+		file := filepath.Join(folder, name+".tsx")
+		code := "export function " + name + "(props: { label: string }) {\n\treturn <div>{props.label}</div>\n}\n"
+		assert.Nil(t, os.WriteFile(file, []byte(code), 0644))
+		files = append(files, file)
+	}
+
+	return files
+}
+
+func TestNewExtractorFailure(t *testing.T) {
+	extractor, err := NewExtractor(ExtractorOptions{Parser: "unknown"})
+	assert.Nil(t, extractor)
+	assert.EqualError(t, err, "unknown parser: unknown")
+
+	// the Typescript compiler cannot be loaded
+	extractor, err = NewExtractor(ExtractorOptions{
+		Parser:         "typescript",
+		TypescriptFile: filepath.Join(t.TempDir(), "typescript.js"),
+	})
+	assert.Nil(t, extractor)
+	assert.NotNil(t, err)
+}
+
+func TestConcurrentExtract(t *testing.T) {
+	extractor, err := NewExtractor(ExtractorOptions{Parser: "go"})
+	assert.Nil(t, err)
+	defer extractor.Close()
+
+	files := writeComponentFiles(t, "Button", "Menu")
+
+	// extractions are run one at a time, each with its own result
+	var wait sync.WaitGroup
+	results := make([]*Result, 8)
+	errs := make([]error, len(results))
+	for index := range results {
+		wait.Add(1)
+		go func(index int) {
+			defer wait.Done()
+			results[index], errs[index] = extractor.Extract(context.Background(), Options{Files: files[index%2 : index%2+1]})
+		}(index)
+	}
+	wait.Wait()
+
+	for index, result := range results {
+		assert.Nil(t, errs[index])
+		assert.Equal(t, 1, len(result.Components))
+		assert.Equal(t, []string{"Button", "Menu"}[index%2], result.Components[0].Name)
+		assert.Equal(t, 0, len(result.Diagnostics))
+	}
+
+	// several extractors run concurrently
+	names := make([]string, 2)
+	for index := range names {
+		wait.Add(1)
+		go func(index int) {
+			defer wait.Done()
+			other, err := NewExtractor(ExtractorOptions{Parser: "go"})
+			assert.Nil(t, err)
+			defer other.Close()
+
+			result, err := other.Extract(context.Background(), Options{Files: files[index : index+1]})
+			assert.Nil(t, err)
+			names[index] = result.Components[0].Name
+		}(index)
+	}
+	wait.Wait()
+
+	sort.Strings(names)
+	assert.Equal(t, []string{"Button", "Menu"}, names)
+}
+
+func TestExtractCancelled(t *testing.T) {
+	extractor, err := NewExtractor(ExtractorOptions{Parser: "go"})
+	assert.Nil(t, err)
+	defer extractor.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := extractor.Extract(ctx, Options{Files: writeComponentFiles(t, "Button")})
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClose(t *testing.T) {
+	extractor, err := NewExtractor(ExtractorOptions{Parser: "go"})
+	assert.Nil(t, err)
+	assert.NotNil(t, extractor.GetSyntaxKind())
+
+	files := writeComponentFiles(t, "Button")
+	result, err := extractor.Extract(context.Background(), Options{Files: files})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result.Components))
+
+	// closing more than once is allowed, and extractions fail after
+	extractor.Close()
+	extractor.Close()
+
+	result, err = extractor.Extract(context.Background(), Options{Files: files})
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,104 @@
+/**
+ * Generated by `redefine types` from the Go model of `components.json`,
+ * schema version 2. Do not edit.
+ */
+
+interface RedefinePayload {
+    $schema: string;
+    schemaVersion: number;
+    title: string;
+    favicon: string;
+    description: string;
+    libDocs: string;
+    version: string;
+    name: string;
+    homePage: string;
+    repository: string;
+    bugs: string;
+    keywords: Array<string> | null;
+    author: Author;
+    license: string;
+    peerDependencies: Record<string, string> | null;
+    components: Array<ComponentDef> | null;
+    customCSS: string;
+    library: string;
+    fonts: Array<string> | null;
+    js: Array<string> | null;
+    packages: Array<PackageDef> | null;
+}
+
+interface Author {
+    name: string;
+    email: string;
+    url: string;
+}
+
+interface ComponentDef {
+    name: string;
+    id: string;
+    sourcePath: string;
+    package: string;
+    componentType: "class" | "function";
+    description: string;
+    props: Array<PropDef> | null;
+    events: Array<PropDef> | null;
+    docs: string;
+    docFileName: string;
+    subComponents: Array<SubComponentDef> | null;
+    parent: string;
+    parentId: string;
+    typeParameters: Array<TypeParamDef> | null;
+    sourceFile: string;
+    line: number;
+    endLine: number;
+    sourceUrl: string;
+}
+
+interface PackageDef {
+    name: string;
+    folder: string;
+    version: string;
+    description: string;
+    libDocs: string;
+    library: string;
+    peerDependencies: Record<string, string> | null;
+}
+
+interface PropDef {
+    name: string;
+    type: string;
+    enumOf: Array<ParamDef> | null;
+    required: boolean;
+    defaultValue: string;
+    description: string;
+    returnType: string;
+    params: Array<ParamDef> | null;
+    isEvent: boolean;
+    isGeneric: boolean;
+    shape: Array<PropDef> | null;
+    sourceFile: string;
+    line: number;
+    endLine: number;
+    sourceUrl: string;
+    inheritedFrom: string;
+    collapsed: boolean;
+}
+
+interface SubComponentDef {
+    name: string;
+    component: string;
+    componentId: string;
+}
+
+interface TypeParamDef {
+    name: string;
+    constraint: string;
+    default: string;
+}
+
+interface ParamDef {
+    name: string;
+    type: string;
+    optional: boolean;
+    rest: boolean;
+}
@@ -69,7 +69,7 @@ class App extends React.Component<NoProps, AppState> {
             const { components } = data;
 
             // retain all metadata except components
-            delete data['components'];
+            data.components = null;
 
             // set the window title to the one given in JSON
             if (data.title) {
@@ -109,7 +109,10 @@ class App extends React.Component<NoProps, AppState> {
                 });
             }
 
-            if (data.library && win.__loadComponentLibrary) {
+            // the library, along with those of the packages
+            // of a workspace, whose components are merged
+            const libraries = [data.library, ...(data.packages || []).map(pkg => pkg.library)].filter(library => !!library);
+            if (libraries.length > 0 && win.__loadComponentLibrary) {
                 try {
                     for (let index = 0; index < 5; index++) {
                         if (win.__isReady) {
@@ -119,9 +122,11 @@ class App extends React.Component<NoProps, AppState> {
                         await sleep(250);
                     }
 
-                    const libraryComponents = await win.__loadComponentLibrary('http://localhost:1309/' + data.library);
-                    if (libraryComponents) {
-                        win.__ComponentLibrary = libraryComponents;
+                    for (const library of libraries) {
+                        const libraryComponents = await win.__loadComponentLibrary('http://localhost:1309/' + library);
+                        if (libraryComponents) {
+                            win.__ComponentLibrary = { ...(win.__ComponentLibrary || {}), ...libraryComponents };
+                        }
                     }
                 } catch (e) {
                     console.error('error loading component library', e);
@@ -25,7 +25,7 @@ export function componentSorter(a: ComponentDef, b: ComponentDef) {
         return 0;
     }
 
-    return a.name.localeCompare(b.name);
+    return a.name.localeCompare(b.name) || (a.id || '').localeCompare(b.id || '');
 }
 
 /**
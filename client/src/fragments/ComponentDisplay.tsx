@@ -97,8 +97,11 @@ export default class ComponentDisplay extends React.Component<ComponentDisplayPr
                 {component.sourcePath}
                 <span style={{ paddingLeft: '12px' }}><CopyIcon onClick={this.handleCopy} /></span>
             </ComponentSourceFile>
+            {component.package && <ComponentSourceFile as='div'>
+                {`import { ${component.name} } from '${component.package}';`}
+            </ComponentSourceFile>}
 
-            <TabContainer key={component.name + '-' + example?.name} tabs={tabs} selectedTab={example ? exampleTab - 1 : 0} />
+            <TabContainer key={(component.id || component.name) + '-' + example?.name} tabs={tabs} selectedTab={example ? exampleTab - 1 : 0} />
 
             <br /><br /><br />
         </DetailsContainer>
@@ -46,7 +46,7 @@ export default class ContentPane extends React.Component<ContentPaneProps> {
             }
             
             if(meta.license) {
-                kids += 'License: ' + meta.version;
+                kids += 'License: ' + meta.license;
                 kids += '\n\n';
             }
 
@@ -55,9 +55,44 @@ export default class ContentPane extends React.Component<ContentPaneProps> {
                 kids += '\n\n';
             }
 
+            if(meta.repository) {
+                kids += 'Repository: ' + `[${meta.repository}](${meta.repository})`
+                kids += '\n\n';
+            }
+
+            if(meta.bugs) {
+                kids += 'Issues: ' + `[${meta.bugs}](${meta.bugs})`
+                kids += '\n\n';
+            }
+
             kids += (meta.description || '');
             kids += '\n\n';
 
+            if(meta.name) {
+                kids += '```sh\nnpm install ' + meta.name + '\n```';
+                kids += '\n\n';
+            }
+
+            const peers = Object.keys(meta.peerDependencies || {});
+            if(peers.length > 0) {
+                kids += 'Requires: ' + peers.map(peer => '`' + peer + ' ' + meta.peerDependencies[peer] + '`').join(', ');
+                kids += '\n\n';
+            }
+
+            if(meta.keywords && meta.keywords.length > 0) {
+                kids += 'Keywords: ' + meta.keywords.join(', ');
+                kids += '\n\n';
+            }
+
+            if(meta.packages && meta.packages.length > 0) {
+                kids += '| Package | Version | Description |\n';
+                kids += '| --- | --- | --- |\n';
+                meta.packages.forEach(pkg => {
+                    kids += `| \`${pkg.name}\` | ${pkg.version} | ${pkg.description} |\n`;
+                });
+                kids += '\n\n';
+            }
+
             if (meta.author && meta.author.name) {
                 let author = meta.author.name;
                 if(meta.author.url) {
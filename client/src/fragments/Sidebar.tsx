@@ -72,9 +72,24 @@ export default class Sidebar extends React.Component<SidebarProps, SidebarState>
 
         filtered.sort(componentSorter);
 
+        // components of a workspace are grouped by their package
+        const packages = Array.from(new Set(filtered.map(component => component.package || ''))).sort();
+        if (packages.length === 1 && packages[0] === '') {
+            return <ComponentContainer>
+                {filtered.map(component => {
+                    return <ComponentItem key={component.id || component.name} component={component} onSelect={this.handleComponentSelect} />
+                })}
+            </ComponentContainer>
+        }
+
         return <ComponentContainer>
-            {filtered.map(component => {
-                return <ComponentItem key={component.name} component={component} onSelect={this.handleComponentSelect} />
+            {packages.map(pkg => {
+                return <React.Fragment key={pkg}>
+                    <PackageName>{pkg}</PackageName>
+                    {filtered.filter(component => (component.package || '') === pkg).map(component => {
+                        return <ComponentItem key={component.id || component.name} component={component} onSelect={this.handleComponentSelect} />
+                    })}
+                </React.Fragment>
             })}
         </ComponentContainer>
     }
@@ -124,6 +139,13 @@ const ComponentContainer = styled.div`
     line-height: 22px;
 `;
 
+const PackageName = styled.div`
+    padding: 8px;
+    padding-top: 16px;
+    font-weight: bold;
+    color: rgb(118, 118, 118);
+`;
+
 const SearchContainer = styled.div`
     color: #212529;
     padding: .5rem;